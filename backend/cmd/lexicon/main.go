@@ -0,0 +1,189 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"tamil-proofreading-platform/backend/internal/config"
+	"tamil-proofreading-platform/backend/internal/services/hunspell"
+	"tamil-proofreading-platform/backend/internal/services/lexicon"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+const usage = `Usage:
+  lexicon import [-format json|csv|tsv|hunspell] [-source tag] [-verified] [-dry-run] [-v] <file>
+  lexicon export [-format json|csv|tsv|hunspell] [-verified] [-source tag] [-category name] [-out path]
+
+Import upserts tamil_words by transliteration. Export writes to stdout unless
+-out is given; hunspell export requires -out and writes <out>.dic and <out>.aff.
+`
+
+func main() {
+	log.SetFlags(0)
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	switch os.Args[1] {
+	case "import":
+		runImport(os.Args[2:])
+	case "export":
+		runExport(os.Args[2:])
+	default:
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+}
+
+func openDB() *gorm.DB {
+	cfg := config.Load()
+	db, err := gorm.Open(postgres.Open(cfg.DatabaseURL), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	return db
+}
+
+func runImport(args []string) {
+	fs := flag.NewFlagSet("import", flag.ExitOnError)
+	formatName := fs.String("format", "", "input format (default: from file extension)")
+	source := fs.String("source", "", "source tag for imported rows (default: import:<file name>)")
+	verified := fs.Bool("verified", false, "mark imported rows as verified")
+	dryRun := fs.Bool("dry-run", false, "print the diff without writing")
+	verbose := fs.Bool("v", false, "also list unchanged entries")
+	fs.Parse(args)
+
+	if fs.NArg() != 1 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+	path := fs.Arg(0)
+
+	format, err := lexicon.ParseFormat(*formatName, path)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		log.Fatalf("Failed to open %s: %v", path, err)
+	}
+	entries, err := lexicon.Read(format, file)
+	file.Close()
+	if err != nil {
+		log.Fatalf("Failed to read %s: %v", path, err)
+	}
+
+	tag := *source
+	if tag == "" {
+		tag = "import:" + filepath.Base(path)
+	}
+
+	importer := lexicon.NewImporter(openDB())
+	changes, err := importer.Plan(entries, lexicon.Options{Source: tag, MarkVerified: *verified})
+	if err != nil {
+		log.Fatalf("Failed to plan import: %v", err)
+	}
+
+	for _, c := range changes {
+		if c.Action == lexicon.ActionUnchanged && !*verbose {
+			continue
+		}
+		fmt.Println(c.String())
+	}
+
+	summary := lexicon.Summarize(changes)
+	log.Printf("Read %d entries: %d create, %d update, %d merge, %d unchanged, %d skipped",
+		len(entries), summary[lexicon.ActionCreate], summary[lexicon.ActionUpdate],
+		summary[lexicon.ActionMerge], summary[lexicon.ActionUnchanged], summary[lexicon.ActionSkip])
+
+	if *dryRun {
+		log.Printf("Dry run: no changes written")
+		return
+	}
+	if err := importer.Apply(changes); err != nil {
+		log.Fatalf("Import failed, nothing written: %v", err)
+	}
+	log.Printf("Import complete (source=%s)", tag)
+}
+
+func runExport(args []string) {
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	formatName := fs.String("format", "", "output format (default: from -out extension, else json)")
+	out := fs.String("out", "", "output path (default: stdout)")
+	verifiedOnly := fs.Bool("verified", false, "export verified words only")
+	source := fs.String("source", "", "export words with this source tag only")
+	category := fs.String("category", "", "export words in this category only")
+	fs.Parse(args)
+
+	name := *formatName
+	if name == "" && *out == "" {
+		name = "json"
+	}
+	format, err := lexicon.ParseFormat(name, *out)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	entries, err := lexicon.Export(openDB(), lexicon.Filter{
+		VerifiedOnly: *verifiedOnly,
+		Source:       *source,
+		Category:     *category,
+	})
+	if err != nil {
+		log.Fatalf("Failed to load words: %v", err)
+	}
+
+	if format == lexicon.FormatHunspell {
+		if *out == "" {
+			log.Fatal("hunspell export requires -out")
+		}
+		base := strings.TrimSuffix(*out, filepath.Ext(*out))
+		if err := writeHunspellFiles(base, entries); err != nil {
+			log.Fatal(err)
+		}
+		log.Printf("Exported %d words to %s.dic and %s.aff", len(entries), base, base)
+		return
+	}
+
+	var w io.Writer = os.Stdout
+	if *out != "" {
+		file, err := os.Create(*out)
+		if err != nil {
+			log.Fatalf("Failed to create %s: %v", *out, err)
+		}
+		defer file.Close()
+		w = file
+	}
+	if err := lexicon.Write(format, w, entries); err != nil {
+		log.Fatalf("Export failed: %v", err)
+	}
+	log.Printf("Exported %d words", len(entries))
+}
+
+func writeHunspellFiles(base string, entries []lexicon.Entry) error {
+	dic, err := os.Create(base + ".dic")
+	if err != nil {
+		return err
+	}
+	defer dic.Close()
+
+	aff, err := os.Create(base + ".aff")
+	if err != nil {
+		return err
+	}
+	defer aff.Close()
+
+	return lexicon.WriteHunspell(dic, aff, entries, hunspell.AffixRules, hunspell.FlagsFor)
+}
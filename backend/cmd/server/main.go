@@ -13,7 +13,12 @@ import (
         "tamil-proofreading-platform/backend/internal/handlers"
         "tamil-proofreading-platform/backend/internal/middleware"
         "tamil-proofreading-platform/backend/internal/models"
+        "tamil-proofreading-platform/backend/internal/services/codemix"
+        "tamil-proofreading-platform/backend/internal/services/grantha"
+        "tamil-proofreading-platform/backend/internal/services/library"
+        "tamil-proofreading-platform/backend/internal/services/search"
         "tamil-proofreading-platform/backend/internal/translit"
+        "tamil-proofreading-platform/backend/internal/util/htmlsanitize"
 )
 
 func main() {
@@ -104,11 +109,52 @@ func main() {
                                 &models.DailyActivityStats{},
                                 &models.EmailVerification{},
                                 &models.PasswordResetToken{},
+                                &models.UserDictionaryEntry{},
+                                &models.UserIgnoreRule{},
+                                &models.Organization{},
+                                &models.OrganizationMember{},
+                                &models.OrganizationInvite{},
+                                &models.Glossary{},
+                                &models.GlossaryTerm{},
+                                &models.GlossaryVersion{},
+                                &models.StyleProfile{},
+                                &models.GranthaMapping{},
+                                &models.CodeMixMapping{},
+                                &models.SubmissionSuggestion{},
+                                &models.Document{},
+                                &models.DocumentRevision{},
+                                &models.SubmissionFile{},
+                                &models.Batch{},
+                                &models.BatchItem{},
+                                &models.Job{},
+                                &models.WebhookEndpoint{},
+                                &models.WebhookDelivery{},
+                                &models.StreamEvent{},
+                                &models.Folder{},
+                                &models.Tag{},
                         )
                         if err != nil {
                                 log.Printf("[ERROR] Database migration failed: %v", err)
                         } else {
                                 log.Printf("[SUCCESS] Database migrations completed")
+                                if err := grantha.SeedDefaults(db); err != nil {
+                                        log.Printf("[ERROR] Seeding grantha mappings failed: %v", err)
+                                }
+                                if err := codemix.SeedDefaults(db); err != nil {
+                                        log.Printf("[ERROR] Seeding code-mix mappings failed: %v", err)
+                                }
+                                if err := search.Migrate(db); err != nil {
+                                        log.Printf("[ERROR] Creating search indexes failed: %v", err)
+                                }
+                                if err := library.Migrate(db); err != nil {
+                                        log.Printf("[ERROR] Creating folder and tag indexes failed: %v", err)
+                                }
+                                if err := htmlsanitize.Backfill(db,
+                                        htmlsanitize.Target{Table: "submissions", Column: "original_html"},
+                                        htmlsanitize.Target{Table: "document_revisions", Column: "html"},
+                                ); err != nil {
+                                        log.Printf("[ERROR] Sanitizing stored HTML failed: %v", err)
+                                }
                         }
                 }
         }
@@ -136,7 +182,6 @@ func main() {
                 api.POST("/auth/reset-password", h.ResetPassword)
                 api.GET("/autocomplete", h.AutocompleteTamil)
                 api.POST("/transliterate", h.Transliterate)
-                api.POST("/tamil-words", h.AddTamilWord)
                 api.POST("/tamil-words/confirm", h.ConfirmTamilWord)
                 api.POST("/events/visit", h.LogVisit)
                 api.POST("/webhooks/stripe", h.StripeWebhook)
@@ -155,9 +200,58 @@ func main() {
                 protected.GET("/auth/me", h.GetCurrentUser)
                 protected.POST("/submit", h.SubmitText)
                 protected.GET("/submissions", h.GetSubmissions)
+                protected.GET("/submissions/search", h.SearchSubmissions)
+                protected.POST("/submissions/bulk/move", h.BulkMoveSubmissions)
+                protected.POST("/submissions/bulk/tags", h.BulkTagSubmissions)
                 protected.GET("/submissions/:id", h.GetSubmission)
+                protected.PATCH("/submissions/:id", h.UpdateSubmission)
                 protected.DELETE("/submissions/:id", h.ArchiveSubmission)
+                protected.GET("/submissions/:id/suggestions", h.GetSubmissionSuggestions)
+                protected.GET("/submissions/:id/diff", h.GetSubmissionDiff)
+                protected.POST("/submissions/:id/email-report", h.EmailSubmissionReport)
+                protected.GET("/submissions/:id/export", h.ExportSubmission)
+                protected.GET("/submissions/:id/html", h.GetSubmissionHTML)
+                protected.GET("/submissions/:id/subtitle-report", h.GetSubtitleReport)
+                protected.POST("/submissions/:id/cancel", h.CancelSubmission)
+                protected.POST("/submissions/:id/reprocess", h.ReprocessSubmission)
+                protected.POST("/subtitles/check", h.CheckSubtitles)
+                protected.POST("/submit/file", h.SubmitFile)
+                protected.GET("/file-formats", h.GetFileFormats)
+                protected.POST("/submissions/:id/suggestions/accept-all", h.AcceptAllSuggestions)
+                protected.POST("/submissions/:id/suggestions/:suggestion_id/accept", h.AcceptSuggestion)
+                protected.POST("/submissions/:id/suggestions/:suggestion_id/reject", h.RejectSuggestion)
                 protected.GET("/stream/submissions/:id", h.StreamSubmission)
+                protected.POST("/batches", h.CreateBatch)
+                protected.GET("/batches", h.GetBatches)
+                protected.GET("/batches/:id", h.GetBatch)
+                protected.GET("/batches/:id/results", h.GetBatchResults)
+                protected.GET("/stream/batches/:id", h.StreamBatch)
+                protected.GET("/live", h.LiveProofread)
+                protected.GET("/webhook-endpoints", h.GetWebhookEndpoints)
+                protected.POST("/webhook-endpoints", h.CreateWebhookEndpoint)
+                protected.PATCH("/webhook-endpoints/:id", h.UpdateWebhookEndpoint)
+                protected.DELETE("/webhook-endpoints/:id", h.DeleteWebhookEndpoint)
+                protected.POST("/webhook-endpoints/:id/rotate-secret", h.RotateWebhookSecret)
+                protected.GET("/webhook-endpoints/:id/deliveries", h.GetWebhookDeliveries)
+                protected.POST("/webhook-endpoints/:id/deliveries/:delivery_id/replay", h.ReplayWebhookDelivery)
+                protected.GET("/folders", h.GetFolders)
+                protected.POST("/folders", h.CreateFolder)
+                protected.PATCH("/folders/:id", h.RenameFolder)
+                protected.DELETE("/folders/:id", h.DeleteFolder)
+                protected.GET("/tags", h.GetTags)
+                protected.POST("/tags", h.CreateTag)
+                protected.PATCH("/tags/:id", h.UpdateTag)
+                protected.DELETE("/tags/:id", h.DeleteTag)
+                protected.GET("/documents", h.GetDocuments)
+                protected.POST("/documents", h.CreateDocument)
+                protected.GET("/documents/:id", h.GetDocument)
+                protected.PUT("/documents/:id", h.UpdateDocument)
+                protected.DELETE("/documents/:id", h.DeleteDocument)
+                protected.GET("/documents/:id/revisions", h.GetDocumentRevisions)
+                protected.POST("/documents/:id/revisions", h.CreateDocumentRevision)
+                protected.GET("/documents/:id/revisions/:number", h.GetDocumentRevision)
+                protected.POST("/documents/:id/revisions/:number/restore", h.RestoreDocumentRevision)
+                protected.GET("/documents/:id/diff", h.GetDocumentDiff)
                 protected.GET("/archive", h.GetArchivedSubmissions)
                 protected.POST("/contact", h.SubmitContactMessage)
                 protected.POST("/payments/create", h.CreatePayment)
@@ -166,6 +260,51 @@ func main() {
                 protected.GET("/dashboard/stats", h.GetDashboardStats)
                 protected.GET("/usage", h.GetUsage)
                 protected.POST("/events/activity", h.LogActivity)
+                protected.POST("/tamil-words", h.AddTamilWord)
+                protected.GET("/dictionary/hunspell/:file", h.GetHunspellDictionary)
+                protected.GET("/dictionary/words", h.GetDictionaryWords)
+                protected.POST("/dictionary/words", h.AddDictionaryWords)
+                protected.DELETE("/dictionary/words/:id", h.DeleteDictionaryWord)
+                protected.GET("/dictionary/ignore-rules", h.GetIgnoreRules)
+                protected.POST("/dictionary/ignore-rules", h.CreateIgnoreRule)
+                protected.DELETE("/dictionary/ignore-rules/:id", h.DeleteIgnoreRule)
+                protected.POST("/organizations", h.CreateOrganization)
+                protected.GET("/organizations/me", h.GetMyOrganization)
+                protected.POST("/organizations/invites", h.InviteOrganizationMember)
+                protected.DELETE("/organizations/invites/:id", h.RevokeOrganizationInvite)
+                protected.DELETE("/organizations/members/me", h.LeaveOrganization)
+                protected.DELETE("/organizations/members/:user_id", h.RemoveOrganizationMember)
+                protected.GET("/organization-invites", h.GetMyOrganizationInvites)
+                protected.POST("/organization-invites/:id/accept", h.AcceptOrganizationInvite)
+                protected.DELETE("/organization-invites/:id", h.DeclineOrganizationInvite)
+                protected.PUT("/organizations/style-profile", h.SetOrganizationStyleProfile)
+                protected.PUT("/organizations/webhook-events", h.SetOrganizationWebhookEvents)
+                protected.GET("/style-profiles", h.GetStyleProfiles)
+                protected.POST("/style-profiles", h.CreateStyleProfile)
+                protected.PUT("/style-profiles/:key", h.UpdateStyleProfile)
+                protected.DELETE("/style-profiles/:key", h.DeleteStyleProfile)
+                protected.GET("/glossaries", h.GetGlossaries)
+                protected.POST("/glossaries", h.CreateGlossary)
+                protected.GET("/glossaries/:id", h.GetGlossary)
+                protected.PUT("/glossaries/:id", h.UpdateGlossary)
+                protected.DELETE("/glossaries/:id", h.DeleteGlossary)
+                protected.POST("/glossaries/:id/terms", h.AddGlossaryTerm)
+                protected.PUT("/glossaries/:id/terms/:term_id", h.UpdateGlossaryTerm)
+                protected.DELETE("/glossaries/:id/terms/:term_id", h.DeleteGlossaryTerm)
+                protected.POST("/glossaries/:id/import", h.ImportGlossaryCSV)
+                protected.GET("/glossaries/:id/versions", h.GetGlossaryVersions)
+                protected.GET("/glossaries/:id/versions/:version", h.GetGlossaryVersion)
+                protected.POST("/glossaries/:id/versions/:version/restore", h.RestoreGlossaryVersion)
+        }
+
+        // Reviewer routes (word moderation queue)
+        review := protected.Group("/review")
+        review.Use(middleware.ReviewerMiddleware(db))
+        {
+                review.GET("/tamil-words", h.ReviewListTamilWords)
+                review.PUT("/tamil-words/:id", h.ReviewEditTamilWord)
+                review.POST("/tamil-words/:id/approve", h.ReviewApproveTamilWord)
+                review.POST("/tamil-words/:id/reject", h.ReviewRejectTamilWord)
         }
 
         // Admin routes
@@ -178,8 +317,19 @@ func main() {
                 admin.GET("/payments", h.AdminGetPayments)
                 admin.GET("/analytics", h.AdminGetAnalytics)
                 admin.GET("/model-logs", h.AdminGetModelLogs)
+                admin.GET("/jobs", h.AdminGetJobs)
+                admin.POST("/jobs/:id/retry", h.AdminRetryJob)
+                admin.GET("/queue/stats", h.AdminGetQueueStats)
                 admin.GET("/contact", h.AdminListContactMessages)
                 admin.GET("/analytics-dashboard", h.GetAnalyticsDashboard)
+                admin.GET("/grantha-mappings", h.AdminGetGranthaMappings)
+                admin.POST("/grantha-mappings", h.AdminCreateGranthaMapping)
+                admin.PUT("/grantha-mappings/:id", h.AdminUpdateGranthaMapping)
+                admin.DELETE("/grantha-mappings/:id", h.AdminDeleteGranthaMapping)
+                admin.GET("/code-mix-mappings", h.AdminGetCodeMixMappings)
+                admin.POST("/code-mix-mappings", h.AdminCreateCodeMixMapping)
+                admin.PUT("/code-mix-mappings/:id", h.AdminUpdateCodeMixMapping)
+                admin.DELETE("/code-mix-mappings/:id", h.AdminDeleteCodeMixMapping)
         }
 
         log.Printf("[SUCCESS] All routes registered")
@@ -33,6 +33,9 @@ type Config struct {
         TwilioAccountSID             string
         TwilioAuthToken              string
         TwilioPhoneNumber            string
+        QueueWorkers                 int
+        WebhookAllowPrivate          bool
+        StreamBroker                 string
 }
 
 func Load() *Config {
@@ -78,6 +81,9 @@ func Load() *Config {
                 TwilioAccountSID:           getEnv("TWILIO_ACCOUNT_SID", ""),
                 TwilioAuthToken:            getEnv("TWILIO_AUTH_TOKEN", ""),
                 TwilioPhoneNumber:          getEnv("TWILIO_PHONE_NUMBER", ""),
+                QueueWorkers:               getEnvAsInt("QUEUE_WORKERS", 4),
+                WebhookAllowPrivate:        getEnv("WEBHOOK_ALLOW_PRIVATE", "") == "true",
+                StreamBroker:               getEnv("STREAM_BROKER", "postgres"),
         }
 }
 
@@ -0,0 +1,394 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"tamil-proofreading-platform/backend/internal/middleware"
+	"tamil-proofreading-platform/backend/internal/models"
+	"tamil-proofreading-platform/backend/internal/services/batch"
+	"tamil-proofreading-platform/backend/internal/services/broker"
+	"tamil-proofreading-platform/backend/internal/services/document"
+	"tamil-proofreading-platform/backend/internal/services/style"
+	"tamil-proofreading-platform/backend/internal/util/auditlog"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+const maxBatchBytes = 5 << 20
+
+type BatchItemRequest struct {
+	ClientID string `json:"client_id"`
+	Text     string `json:"text"`
+	HTML     string `json:"html"`
+}
+
+type CreateBatchRequest struct {
+	Name                string             `json:"name"`
+	Items               []BatchItemRequest `json:"items"`
+	IncludeAlternatives bool               `json:"include_alternatives"`
+	StyleProfile        string             `json:"style_profile"`
+	PureTamil           bool               `json:"pure_tamil"`
+}
+
+// CreateBatch submits many items for proofreading at once. Each item
+// becomes its own document and submission, queued like any other; progress
+// is published on the batch's stream as items finish.
+// POST /api/v1/batches
+func (h *Handlers) CreateBatch(c *gin.Context) {
+	userID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var req CreateBatchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	req.Name = strings.TrimSpace(req.Name)
+	if len(req.Name) > 255 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "name must be at most 255 bytes"})
+		return
+	}
+	if len(req.Items) == 0 || len(req.Items) > batch.MaxItems {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "items must hold between 1 and " + strconv.Itoa(batch.MaxItems) + " items"})
+		return
+	}
+
+	profile, err := style.Resolve(h.db, userID, req.StyleProfile)
+	if err != nil {
+		h.respondStyleError(c, err)
+		return
+	}
+	styleKey := ""
+	if profile != nil {
+		styleKey = profile.Key
+	}
+
+	requestID := middleware.GetRequestID(c)
+	if requestID == "" {
+		requestID = strconv.FormatInt(time.Now().UnixNano(), 36)
+	}
+
+	seen := make(map[string]bool, len(req.Items))
+	submissions := make([]models.Submission, len(req.Items))
+	revisions := make([]models.DocumentRevision, len(req.Items))
+	items := make([]models.BatchItem, len(req.Items))
+	size := 0
+	for i, item := range req.Items {
+		clientID := strings.TrimSpace(item.ClientID)
+		switch {
+		case clientID == "":
+			c.JSON(http.StatusBadRequest, gin.H{"error": "items[" + strconv.Itoa(i) + "]: client_id is required"})
+			return
+		case len(clientID) > 128:
+			c.JSON(http.StatusBadRequest, gin.H{"error": "items[" + strconv.Itoa(i) + "]: client_id must be at most 128 bytes"})
+			return
+		case seen[clientID]:
+			c.JSON(http.StatusBadRequest, gin.H{"error": "items[" + strconv.Itoa(i) + "]: duplicate client_id " + clientID})
+			return
+		}
+		seen[clientID] = true
+
+		text, safeHTML, wordCount, err := h.cleanSubmissionText(item.Text, item.HTML)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "items[" + strconv.Itoa(i) + "]: " + err.Error(), "client_id": clientID})
+			return
+		}
+		if size += len(text) + len(safeHTML); size > maxBatchBytes {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Batch is too large (max 5MB of text)"})
+			return
+		}
+
+		submissions[i] = *h.newSubmission(userID, requestID+"-"+strconv.Itoa(i+1), text, safeHTML, wordCount, req.IncludeAlternatives, styleKey, req.PureTamil)
+		revisions[i] = models.DocumentRevision{Text: text, HTML: safeHTML, WordCount: wordCount}
+		items[i] = models.BatchItem{ClientID: clientID, Position: i}
+	}
+
+	b := models.Batch{UserID: userID, Name: req.Name, Status: models.BatchProcessing, Total: len(items)}
+	err = h.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&b).Error; err != nil {
+			return err
+		}
+		for i := range items {
+			doc := models.Document{UserID: userID}
+			if err := document.AddRevisionTx(tx, &doc, &revisions[i], &submissions[i]); err != nil {
+				return err
+			}
+			items[i].BatchID = b.ID
+			items[i].SubmissionID = submissions[i].ID
+		}
+		if err := tx.CreateInBatches(&items, 100).Error; err != nil {
+			return err
+		}
+		for i := range submissions {
+			if err := h.jobQueue.Enqueue(tx, submissions[i].ID); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create batch"})
+		return
+	}
+
+	auditlog.Info(c, "batch.created", map[string]any{
+		"batch_id":   b.ID,
+		"request_id": requestID,
+		"items":      len(items),
+	})
+
+	go h.recordUsage(submissions...)
+
+	created := make([]batch.Item, len(items))
+	for i, item := range items {
+		created[i] = batch.Item{ClientID: item.ClientID, SubmissionID: item.SubmissionID, Status: models.StatusPending}
+	}
+	c.JSON(http.StatusAccepted, gin.H{
+		"batch":      b,
+		"items":      created,
+		"message":    "Batch received, proofreading started...",
+		"request_id": requestID,
+	})
+}
+
+// publishBatchProgress sends a finished item's status and the batch's
+// progress to the batch's stream, ending the stream once the batch is
+// completed.
+func (h *Handlers) publishBatchProgress(batchID uint, item models.BatchItem) {
+	var submission models.Submission
+	if err := h.db.Select("id", "status", "error").First(&submission, item.SubmissionID).Error; err == nil {
+		h.batchHub.broadcast(batchID, submissionEvent{
+			Event: "item",
+			Data: batch.Item{
+				ClientID:     item.ClientID,
+				SubmissionID: item.SubmissionID,
+				Status:       submission.Status,
+				Error:        submission.Error,
+			},
+		})
+	}
+
+	progress, err := h.batchService.Progress(batchID)
+	if err != nil {
+		return
+	}
+	h.batchHub.broadcast(batchID, submissionEvent{Event: "progress", Data: progress})
+
+	if finished, _ := h.batchService.Finish(batchID); finished {
+		h.batchHub.broadcast(batchID, submissionEvent{
+			Event: "end",
+			Data:  gin.H{"status": models.BatchCompleted, "progress": progress},
+		})
+
+		var b models.Batch
+		if err := h.db.First(&b, batchID).Error; err == nil {
+			h.publishWebhook(b.UserID, models.EventBatchCompleted, gin.H{
+				"batch_id":     b.ID,
+				"name":         b.Name,
+				"total":        b.Total,
+				"progress":     progress,
+				"completed_at": b.CompletedAt,
+			})
+		}
+	}
+}
+
+// GetBatches lists the user's batches, newest first
+// GET /api/v1/batches?limit=
+func (h *Handlers) GetBatches(c *gin.Context) {
+	userID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit <= 0 || limit > 100 {
+		limit = 20
+	}
+
+	var batches []models.Batch
+	if err := h.db.Where("user_id = ?", userID).Order("created_at DESC").Limit(limit).Find(&batches).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch batches"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"batches": batches})
+}
+
+// GetBatch returns a batch with its progress and the status of each item
+// GET /api/v1/batches/:id
+func (h *Handlers) GetBatch(c *gin.Context) {
+	b, ok := h.loadUserBatch(c)
+	if !ok {
+		return
+	}
+
+	progress, err := h.batchService.Progress(b.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch batch progress"})
+		return
+	}
+	items, err := h.batchService.Items(b.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch batch items"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"batch": b, "progress": progress, "items": items})
+}
+
+// GetBatchResults downloads every item's text, corrected text and
+// suggestions as one JSON or CSV file. Items still being proofread are
+// included with their current status.
+// GET /api/v1/batches/:id/results?format=json|csv&include=all|accepted
+func (h *Handlers) GetBatchResults(c *gin.Context) {
+	b, ok := h.loadUserBatch(c)
+	if !ok {
+		return
+	}
+
+	format := c.DefaultQuery("format", "json")
+	if format != "json" && format != "csv" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "format must be json or csv"})
+		return
+	}
+	include := c.DefaultQuery("include", "all")
+	if include != "all" && include != "accepted" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "include must be all or accepted"})
+		return
+	}
+
+	results, err := h.batchService.Results(b.ID, include == "accepted")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch batch results"})
+		return
+	}
+
+	name := "batch-" + strconv.FormatUint(uint64(b.ID), 10) + "-results." + format
+	c.Header("Content-Disposition", `attachment; filename="`+name+`"`)
+	if format == "csv" {
+		c.Header("Content-Type", "text/csv; charset=utf-8")
+		c.Status(http.StatusOK)
+		if err := batch.WriteCSV(c.Writer, results); err != nil {
+			c.Error(err)
+		}
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"batch": b, "results": results})
+}
+
+// StreamBatch streams a batch's progress using Server-Sent Events: an
+// "item" event as each item finishes, "progress" with the counts after it,
+// and "end" once the batch is completed. A client that reconnects with
+// Last-Event-ID first receives the events it missed.
+// GET /api/v1/stream/batches/:id
+func (h *Handlers) StreamBatch(c *gin.Context) {
+	b, ok := h.loadUserBatch(c)
+	if !ok {
+		return
+	}
+
+	c.Writer.Header().Set("Content-Type", "text/event-stream")
+	c.Writer.Header().Set("Cache-Control", "no-cache")
+	c.Writer.Header().Set("Connection", "keep-alive")
+	c.Writer.Header().Set("X-Accel-Buffering", "no")
+
+	flusher, ok := c.Writer.(http.Flusher)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Streaming unsupported"})
+		return
+	}
+
+	// Listen before taking the snapshot so no update falls between them
+	listener, unsubscribe := h.batchHub.register(b.ID)
+	defer unsubscribe()
+
+	sent := lastEventID(c)
+	if sent > 0 {
+		missed, err := h.batchHub.replay(b.ID, sent)
+		if err != nil {
+			log.Printf("Error replaying events of batch %d: %v", b.ID, err)
+		}
+		for _, msg := range missed {
+			writeStreamEvent(c, msg)
+			sent = msg.ID
+			if msg.Event == broker.EndEvent {
+				flusher.Flush()
+				return
+			}
+		}
+	}
+
+	progress, err := h.batchService.Progress(b.ID)
+	if err != nil {
+		c.SSEvent("failure", gin.H{"message": "Failed to fetch batch progress"})
+		flusher.Flush()
+		return
+	}
+	c.SSEvent("progress", progress)
+	if b.Status == models.BatchCompleted || progress.Done() {
+		c.SSEvent("end", gin.H{"status": models.BatchCompleted, "progress": progress})
+		flusher.Flush()
+		return
+	}
+	flusher.Flush()
+
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case <-c.Request.Context().Done():
+			return false
+		case msg, ok := <-listener:
+			if !ok {
+				return false
+			}
+			if msg.ID <= sent {
+				return true // already replayed
+			}
+			writeStreamEvent(c, msg)
+			flusher.Flush()
+			return msg.Event != broker.EndEvent
+		case <-time.After(25 * time.Second):
+			c.SSEvent("ping", gin.H{"time": time.Now().Unix()})
+			flusher.Flush()
+			return true
+		}
+	})
+}
+
+// loadUserBatch loads the :id batch owned by the current user, writing an
+// error response if it cannot.
+func (h *Handlers) loadUserBatch(c *gin.Context) (*models.Batch, bool) {
+	userID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return nil, false
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid batch ID"})
+		return nil, false
+	}
+
+	b, err := h.batchService.Get(userID, uint(id))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Batch not found"})
+			return nil, false
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch batch"})
+		return nil, false
+	}
+	return b, true
+}
@@ -0,0 +1,192 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"tamil-proofreading-platform/backend/internal/middleware"
+	"tamil-proofreading-platform/backend/internal/models"
+	"tamil-proofreading-platform/backend/internal/util/auditlog"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CodeMixMappingRequest struct {
+	English     string   `json:"english" binding:"required"`
+	Spellings   []string `json:"spellings"`
+	Equivalents []string `json:"equivalents" binding:"required"`
+	IsActive    *bool    `json:"is_active"`
+}
+
+// normalize trims fields and drops empty values. It reports whether the
+// mapping is usable.
+func (r *CodeMixMappingRequest) normalize() bool {
+	r.English = strings.ToLower(strings.TrimSpace(r.English))
+	r.Spellings = trimNonEmpty(r.Spellings)
+	r.Equivalents = trimNonEmpty(r.Equivalents)
+	return r.English != "" && len(r.Equivalents) > 0
+}
+
+func trimNonEmpty(values []string) []string {
+	out := make([]string, 0, len(values))
+	for _, v := range values {
+		if v = strings.TrimSpace(v); v != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// AdminGetCodeMixMappings lists the English → Tamil mapping table
+// GET /api/v1/admin/code-mix-mappings?q=&source=llm
+func (h *Handlers) AdminGetCodeMixMappings(c *gin.Context) {
+	query := h.db.Model(&models.CodeMixMapping{})
+	if q := strings.TrimSpace(c.Query("q")); q != "" {
+		query = query.Where("english LIKE ?", "%"+strings.ToLower(q)+"%")
+	}
+	if source := c.Query("source"); source != "" {
+		query = query.Where("source = ?", source)
+	}
+
+	var mappings []models.CodeMixMapping
+	if err := query.Order("english ASC").Find(&mappings).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch mappings"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"mappings": mappings})
+}
+
+// AdminCreateCodeMixMapping adds a mapping
+// POST /api/v1/admin/code-mix-mappings
+func (h *Handlers) AdminCreateCodeMixMapping(c *gin.Context) {
+	var req CodeMixMappingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if !req.normalize() {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "english and at least one equivalent are required"})
+		return
+	}
+
+	var count int64
+	h.db.Model(&models.CodeMixMapping{}).Where("english = ?", req.English).Count(&count)
+	if count > 0 {
+		c.JSON(http.StatusConflict, gin.H{"error": "A mapping for this word already exists"})
+		return
+	}
+
+	adminID, _ := middleware.GetUserFromContext(c)
+	mapping := models.CodeMixMapping{
+		English:     req.English,
+		Spellings:   req.Spellings,
+		Equivalents: req.Equivalents,
+		Source:      models.CodeMixAdmin,
+		IsActive:    req.IsActive == nil || *req.IsActive,
+		UpdatedBy:   &adminID,
+	}
+	if err := h.db.Create(&mapping).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create mapping"})
+		return
+	}
+	if !mapping.IsActive {
+		h.db.Model(&mapping).Update("is_active", false)
+	}
+	h.codeMixService.Invalidate()
+
+	auditlog.Info(c, "code_mix_mapping.created", map[string]any{
+		"mapping_id": mapping.ID,
+		"admin_id":   adminID,
+	})
+
+	c.JSON(http.StatusCreated, gin.H{"mapping": mapping})
+}
+
+// AdminUpdateCodeMixMapping replaces a mapping, e.g. to correct or disable
+// one learned from the LLM
+// PUT /api/v1/admin/code-mix-mappings/:id
+func (h *Handlers) AdminUpdateCodeMixMapping(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid mapping ID"})
+		return
+	}
+
+	var mapping models.CodeMixMapping
+	if err := h.db.First(&mapping, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Mapping not found"})
+		return
+	}
+
+	var req CodeMixMappingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if !req.normalize() {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "english and at least one equivalent are required"})
+		return
+	}
+	if req.English != mapping.English {
+		var count int64
+		h.db.Model(&models.CodeMixMapping{}).Where("english = ? AND id <> ?", req.English, mapping.ID).Count(&count)
+		if count > 0 {
+			c.JSON(http.StatusConflict, gin.H{"error": "A mapping for this word already exists"})
+			return
+		}
+	}
+
+	adminID, _ := middleware.GetUserFromContext(c)
+	mapping.English = req.English
+	mapping.Spellings = req.Spellings
+	mapping.Equivalents = req.Equivalents
+	mapping.Source = models.CodeMixAdmin
+	if req.IsActive != nil {
+		mapping.IsActive = *req.IsActive
+	}
+	mapping.UpdatedBy = &adminID
+	if err := h.db.Save(&mapping).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update mapping"})
+		return
+	}
+	h.codeMixService.Invalidate()
+
+	auditlog.Info(c, "code_mix_mapping.updated", map[string]any{
+		"mapping_id": mapping.ID,
+		"admin_id":   adminID,
+	})
+
+	c.JSON(http.StatusOK, gin.H{"mapping": mapping})
+}
+
+// AdminDeleteCodeMixMapping removes a mapping. Prefer disabling learned
+// mappings, since deleted ones may be learned again.
+// DELETE /api/v1/admin/code-mix-mappings/:id
+func (h *Handlers) AdminDeleteCodeMixMapping(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid mapping ID"})
+		return
+	}
+
+	result := h.db.Delete(&models.CodeMixMapping{}, id)
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete mapping"})
+		return
+	}
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Mapping not found"})
+		return
+	}
+	h.codeMixService.Invalidate()
+
+	adminID, _ := middleware.GetUserFromContext(c)
+	auditlog.Info(c, "code_mix_mapping.deleted", map[string]any{
+		"mapping_id": id,
+		"admin_id":   adminID,
+	})
+
+	c.JSON(http.StatusOK, gin.H{"message": "Mapping deleted"})
+}
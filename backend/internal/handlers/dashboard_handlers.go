@@ -54,6 +54,16 @@ func (h *Handlers) GetDashboardStats(c *gin.Context) {
 		Where("user_id = ? AND status = ?", userID, models.StatusCompleted).
 		Count(&completedCount)
 
+	// Get code-mixing counts for this month
+	var codeMix struct {
+		Words       int
+		Submissions int
+	}
+	h.db.Model(&models.Submission{}).
+		Where("user_id = ? AND created_at >= ?", userID, startOfMonth).
+		Select("COALESCE(SUM(code_mix_count), 0) AS words, COUNT(*) FILTER (WHERE code_mix_count > 0) AS submissions").
+		Scan(&codeMix)
+
 	// Get recent submissions
 	var recentSubmissions []models.Submission
 	h.db.Where("user_id = ?", userID).
@@ -76,6 +86,10 @@ func (h *Handlers) GetDashboardStats(c *gin.Context) {
 			"pending":   pendingCount,
 			"completed": completedCount,
 		},
+		"code_mix": gin.H{
+			"words":       codeMix.Words,
+			"submissions": codeMix.Submissions,
+		},
 		"recent_submissions": recentSubmissions,
 		"subscription": gin.H{
 			"plan":           user.Subscription,
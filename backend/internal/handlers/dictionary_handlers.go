@@ -0,0 +1,53 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"tamil-proofreading-platform/backend/internal/middleware"
+	"tamil-proofreading-platform/backend/internal/services/userdict"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetHunspellDictionary serves the generated Hunspell dictionary files
+// GET /api/v1/dictionary/hunspell/ta_IN.dic
+// GET /api/v1/dictionary/hunspell/ta_IN.aff
+func (h *Handlers) GetHunspellDictionary(c *gin.Context) {
+	file := c.Param("file")
+	if file != "ta_IN.dic" && file != "ta_IN.aff" {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Unknown dictionary file, expected ta_IN.dic or ta_IN.aff"})
+		return
+	}
+
+	// Personal dictionary words are added so spellcheckers accept them too.
+	var personal []string
+	if userID, err := middleware.GetUserFromContext(c); err == nil {
+		if userDict, err := userdict.Load(h.db, userID); err == nil {
+			personal = userDict.Words()
+		}
+	}
+
+	dict, err := h.hunspellGenerator.Build(personal)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate dictionary"})
+		return
+	}
+
+	body, tag := dict.Dic, dict.DicETag
+	if file == "ta_IN.aff" {
+		body, tag = dict.Aff, dict.AffETag
+	}
+
+	c.Header("ETag", tag)
+	c.Header("Cache-Control", "private, max-age=300")
+	c.Header("Last-Modified", dict.GeneratedAt.UTC().Format(http.TimeFormat))
+	c.Header("X-Dictionary-Words", strconv.Itoa(dict.WordCount))
+	if match := c.GetHeader("If-None-Match"); match != "" && match == tag {
+		c.Status(http.StatusNotModified)
+		return
+	}
+
+	c.Header("Content-Disposition", `attachment; filename="`+file+`"`)
+	c.Data(http.StatusOK, "text/plain; charset=utf-8", body)
+}
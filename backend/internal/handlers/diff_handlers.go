@@ -0,0 +1,124 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+
+	"tamil-proofreading-platform/backend/internal/models"
+	"tamil-proofreading-platform/backend/internal/services/diff"
+	"tamil-proofreading-platform/backend/internal/util/auditlog"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetSubmissionDiff diffs a submission's original text against its
+// proofread text, or its final text with ?target=final
+// GET /api/v1/submissions/:id/diff?granularity=word|grapheme|line&format=json|html|unified
+func (h *Handlers) GetSubmissionDiff(c *gin.Context) {
+	submission, ok := h.loadUserSubmission(c)
+	if !ok {
+		return
+	}
+
+	target, name, ok := submissionDiffTarget(c, submission)
+	if !ok {
+		return
+	}
+	respondDiff(c, submission.OriginalText, target, "original", name, diff.ByWord)
+}
+
+// EmailSubmissionReport emails the user a report of the changes made to a
+// submission
+// POST /api/v1/submissions/:id/email-report?target=proofread|final
+func (h *Handlers) EmailSubmissionReport(c *gin.Context) {
+	submission, ok := h.loadUserSubmission(c)
+	if !ok {
+		return
+	}
+
+	target, _, ok := submissionDiffTarget(c, submission)
+	if !ok {
+		return
+	}
+
+	var user models.User
+	if err := h.db.First(&user, submission.UserID).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load user"})
+		return
+	}
+	if !h.emailService.IsConfigured() {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Email is not configured"})
+		return
+	}
+
+	ops := diff.Words(submission.OriginalText, target)
+	changes := len(diff.Changes(ops))
+	if err := h.emailService.SendProofreadReport(user.Email, submissionTitle(submission), diff.HTML(submission.OriginalText, ops), changes); err != nil {
+		log.Printf("Error sending report for submission %d: %v", submission.ID, err)
+		c.JSON(http.StatusBadGateway, gin.H{"error": "Failed to send report"})
+		return
+	}
+
+	auditlog.Info(c, "submission.report_emailed", map[string]any{
+		"submission_id": submission.ID,
+		"changes":       changes,
+	})
+
+	c.JSON(http.StatusOK, gin.H{"message": "Report sent", "changes": changes})
+}
+
+func submissionDiffTarget(c *gin.Context, submission *models.Submission) (string, string, bool) {
+	switch c.DefaultQuery("target", "proofread") {
+	case "proofread":
+		return submission.ProofreadText, "proofread", true
+	case "final":
+		if submission.FinalText == "" {
+			return submission.OriginalText, "final", true
+		}
+		return submission.FinalText, "final", true
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "target must be proofread or final"})
+		return "", "", false
+	}
+}
+
+func submissionTitle(submission *models.Submission) string {
+	runes := []rune(submission.OriginalText)
+	if len(runes) > 60 {
+		return string(runes[:60]) + "…"
+	}
+	return string(runes)
+}
+
+// respondDiff writes the diff of two texts in the format named by ?format,
+// at the granularity named by ?granularity.
+func respondDiff(c *gin.Context, from, to, fromName, toName string, defaultGranularity diff.Granularity) {
+	granularity := diff.Granularity(c.DefaultQuery("granularity", string(defaultGranularity)))
+	switch granularity {
+	case diff.ByLine, diff.ByWord, diff.ByGrapheme:
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "granularity must be line, word or grapheme"})
+		return
+	}
+
+	switch c.DefaultQuery("format", "json") {
+	case "json":
+		ops := diff.Compute(from, to, granularity)
+		c.JSON(http.StatusOK, gin.H{
+			"granularity": granularity,
+			"ops":         ops,
+			"changes":     len(diff.Changes(ops)),
+		})
+	case "html":
+		ops := diff.Compute(from, to, granularity)
+		c.JSON(http.StatusOK, gin.H{
+			"granularity": granularity,
+			"html":        diff.HTML(from, ops),
+			"changes":     len(diff.Changes(ops)),
+		})
+	case "unified":
+		c.String(http.StatusOK, diff.Unified(from, to, fromName, toName, 3))
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "format must be json, html or unified"})
+	}
+}
@@ -0,0 +1,381 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"tamil-proofreading-platform/backend/internal/middleware"
+	"tamil-proofreading-platform/backend/internal/models"
+	"tamil-proofreading-platform/backend/internal/services/diff"
+	"tamil-proofreading-platform/backend/internal/services/document"
+	"tamil-proofreading-platform/backend/internal/services/style"
+	"tamil-proofreading-platform/backend/internal/util/auditlog"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type DocumentRevisionRequest struct {
+	Text string `json:"text"`
+	HTML string `json:"html"`
+	Note string `json:"note"`
+	// Proofread sends the revision for proofreading with the options below.
+	Proofread           bool   `json:"proofread"`
+	IncludeAlternatives bool   `json:"include_alternatives"`
+	StyleProfile        string `json:"style_profile"`
+	PureTamil           bool   `json:"pure_tamil"`
+}
+
+type CreateDocumentRequest struct {
+	Title string `json:"title"`
+	DocumentRevisionRequest
+}
+
+type UpdateDocumentRequest struct {
+	Title string `json:"title" binding:"required"`
+}
+
+type RestoreRevisionRequest struct {
+	Proofread    bool   `json:"proofread"`
+	StyleProfile string `json:"style_profile"`
+	PureTamil    bool   `json:"pure_tamil"`
+}
+
+// GetDocuments lists the user's documents, most recently changed first
+// GET /api/v1/documents?limit=&offset=
+func (h *Handlers) GetDocuments(c *gin.Context) {
+	userID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit <= 0 || limit > 100 {
+		limit = 20
+	}
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		offset = 0
+	}
+
+	var documents []models.Document
+	if err := h.db.Where("user_id = ?", userID).
+		Order("updated_at DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&documents).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch documents"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"documents": documents})
+}
+
+// CreateDocument starts a document with its first revision
+// POST /api/v1/documents
+func (h *Handlers) CreateDocument(c *gin.Context) {
+	userID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var req CreateDocumentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	doc := &models.Document{UserID: userID, Title: strings.TrimSpace(req.Title)}
+	h.saveDocumentRevision(c, doc, req.DocumentRevisionRequest, nil)
+}
+
+// GetDocument returns a document with its current revision
+// GET /api/v1/documents/:id
+func (h *Handlers) GetDocument(c *gin.Context) {
+	doc, ok := h.loadUserDocument(c)
+	if !ok {
+		return
+	}
+
+	revision, err := h.documentService.Revision(doc.ID, doc.CurrentRevision)
+	if err != nil {
+		respondDocumentError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"document": doc, "revision": revision})
+}
+
+// UpdateDocument renames a document
+// PUT /api/v1/documents/:id
+func (h *Handlers) UpdateDocument(c *gin.Context) {
+	doc, ok := h.loadUserDocument(c)
+	if !ok {
+		return
+	}
+
+	var req UpdateDocumentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	title := strings.TrimSpace(req.Title)
+	if title == "" || len(title) > 255 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "title must be 1-255 bytes"})
+		return
+	}
+
+	if err := h.db.Model(doc).Update("title", title).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update document"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"document": doc})
+}
+
+// DeleteDocument deletes a document. Its submissions are kept.
+// DELETE /api/v1/documents/:id
+func (h *Handlers) DeleteDocument(c *gin.Context) {
+	doc, ok := h.loadUserDocument(c)
+	if !ok {
+		return
+	}
+
+	if err := h.db.Delete(doc).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete document"})
+		return
+	}
+
+	auditlog.Info(c, "document.deleted", map[string]any{"document_id": doc.ID, "user_id": doc.UserID})
+	c.JSON(http.StatusOK, gin.H{"message": "Document deleted"})
+}
+
+// GetDocumentRevisions lists a document's revisions without their text,
+// newest first
+// GET /api/v1/documents/:id/revisions
+func (h *Handlers) GetDocumentRevisions(c *gin.Context) {
+	doc, ok := h.loadUserDocument(c)
+	if !ok {
+		return
+	}
+
+	var revisions []models.DocumentRevision
+	if err := h.db.Omit("text", "html").
+		Where("document_id = ?", doc.ID).
+		Order("number DESC").
+		Find(&revisions).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch revisions"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"document": doc, "revisions": revisions})
+}
+
+// CreateDocumentRevision saves a new revision, optionally proofreading it
+// POST /api/v1/documents/:id/revisions
+func (h *Handlers) CreateDocumentRevision(c *gin.Context) {
+	doc, ok := h.loadUserDocument(c)
+	if !ok {
+		return
+	}
+
+	var req DocumentRevisionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	h.saveDocumentRevision(c, doc, req, nil)
+}
+
+// GetDocumentRevision returns one revision with its text
+// GET /api/v1/documents/:id/revisions/:number
+func (h *Handlers) GetDocumentRevision(c *gin.Context) {
+	doc, ok := h.loadUserDocument(c)
+	if !ok {
+		return
+	}
+
+	revision, ok := h.loadRevisionParam(c, doc, c.Param("number"))
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"revision": revision})
+}
+
+// RestoreDocumentRevision saves a copy of an earlier revision as the new
+// current revision; history is never rewritten
+// POST /api/v1/documents/:id/revisions/:number/restore
+func (h *Handlers) RestoreDocumentRevision(c *gin.Context) {
+	doc, ok := h.loadUserDocument(c)
+	if !ok {
+		return
+	}
+
+	revision, ok := h.loadRevisionParam(c, doc, c.Param("number"))
+	if !ok {
+		return
+	}
+
+	var req RestoreRevisionRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	h.saveDocumentRevision(c, doc, DocumentRevisionRequest{
+		Text:         revision.Text,
+		HTML:         revision.HTML,
+		Note:         "Restored from revision " + strconv.Itoa(revision.Number),
+		Proofread:    req.Proofread,
+		StyleProfile: req.StyleProfile,
+		PureTamil:    req.PureTamil,
+	}, &revision.Number)
+}
+
+// GetDocumentDiff compares two revisions, line by line unless another
+// granularity is given. from defaults to the revision before to, and to
+// defaults to the current revision.
+// GET /api/v1/documents/:id/diff?from=&to=&granularity=&format=json|html|unified
+func (h *Handlers) GetDocumentDiff(c *gin.Context) {
+	doc, ok := h.loadUserDocument(c)
+	if !ok {
+		return
+	}
+
+	toParam := c.DefaultQuery("to", strconv.Itoa(doc.CurrentRevision))
+	to, ok := h.loadRevisionParam(c, doc, toParam)
+	if !ok {
+		return
+	}
+	previous := to.Number - 1
+	if previous < 1 {
+		previous = to.Number
+	}
+	fromParam := c.DefaultQuery("from", strconv.Itoa(previous))
+	from, ok := h.loadRevisionParam(c, doc, fromParam)
+	if !ok {
+		return
+	}
+
+	respondDiff(c, from.Text, to.Text, "revision "+strconv.Itoa(from.Number), "revision "+strconv.Itoa(to.Number), diff.ByLine)
+}
+
+// saveDocumentRevision validates req and appends it to doc, creating doc
+// when it is new, and starts proofreading when requested.
+func (h *Handlers) saveDocumentRevision(c *gin.Context, doc *models.Document, req DocumentRevisionRequest, restoredFrom *int) {
+	requestID := middleware.GetRequestID(c)
+	if requestID == "" {
+		requestID = strconv.FormatInt(time.Now().UnixNano(), 36)
+	}
+
+	text, safeHTML, wordCount, ok := h.prepareSubmissionText(c, req.Text, req.HTML)
+	if !ok {
+		return
+	}
+
+	revision := &models.DocumentRevision{
+		Text:         text,
+		HTML:         safeHTML,
+		WordCount:    wordCount,
+		Note:         strings.TrimSpace(req.Note),
+		RestoredFrom: restoredFrom,
+	}
+	if len(revision.Note) > 255 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "note must be at most 255 bytes"})
+		return
+	}
+
+	var submission *models.Submission
+	if req.Proofread {
+		profile, err := style.Resolve(h.db, doc.UserID, req.StyleProfile)
+		if err != nil {
+			h.respondStyleError(c, err)
+			return
+		}
+		styleKey := ""
+		if profile != nil {
+			styleKey = profile.Key
+		}
+		submission = h.newSubmission(doc.UserID, requestID, text, safeHTML, wordCount, req.IncludeAlternatives, styleKey, req.PureTamil)
+	}
+
+	if err := h.documentService.AddRevision(doc, revision, submission); err != nil {
+		log.Printf("Error saving revision for document %d: %v", doc.ID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save revision"})
+		return
+	}
+	if submission != nil {
+		h.startSubmission(c, submission)
+	}
+
+	auditlog.Info(c, "document.revision_saved", map[string]any{
+		"document_id": doc.ID,
+		"revision":    revision.Number,
+		"proofread":   submission != nil,
+	})
+
+	c.JSON(http.StatusCreated, gin.H{
+		"document":   doc,
+		"revision":   revision,
+		"submission": submission,
+	})
+}
+
+// loadUserDocument loads the :id document owned by the current user,
+// writing an error response if it cannot.
+func (h *Handlers) loadUserDocument(c *gin.Context) (*models.Document, bool) {
+	userID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return nil, false
+	}
+
+	documentID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid document ID"})
+		return nil, false
+	}
+
+	doc, err := h.documentService.Get(userID, uint(documentID))
+	if err != nil {
+		respondDocumentError(c, err)
+		return nil, false
+	}
+	return doc, true
+}
+
+func (h *Handlers) loadRevisionParam(c *gin.Context, doc *models.Document, param string) (*models.DocumentRevision, bool) {
+	number, err := strconv.Atoi(param)
+	if err != nil || number < 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid revision number"})
+		return nil, false
+	}
+
+	revision, err := h.documentService.Revision(doc.ID, number)
+	if err != nil {
+		respondDocumentError(c, err)
+		return nil, false
+	}
+	return revision, true
+}
+
+func respondDocumentError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Document not found"})
+	case errors.Is(err, document.ErrRevisionNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch document"})
+	}
+}
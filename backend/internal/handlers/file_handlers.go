@@ -0,0 +1,249 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"tamil-proofreading-platform/backend/internal/middleware"
+	"tamil-proofreading-platform/backend/internal/models"
+	"tamil-proofreading-platform/backend/internal/services/fileformat"
+	"tamil-proofreading-platform/backend/internal/services/style"
+	"tamil-proofreading-platform/backend/internal/services/suggestions"
+	"tamil-proofreading-platform/backend/internal/util/auditlog"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+const maxUploadBytes = 10 << 20
+
+// GetFileFormats lists the file formats that can be uploaded
+// GET /api/v1/file-formats
+func (h *Handlers) GetFileFormats(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"formats": fileformat.Names()})
+}
+
+// SubmitFile proofreads an uploaded file. The multipart form takes "file"
+// plus the optional fields of SubmitText: style_profile, pure_tamil,
+// include_alternatives and document_id.
+// POST /api/v1/submit/file
+func (h *Handlers) SubmitFile(c *gin.Context) {
+	requestID := middleware.GetRequestID(c)
+	if requestID == "" {
+		requestID = strconv.FormatInt(time.Now().UnixNano(), 36)
+	}
+
+	userID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized - please login"})
+		return
+	}
+
+	header, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "file is required"})
+		return
+	}
+	if header.Size > maxUploadBytes {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "File is too large (max 10MB)"})
+		return
+	}
+	format, err := fileformat.ForFilename(header.Filename)
+	if err != nil {
+		c.JSON(http.StatusUnsupportedMediaType, gin.H{
+			"error":     "Unsupported file type",
+			"supported": fileformat.Names(),
+		})
+		return
+	}
+
+	f, err := header.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read upload"})
+		return
+	}
+	defer f.Close()
+	data, err := io.ReadAll(io.LimitReader(f, maxUploadBytes+1))
+	if err != nil || len(data) > maxUploadBytes {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read upload"})
+		return
+	}
+
+	extracted, err := format.Extract(data)
+	if err != nil {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	}
+	textOffset := len(extracted) - len(strings.TrimLeftFunc(extracted, unicode.IsSpace))
+
+	text, _, wordCount, ok := h.prepareSubmissionText(c, extracted, "")
+	if !ok {
+		return
+	}
+
+	profile, err := style.Resolve(h.db, userID, c.PostForm("style_profile"))
+	if err != nil {
+		h.respondStyleError(c, err)
+		return
+	}
+	styleKey := ""
+	if profile != nil {
+		styleKey = profile.Key
+	}
+
+	doc := models.Document{UserID: userID, Title: strings.TrimSuffix(filepath.Base(header.Filename), filepath.Ext(header.Filename))}
+	if raw := c.PostForm("document_id"); raw != "" {
+		documentID, err := strconv.ParseUint(raw, 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid document ID"})
+			return
+		}
+		existing, err := h.documentService.Get(userID, uint(documentID))
+		if err != nil {
+			respondDocumentError(c, err)
+			return
+		}
+		doc = *existing
+	}
+
+	pureTamil, _ := strconv.ParseBool(c.PostForm("pure_tamil"))
+	includeAlternatives, _ := strconv.ParseBool(c.PostForm("include_alternatives"))
+	submission := h.newSubmission(userID, requestID, text, "", wordCount, includeAlternatives, styleKey, pureTamil)
+	revision := &models.DocumentRevision{
+		Text:      text,
+		WordCount: wordCount,
+		Note:      "Uploaded " + filepath.Base(header.Filename),
+	}
+	file := &models.SubmissionFile{
+		UserID:      userID,
+		Filename:    filepath.Base(header.Filename),
+		Format:      format.Name(),
+		ContentType: format.ContentType(),
+		Size:        len(data),
+		TextOffset:  textOffset,
+		Data:        data,
+	}
+	if err := h.documentService.AddRevision(&doc, revision, submission, func(tx *gorm.DB) error {
+		file.SubmissionID = submission.ID
+		return tx.Create(file).Error
+	}); err != nil {
+		log.Printf("Error creating file submission: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create submission"})
+		return
+	}
+
+	h.startSubmission(c, submission)
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"submission": submission,
+		"file":       file,
+		"document":   doc,
+		"revision":   revision.Number,
+		"message":    "Submission received, proofreading started...",
+		"request_id": requestID,
+	})
+}
+
+// ExportSubmission writes the submission's suggestions back into its
+// uploaded file. By default every suggestion that has not been rejected is
+// written as a tracked change with its reason as a comment; include=accepted
+// limits this to accepted suggestions and track=false applies them
+// directly. Submissions without a file can be exported with format=txt.
+// GET /api/v1/submissions/:id/export?include=all|accepted&track=true|false&format=txt
+func (h *Handlers) ExportSubmission(c *gin.Context) {
+	submission, ok := h.loadUserSubmission(c)
+	if !ok {
+		return
+	}
+
+	include := c.DefaultQuery("include", "all")
+	if include != "all" && include != "accepted" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "include must be all or accepted"})
+		return
+	}
+	track := c.DefaultQuery("track", "true") != "false"
+
+	var file models.SubmissionFile
+	if err := h.db.Where("submission_id = ?", submission.ID).First(&file).Error; err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load file"})
+			return
+		}
+		// Text submissions can still be exported as a plain text file
+		if c.Query("format") != "txt" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Submission has no uploaded file; use format=txt"})
+			return
+		}
+		file = models.SubmissionFile{
+			Filename: "submission-" + strconv.FormatUint(uint64(submission.ID), 10) + ".txt",
+			Format:   "txt",
+			Data:     []byte(submission.OriginalText),
+		}
+	}
+	format, err := fileformat.Lookup(file.Format)
+	if err != nil {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	}
+
+	rows, err := suggestions.Load(h.db, *submission)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch suggestions"})
+		return
+	}
+	edits := fileEdits(rows, include == "accepted", file.TextOffset)
+
+	out, err := format.Apply(file.Data, edits, fileformat.ApplyOptions{
+		TrackChanges: track,
+		Author:       "ProofTamil",
+		Date:         time.Now(),
+	})
+	if err != nil {
+		log.Printf("Error exporting submission %d: %v", submission.ID, err)
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	}
+
+	auditlog.Info(c, "submission.exported", map[string]any{
+		"submission_id": submission.ID,
+		"format":        file.Format,
+		"edits":         len(edits),
+	})
+
+	ext := filepath.Ext(file.Filename)
+	name := strings.TrimSuffix(file.Filename, ext) + "-proofread" + ext
+	c.Header("Content-Disposition", `attachment; filename="`+strings.ReplaceAll(name, `"`, "")+`"`)
+	c.Data(http.StatusOK, format.ContentType(), out)
+}
+
+// fileEdits converts suggestion rows to edits on the file's text, skipping
+// rejected ones and, with acceptedOnly, pending ones.
+func fileEdits(rows []models.SubmissionSuggestion, acceptedOnly bool, offset int) []fileformat.Edit {
+	var edits []fileformat.Edit
+	for _, s := range rows {
+		if !s.Applicable() || s.State == models.SuggestionRejected {
+			continue
+		}
+		if acceptedOnly && s.State != models.SuggestionAccepted {
+			continue
+		}
+		if s.Original == s.Corrected {
+			continue
+		}
+		edits = append(edits, fileformat.Edit{
+			Start:       s.StartIndex + offset,
+			End:         s.EndIndex + offset,
+			Original:    s.Original,
+			Replacement: s.Corrected,
+			Reason:      s.Reason,
+		})
+	}
+	return edits
+}
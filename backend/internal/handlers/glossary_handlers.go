@@ -0,0 +1,490 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"tamil-proofreading-platform/backend/internal/models"
+	"tamil-proofreading-platform/backend/internal/services/glossary"
+	"tamil-proofreading-platform/backend/internal/util/auditlog"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+const maxGlossaryImportBytes = 2 << 20
+
+type GlossaryRequest struct {
+	Name        *string `json:"name"`
+	Description *string `json:"description"`
+	IsActive    *bool   `json:"is_active"`
+}
+
+type GlossaryTermRequest struct {
+	Term        string                   `json:"term" binding:"required"`
+	Kind        models.GlossaryTermKind  `json:"kind"`
+	Variants    []string                 `json:"variants"`
+	Replacement string                   `json:"replacement"`
+	MatchMode   models.GlossaryMatchMode `json:"match_mode"`
+	Note        string                   `json:"note"`
+}
+
+func (r *GlossaryTermRequest) toModel() models.GlossaryTerm {
+	term := models.GlossaryTerm{
+		Term:        r.Term,
+		Kind:        r.Kind,
+		Variants:    r.Variants,
+		Replacement: r.Replacement,
+		MatchMode:   r.MatchMode,
+		Note:        r.Note,
+	}
+	term.Normalize()
+	return term
+}
+
+// GetGlossaries lists the organization's glossaries
+// GET /api/v1/glossaries
+func (h *Handlers) GetGlossaries(c *gin.Context) {
+	member, ok := h.requireOrgMember(c, false)
+	if !ok {
+		return
+	}
+
+	var glossaries []models.Glossary
+	if err := h.db.Where("organization_id = ?", member.OrganizationID).Order("name ASC").Find(&glossaries).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch glossaries"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"glossaries": glossaries})
+}
+
+// CreateGlossary creates an empty glossary
+// POST /api/v1/glossaries
+func (h *Handlers) CreateGlossary(c *gin.Context) {
+	member, ok := h.requireOrgMember(c, true)
+	if !ok {
+		return
+	}
+
+	var req GlossaryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.Name == nil || strings.TrimSpace(*req.Name) == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "name is required"})
+		return
+	}
+
+	g := models.Glossary{
+		OrganizationID: member.OrganizationID,
+		Name:           strings.TrimSpace(*req.Name),
+		IsActive:       true,
+	}
+	if req.Description != nil {
+		g.Description = strings.TrimSpace(*req.Description)
+	}
+	if req.IsActive != nil {
+		g.IsActive = *req.IsActive
+	}
+
+	err := h.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&g).Error; err != nil {
+			return err
+		}
+		// Persist an explicit false; gorm skips zero values on create.
+		if !g.IsActive {
+			if err := tx.Model(&g).Update("is_active", false).Error; err != nil {
+				return err
+			}
+		}
+		return glossary.Commit(tx, &g, member.UserID, "Created glossary")
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create glossary"})
+		return
+	}
+
+	auditlog.Info(c, "glossary.created", map[string]any{
+		"organization_id": member.OrganizationID,
+		"glossary_id":     g.ID,
+	})
+
+	c.JSON(http.StatusCreated, gin.H{"glossary": g})
+}
+
+// GetGlossary returns a glossary with its terms
+// GET /api/v1/glossaries/:id
+func (h *Handlers) GetGlossary(c *gin.Context) {
+	member, ok := h.requireOrgMember(c, false)
+	if !ok {
+		return
+	}
+	g, ok := h.loadOrgGlossary(c, member)
+	if !ok {
+		return
+	}
+
+	if err := h.db.Where("glossary_id = ?", g.ID).Order("term ASC").Find(&g.Terms).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch terms"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"glossary": g})
+}
+
+// UpdateGlossary renames, describes or (de)activates a glossary
+// PUT /api/v1/glossaries/:id
+func (h *Handlers) UpdateGlossary(c *gin.Context) {
+	member, ok := h.requireOrgMember(c, true)
+	if !ok {
+		return
+	}
+	g, ok := h.loadOrgGlossary(c, member)
+	if !ok {
+		return
+	}
+
+	var req GlossaryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	updates := map[string]interface{}{}
+	if req.Name != nil {
+		name := strings.TrimSpace(*req.Name)
+		if name == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "name cannot be empty"})
+			return
+		}
+		updates["name"] = name
+	}
+	if req.Description != nil {
+		updates["description"] = strings.TrimSpace(*req.Description)
+	}
+	if req.IsActive != nil {
+		updates["is_active"] = *req.IsActive
+	}
+	if len(updates) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Nothing to update"})
+		return
+	}
+
+	if err := h.db.Model(g).Updates(updates).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update glossary"})
+		return
+	}
+
+	auditlog.Info(c, "glossary.updated", map[string]any{"glossary_id": g.ID})
+	c.JSON(http.StatusOK, gin.H{"glossary": g})
+}
+
+// DeleteGlossary deletes a glossary
+// DELETE /api/v1/glossaries/:id
+func (h *Handlers) DeleteGlossary(c *gin.Context) {
+	member, ok := h.requireOrgMember(c, true)
+	if !ok {
+		return
+	}
+	g, ok := h.loadOrgGlossary(c, member)
+	if !ok {
+		return
+	}
+
+	if err := h.db.Delete(g).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete glossary"})
+		return
+	}
+
+	auditlog.Info(c, "glossary.deleted", map[string]any{"glossary_id": g.ID})
+	c.JSON(http.StatusOK, gin.H{"message": "Glossary deleted"})
+}
+
+// AddGlossaryTerm adds a term
+// POST /api/v1/glossaries/:id/terms
+func (h *Handlers) AddGlossaryTerm(c *gin.Context) {
+	member, ok := h.requireOrgMember(c, true)
+	if !ok {
+		return
+	}
+	g, ok := h.loadOrgGlossary(c, member)
+	if !ok {
+		return
+	}
+
+	var req GlossaryTermRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	term := req.toModel()
+	term.GlossaryID = g.ID
+	if err := glossary.Validate(&term); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	err := h.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&term).Error; err != nil {
+			return err
+		}
+		return glossary.Commit(tx, g, member.UserID, fmt.Sprintf("Added %s term %q", term.Kind, term.Term))
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to add term"})
+		return
+	}
+
+	auditlog.Info(c, "glossary.term_added", map[string]any{
+		"glossary_id": g.ID,
+		"term_id":     term.ID,
+		"version":     g.Version,
+	})
+
+	c.JSON(http.StatusCreated, gin.H{"term": term, "version": g.Version})
+}
+
+// UpdateGlossaryTerm replaces a term
+// PUT /api/v1/glossaries/:id/terms/:term_id
+func (h *Handlers) UpdateGlossaryTerm(c *gin.Context) {
+	member, ok := h.requireOrgMember(c, true)
+	if !ok {
+		return
+	}
+	g, ok := h.loadOrgGlossary(c, member)
+	if !ok {
+		return
+	}
+	existing, ok := h.loadGlossaryTerm(c, g)
+	if !ok {
+		return
+	}
+
+	var req GlossaryTermRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	term := req.toModel()
+	if err := glossary.Validate(&term); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	term.ID = existing.ID
+	term.GlossaryID = g.ID
+	term.CreatedAt = existing.CreatedAt
+
+	err := h.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(&term).Error; err != nil {
+			return err
+		}
+		return glossary.Commit(tx, g, member.UserID, fmt.Sprintf("Updated term %q", term.Term))
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update term"})
+		return
+	}
+
+	auditlog.Info(c, "glossary.term_updated", map[string]any{
+		"glossary_id": g.ID,
+		"term_id":     term.ID,
+		"version":     g.Version,
+	})
+
+	c.JSON(http.StatusOK, gin.H{"term": term, "version": g.Version})
+}
+
+// DeleteGlossaryTerm removes a term
+// DELETE /api/v1/glossaries/:id/terms/:term_id
+func (h *Handlers) DeleteGlossaryTerm(c *gin.Context) {
+	member, ok := h.requireOrgMember(c, true)
+	if !ok {
+		return
+	}
+	g, ok := h.loadOrgGlossary(c, member)
+	if !ok {
+		return
+	}
+	term, ok := h.loadGlossaryTerm(c, g)
+	if !ok {
+		return
+	}
+
+	err := h.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(term).Error; err != nil {
+			return err
+		}
+		return glossary.Commit(tx, g, member.UserID, fmt.Sprintf("Removed term %q", term.Term))
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete term"})
+		return
+	}
+
+	auditlog.Info(c, "glossary.term_deleted", map[string]any{
+		"glossary_id": g.ID,
+		"term_id":     term.ID,
+		"version":     g.Version,
+	})
+
+	c.JSON(http.StatusOK, gin.H{"message": "Term deleted", "version": g.Version})
+}
+
+// ImportGlossaryCSV upserts terms from a CSV upload (form field "file") or a
+// text/csv body. ?mode=replace removes terms missing from the file.
+// POST /api/v1/glossaries/:id/import
+func (h *Handlers) ImportGlossaryCSV(c *gin.Context) {
+	member, ok := h.requireOrgMember(c, true)
+	if !ok {
+		return
+	}
+	g, ok := h.loadOrgGlossary(c, member)
+	if !ok {
+		return
+	}
+
+	mode := c.DefaultQuery("mode", "merge")
+	if mode != "merge" && mode != "replace" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "mode must be merge or replace"})
+		return
+	}
+
+	var body io.Reader = c.Request.Body
+	if file, err := c.FormFile("file"); err == nil {
+		f, err := file.Open()
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read upload"})
+			return
+		}
+		defer f.Close()
+		body = f
+	}
+
+	terms, err := glossary.ParseCSV(io.LimitReader(body, maxGlossaryImportBytes))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := h.glossaryService.Import(g, terms, mode == "replace", member.UserID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to import terms"})
+		return
+	}
+
+	auditlog.Info(c, "glossary.imported", map[string]any{
+		"glossary_id": g.ID,
+		"added":       result.Added,
+		"updated":     result.Updated,
+		"removed":     result.Removed,
+		"version":     g.Version,
+	})
+
+	c.JSON(http.StatusOK, gin.H{"result": result, "version": g.Version})
+}
+
+// GetGlossaryVersions lists a glossary's version history
+// GET /api/v1/glossaries/:id/versions
+func (h *Handlers) GetGlossaryVersions(c *gin.Context) {
+	member, ok := h.requireOrgMember(c, false)
+	if !ok {
+		return
+	}
+	g, ok := h.loadOrgGlossary(c, member)
+	if !ok {
+		return
+	}
+
+	var versions []models.GlossaryVersion
+	if err := h.db.Select("id", "glossary_id", "version", "term_count", "summary", "created_by", "created_at").
+		Where("glossary_id = ?", g.ID).Order("version DESC").Find(&versions).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch versions"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"versions": versions, "current": g.Version})
+}
+
+// GetGlossaryVersion returns one snapshot including its terms
+// GET /api/v1/glossaries/:id/versions/:version
+func (h *Handlers) GetGlossaryVersion(c *gin.Context) {
+	member, ok := h.requireOrgMember(c, false)
+	if !ok {
+		return
+	}
+	g, ok := h.loadOrgGlossary(c, member)
+	if !ok {
+		return
+	}
+
+	version, err := strconv.Atoi(c.Param("version"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid version"})
+		return
+	}
+
+	var snapshot models.GlossaryVersion
+	if err := h.db.Where("glossary_id = ? AND version = ?", g.ID, version).First(&snapshot).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Version not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"version": snapshot})
+}
+
+// RestoreGlossaryVersion replaces the terms with a snapshot
+// POST /api/v1/glossaries/:id/versions/:version/restore
+func (h *Handlers) RestoreGlossaryVersion(c *gin.Context) {
+	member, ok := h.requireOrgMember(c, true)
+	if !ok {
+		return
+	}
+	g, ok := h.loadOrgGlossary(c, member)
+	if !ok {
+		return
+	}
+
+	version, err := strconv.Atoi(c.Param("version"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid version"})
+		return
+	}
+
+	if err := h.glossaryService.Restore(g, version, member.UserID); err != nil {
+		if errors.Is(err, glossary.ErrVersionNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Version not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to restore version"})
+		return
+	}
+
+	auditlog.Info(c, "glossary.restored", map[string]any{
+		"glossary_id":   g.ID,
+		"restored_from": version,
+		"version":       g.Version,
+	})
+
+	c.JSON(http.StatusOK, gin.H{"message": "Glossary restored", "version": g.Version})
+}
+
+func (h *Handlers) loadGlossaryTerm(c *gin.Context, g *models.Glossary) (*models.GlossaryTerm, bool) {
+	id, err := strconv.ParseUint(c.Param("term_id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid term ID"})
+		return nil, false
+	}
+
+	var term models.GlossaryTerm
+	if err := h.db.Where("id = ? AND glossary_id = ?", id, g.ID).First(&term).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Term not found"})
+		return nil, false
+	}
+	return &term, true
+}
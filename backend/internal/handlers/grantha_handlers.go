@@ -0,0 +1,181 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"tamil-proofreading-platform/backend/internal/middleware"
+	"tamil-proofreading-platform/backend/internal/models"
+	"tamil-proofreading-platform/backend/internal/util/auditlog"
+
+	"github.com/gin-gonic/gin"
+)
+
+type GranthaMappingRequest struct {
+	Word         string   `json:"word" binding:"required"`
+	Alternatives []string `json:"alternatives" binding:"required"`
+	Note         string   `json:"note"`
+	IsActive     *bool    `json:"is_active"`
+}
+
+// normalize trims fields and drops empty alternatives. It reports whether
+// the mapping is usable.
+func (r *GranthaMappingRequest) normalize() bool {
+	r.Word = strings.TrimSpace(r.Word)
+	r.Note = strings.TrimSpace(r.Note)
+	alternatives := r.Alternatives[:0]
+	for _, a := range r.Alternatives {
+		if a = strings.TrimSpace(a); a != "" && a != r.Word {
+			alternatives = append(alternatives, a)
+		}
+	}
+	r.Alternatives = alternatives
+	return r.Word != "" && len(r.Alternatives) > 0
+}
+
+// AdminGetGranthaMappings lists the pure Tamil mapping table
+// GET /api/v1/admin/grantha-mappings?q=
+func (h *Handlers) AdminGetGranthaMappings(c *gin.Context) {
+	query := h.db.Model(&models.GranthaMapping{})
+	if q := strings.TrimSpace(c.Query("q")); q != "" {
+		query = query.Where("word LIKE ?", "%"+q+"%")
+	}
+
+	var mappings []models.GranthaMapping
+	if err := query.Order("word ASC").Find(&mappings).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch mappings"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"mappings": mappings})
+}
+
+// AdminCreateGranthaMapping adds a mapping
+// POST /api/v1/admin/grantha-mappings
+func (h *Handlers) AdminCreateGranthaMapping(c *gin.Context) {
+	var req GranthaMappingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if !req.normalize() {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "word and at least one alternative are required"})
+		return
+	}
+
+	var count int64
+	h.db.Model(&models.GranthaMapping{}).Where("word = ?", req.Word).Count(&count)
+	if count > 0 {
+		c.JSON(http.StatusConflict, gin.H{"error": "A mapping for this word already exists"})
+		return
+	}
+
+	adminID, _ := middleware.GetUserFromContext(c)
+	mapping := models.GranthaMapping{
+		Word:         req.Word,
+		Alternatives: req.Alternatives,
+		Note:         req.Note,
+		IsActive:     req.IsActive == nil || *req.IsActive,
+		UpdatedBy:    &adminID,
+	}
+	if err := h.db.Create(&mapping).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create mapping"})
+		return
+	}
+	if !mapping.IsActive {
+		h.db.Model(&mapping).Update("is_active", false)
+	}
+	h.granthaService.Invalidate()
+
+	auditlog.Info(c, "grantha_mapping.created", map[string]any{
+		"mapping_id": mapping.ID,
+		"admin_id":   adminID,
+	})
+
+	c.JSON(http.StatusCreated, gin.H{"mapping": mapping})
+}
+
+// AdminUpdateGranthaMapping replaces a mapping
+// PUT /api/v1/admin/grantha-mappings/:id
+func (h *Handlers) AdminUpdateGranthaMapping(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid mapping ID"})
+		return
+	}
+
+	var mapping models.GranthaMapping
+	if err := h.db.First(&mapping, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Mapping not found"})
+		return
+	}
+
+	var req GranthaMappingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if !req.normalize() {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "word and at least one alternative are required"})
+		return
+	}
+	if req.Word != mapping.Word {
+		var count int64
+		h.db.Model(&models.GranthaMapping{}).Where("word = ? AND id <> ?", req.Word, mapping.ID).Count(&count)
+		if count > 0 {
+			c.JSON(http.StatusConflict, gin.H{"error": "A mapping for this word already exists"})
+			return
+		}
+	}
+
+	adminID, _ := middleware.GetUserFromContext(c)
+	mapping.Word = req.Word
+	mapping.Alternatives = req.Alternatives
+	mapping.Note = req.Note
+	if req.IsActive != nil {
+		mapping.IsActive = *req.IsActive
+	}
+	mapping.UpdatedBy = &adminID
+	if err := h.db.Save(&mapping).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update mapping"})
+		return
+	}
+	h.granthaService.Invalidate()
+
+	auditlog.Info(c, "grantha_mapping.updated", map[string]any{
+		"mapping_id": mapping.ID,
+		"admin_id":   adminID,
+	})
+
+	c.JSON(http.StatusOK, gin.H{"mapping": mapping})
+}
+
+// AdminDeleteGranthaMapping removes a mapping
+// DELETE /api/v1/admin/grantha-mappings/:id
+func (h *Handlers) AdminDeleteGranthaMapping(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid mapping ID"})
+		return
+	}
+
+	result := h.db.Delete(&models.GranthaMapping{}, id)
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete mapping"})
+		return
+	}
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Mapping not found"})
+		return
+	}
+	h.granthaService.Invalidate()
+
+	adminID, _ := middleware.GetUserFromContext(c)
+	auditlog.Info(c, "grantha_mapping.deleted", map[string]any{
+		"mapping_id": id,
+		"admin_id":   adminID,
+	})
+
+	c.JSON(http.StatusOK, gin.H{"message": "Mapping deleted"})
+}
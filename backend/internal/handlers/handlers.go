@@ -1,16 +1,30 @@
 package handlers
 
 import (
+        "context"
         "log"
         "time"
 
         "tamil-proofreading-platform/backend/internal/config"
         "tamil-proofreading-platform/backend/internal/models"
         "tamil-proofreading-platform/backend/internal/services/auth"
+        "tamil-proofreading-platform/backend/internal/services/batch"
+        "tamil-proofreading-platform/backend/internal/services/broker"
+        "tamil-proofreading-platform/backend/internal/services/codemix"
+        "tamil-proofreading-platform/backend/internal/services/document"
         "tamil-proofreading-platform/backend/internal/services/email"
+        "tamil-proofreading-platform/backend/internal/services/glossary"
+        "tamil-proofreading-platform/backend/internal/services/grantha"
+        "tamil-proofreading-platform/backend/internal/services/hunspell"
+        "tamil-proofreading-platform/backend/internal/services/library"
+        "tamil-proofreading-platform/backend/internal/services/live"
         "tamil-proofreading-platform/backend/internal/services/llm"
+        "tamil-proofreading-platform/backend/internal/services/moderation"
         "tamil-proofreading-platform/backend/internal/services/nlp"
         "tamil-proofreading-platform/backend/internal/services/payment"
+        "tamil-proofreading-platform/backend/internal/services/queue"
+        "tamil-proofreading-platform/backend/internal/services/search"
+        "tamil-proofreading-platform/backend/internal/services/webhooks"
 
         "gorm.io/gorm"
 )
@@ -23,7 +37,20 @@ type Handlers struct {
         nlpService     *nlp.TamilNLPService
         llmService     *llm.LLMService
         paymentService *payment.PaymentService
+        moderationService *moderation.ModerationService
+        hunspellGenerator *hunspell.Generator
+        glossaryService *glossary.GlossaryService
+        granthaService *grantha.GranthaService
+        codeMixService *codemix.CodeMixService
+        documentService *document.DocumentService
+        batchService   *batch.BatchService
+        jobQueue       *queue.JobQueue
+        webhookService *webhooks.WebhookService
+        liveService    *live.LiveService
+        searchService  *search.SearchService
+        libraryService *library.LibraryService
         streamHub      *submissionStreamHub
+        batchHub       *submissionStreamHub
 }
 
 func New(db *gorm.DB, cfg *config.Config) *Handlers {
@@ -53,9 +80,41 @@ func New(db *gorm.DB, cfg *config.Config) *Handlers {
                 nlpService:     nlpService,
                 llmService:     llmService,
                 paymentService: paymentService,
-                streamHub:      newSubmissionStreamHub(),
+                moderationService: moderation.NewModerationService(db),
+                hunspellGenerator: hunspell.NewGenerator(db),
+                glossaryService: glossary.NewGlossaryService(db),
+                granthaService: grantha.NewGranthaService(db),
+                codeMixService: codemix.NewCodeMixService(db, llmService),
+                documentService: document.NewDocumentService(db),
+                batchService:   batch.NewBatchService(db),
+                liveService:    live.NewLiveService(live.DefaultOptions),
+                searchService:  search.NewSearchService(db),
+                libraryService: library.NewLibraryService(db),
         }
 
+        // Streams go through Postgres so every instance hears every event,
+        // unless there is no database or a single instance was configured
+        var streamBroker broker.Broker = broker.NewMemoryBroker(broker.DefaultRetention)
+        if db != nil && cfg.StreamBroker != "memory" {
+                pgBroker := broker.NewPostgresBroker(db, cfg.DatabaseURL, broker.DefaultRetention)
+                pgBroker.Start(context.Background())
+                streamBroker = pgBroker
+        }
+        h.streamHub = newSubmissionStreamHub(streamBroker, "submission")
+        h.batchHub = newSubmissionStreamHub(streamBroker, "batch")
+
+        queueOptions := queue.DefaultOptions
+        if cfg.QueueWorkers > 0 {
+                queueOptions.Workers = cfg.QueueWorkers
+        }
+        h.jobQueue = queue.NewJobQueue(db, queueOptions, h.runProofreadJob, h.proofreadJobFailed)
+        h.jobQueue.Start(context.Background())
+
+        webhookOptions := webhooks.DefaultOptions
+        webhookOptions.AllowPrivate = cfg.WebhookAllowPrivate
+        h.webhookService = webhooks.NewWebhookService(db, webhookOptions)
+        h.webhookService.Start(context.Background())
+
         h.startArchiveCleanup()
 
         return h
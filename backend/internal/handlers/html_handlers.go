@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"net/http"
+
+	"tamil-proofreading-platform/backend/internal/models"
+	"tamil-proofreading-platform/backend/internal/services/htmltext"
+	"tamil-proofreading-platform/backend/internal/services/suggestions"
+	"tamil-proofreading-platform/backend/internal/util/htmlsanitize"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SuggestionHTMLPosition struct {
+	SuggestionID uint                `json:"suggestion_id"`
+	Positions    []htmltext.Position `json:"positions"`
+}
+
+// GetSubmissionHTML returns a rich-text submission's HTML with its
+// suggestions written into the text nodes, and where each suggestion sits in
+// the original markup
+// GET /api/v1/submissions/:id/html?include=all|accepted
+func (h *Handlers) GetSubmissionHTML(c *gin.Context) {
+	submission, ok := h.loadUserSubmission(c)
+	if !ok {
+		return
+	}
+	if submission.OriginalHTML == "" {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Submission has no HTML"})
+		return
+	}
+
+	include := c.DefaultQuery("include", "all")
+	if include != "all" && include != "accepted" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "include must be all or accepted"})
+		return
+	}
+
+	rows, err := suggestions.Load(h.db, *submission)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch suggestions"})
+		return
+	}
+
+	corrected, positions, applied, err := suggestionHTML(submission.OriginalHTML, rows, include == "accepted")
+	if err != nil {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "Failed to parse submission HTML"})
+		return
+	}
+
+	located := make([]SuggestionHTMLPosition, 0, len(rows))
+	for i, s := range rows {
+		if positions[i] != nil {
+			located = append(located, SuggestionHTMLPosition{SuggestionID: s.ID, Positions: positions[i]})
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"html":        corrected,
+		"applied":     applied,
+		"suggestions": located,
+	})
+}
+
+// suggestionHTML writes the suggestions that would be exported into source,
+// a submission's HTML, returning the corrected HTML, the number applied and
+// the position of each row in the original markup. Rows that cannot be
+// mapped to text nodes have nil positions.
+func suggestionHTML(source string, rows []models.SubmissionSuggestion, acceptedOnly bool) (string, [][]htmltext.Position, int, error) {
+	doc, err := htmltext.Parse(source)
+	if err != nil {
+		return "", nil, 0, err
+	}
+
+	positions := make([][]htmltext.Position, len(rows))
+	for i, s := range rows {
+		if s.Applicable() {
+			positions[i] = doc.Positions(s.StartIndex, s.EndIndex)
+		}
+	}
+
+	var edits []htmltext.Edit
+	for _, e := range fileEdits(rows, acceptedOnly, 0) {
+		edits = append(edits, htmltext.Edit{
+			Start:       e.Start,
+			End:         e.End,
+			Original:    e.Original,
+			Replacement: e.Replacement,
+		})
+	}
+	applied := doc.Apply(edits)
+
+	return htmlsanitize.Sanitize(doc.HTML()), positions, applied, nil
+}
@@ -0,0 +1,200 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	"tamil-proofreading-platform/backend/internal/models"
+	"tamil-proofreading-platform/backend/internal/services/queue"
+	"tamil-proofreading-platform/backend/internal/util/auditlog"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// runProofreadJob is the job queue's handler: it proofreads the job's
+// submission unless it is already finished, cancelled or deleted.
+func (h *Handlers) runProofreadJob(ctx context.Context, job models.Job) error {
+	var submission models.Submission
+	if err := h.db.First(&submission, job.SubmissionID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+	switch submission.Status {
+	case models.StatusCompleted, models.StatusFailed, models.StatusCancelled:
+		return nil
+	}
+
+	if err := h.processSubmission(ctx, submission); err != nil {
+		if errors.Is(err, errSubmissionNotProcessing) {
+			// Cancelled; the cancel already told the stream and batch
+			return nil
+		}
+		return err
+	}
+	submission.Status = models.StatusCompleted
+	h.submissionFinished(submission)
+	return nil
+}
+
+// proofreadJobFailed reports a failed attempt to the submission's stream.
+// The submission goes back to pending while a retry is due, and is marked
+// failed once the job is dead.
+func (h *Handlers) proofreadJobFailed(job models.Job, cause error, dead bool) {
+	var submission models.Submission
+	if err := h.db.Select("id", "user_id", "request_id", "word_count", "model_used", "created_at").First(&submission, job.SubmissionID).Error; err != nil {
+		return
+	}
+	requestID := submission.RequestID
+
+	if !dead {
+		if err := h.db.Model(&models.Submission{}).
+			Where("id = ? AND status = ?", submission.ID, models.StatusProcessing).
+			Update("status", models.StatusPending).Error; err != nil {
+			log.Printf("Error resetting submission %d to pending: %v", submission.ID, err)
+		}
+		h.streamHub.broadcast(submission.ID, submissionEvent{
+			Event: "status",
+			Data:  gin.H{"status": models.StatusPending, "retry_at": job.RunAt, "attempt": job.Attempts, "request_id": requestID},
+		})
+		return
+	}
+
+	if err := h.db.Model(&models.Submission{}).
+		Where("id = ?", submission.ID).
+		Updates(map[string]interface{}{
+			"status": models.StatusFailed,
+			"error":  cause.Error(),
+		}).Error; err != nil {
+		log.Printf("Error updating submission with error status: %v", err)
+	}
+
+	h.streamHub.broadcast(submission.ID, submissionEvent{
+		Event: "status",
+		Data:  gin.H{"status": models.StatusFailed, "request_id": requestID},
+	})
+	h.streamHub.broadcast(submission.ID, submissionEvent{
+		Event: "failure",
+		Data:  gin.H{"message": cause.Error(), "request_id": requestID},
+	})
+	h.streamHub.broadcast(submission.ID, submissionEvent{
+		Event: "end",
+		Data:  gin.H{"status": models.StatusFailed, "request_id": requestID},
+	})
+	submission.Status, submission.Error = models.StatusFailed, cause.Error()
+	h.submissionFinished(submission)
+}
+
+// submissionFinished is called once a submission has completed, failed or
+// been cancelled. It sends the matching webhook event and publishes the
+// progress of the batch the submission belongs to, if any.
+func (h *Handlers) submissionFinished(submission models.Submission) {
+	switch submission.Status {
+	case models.StatusCompleted:
+		h.publishWebhook(submission.UserID, models.EventSubmissionCompleted, submissionWebhookData(submission))
+	case models.StatusFailed:
+		h.publishWebhook(submission.UserID, models.EventSubmissionFailed, submissionWebhookData(submission))
+	case models.StatusCancelled:
+		h.publishWebhook(submission.UserID, models.EventSubmissionCancelled, submissionWebhookData(submission))
+	}
+
+	var item models.BatchItem
+	if err := h.db.Where("submission_id = ?", submission.ID).Limit(1).Find(&item).Error; err != nil || item.ID == 0 {
+		return
+	}
+	h.publishBatchProgress(item.BatchID, item)
+}
+
+// AdminGetJobs lists queued, running, done, cancelled or dead jobs, oldest first
+// GET /api/v1/admin/jobs?state=&limit=&offset=
+func (h *Handlers) AdminGetJobs(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if err != nil || limit <= 0 || limit > 200 {
+		limit = 50
+	}
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+
+	query := h.db.Model(&models.Job{})
+	if state := c.Query("state"); state != "" {
+		query = query.Where("state = ?", state)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch jobs"})
+		return
+	}
+	var jobs []models.Job
+	if err := query.Order("id ASC").Limit(limit).Offset(max(offset, 0)).Find(&jobs).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch jobs"})
+		return
+	}
+
+	var counts []struct {
+		State models.JobState `json:"state"`
+		Count int64           `json:"count"`
+	}
+	h.db.Model(&models.Job{}).Select("state, COUNT(*) AS count").Group("state").Scan(&counts)
+
+	c.JSON(http.StatusOK, gin.H{
+		"jobs":   jobs,
+		"total":  total,
+		"counts": counts,
+		"limit":  limit,
+		"offset": offset,
+	})
+}
+
+// AdminGetQueueStats reports queue depth and wait times by plan and the
+// users with the most unfinished jobs
+// GET /api/v1/admin/queue/stats?users=
+func (h *Handlers) AdminGetQueueStats(c *gin.Context) {
+	users, err := strconv.Atoi(c.DefaultQuery("users", "20"))
+	if err != nil || users <= 0 || users > 100 {
+		users = 20
+	}
+
+	stats, err := h.jobQueue.Stats(users)
+	if err != nil {
+		log.Printf("Error fetching queue stats: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch queue stats"})
+		return
+	}
+	c.JSON(http.StatusOK, stats)
+}
+
+// AdminRetryJob requeues a dead job with a fresh set of attempts
+// POST /api/v1/admin/jobs/:id/retry
+func (h *Handlers) AdminRetryJob(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid job ID"})
+		return
+	}
+
+	job, err := h.jobQueue.Retry(uint(id))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Job not found"})
+			return
+		}
+		if errors.Is(err, queue.ErrNotDead) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retry job"})
+		return
+	}
+
+	auditlog.Info(c, "job.retried", map[string]any{
+		"job_id":        job.ID,
+		"submission_id": job.SubmissionID,
+	})
+
+	c.JSON(http.StatusOK, gin.H{"job": job, "message": "Job requeued"})
+}
@@ -0,0 +1,377 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"tamil-proofreading-platform/backend/internal/middleware"
+	"tamil-proofreading-platform/backend/internal/models"
+	"tamil-proofreading-platform/backend/internal/services/library"
+	"tamil-proofreading-platform/backend/internal/util/auditlog"
+
+	"github.com/gin-gonic/gin"
+)
+
+type FolderRequest struct {
+	Name string `json:"name" binding:"required"`
+}
+
+type TagRequest struct {
+	Name  *string `json:"name"`
+	Color *string `json:"color"`
+}
+
+// UpdateSubmissionRequest edits how a submission is organized. Omitted
+// fields are left as they are; folder_id 0 takes the submission out of its
+// folder, and metadata and tags replace the current ones.
+type UpdateSubmissionRequest struct {
+	Title    *string          `json:"title"`
+	FolderID *uint            `json:"folder_id"`
+	Metadata *models.Metadata `json:"metadata"`
+	Tags     *[]string        `json:"tags"`
+}
+
+type BulkMoveRequest struct {
+	SubmissionIDs []uint `json:"submission_ids" binding:"required"`
+	// FolderID 0 takes the submissions out of their folders.
+	FolderID uint `json:"folder_id"`
+}
+
+type BulkTagRequest struct {
+	SubmissionIDs []uint   `json:"submission_ids" binding:"required"`
+	Add           []string `json:"add"`
+	Remove        []string `json:"remove"`
+}
+
+// GetFolders lists the user's folders with their submission counts
+// GET /api/v1/folders
+func (h *Handlers) GetFolders(c *gin.Context) {
+	userID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	folders, err := h.libraryService.Folders(userID)
+	if err != nil {
+		log.Printf("Error fetching folders for user %d: %v", userID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch folders"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"folders": folders})
+}
+
+// CreateFolder creates a folder
+// POST /api/v1/folders
+func (h *Handlers) CreateFolder(c *gin.Context) {
+	userID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var req FolderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	folder, err := h.libraryService.CreateFolder(userID, req.Name)
+	if err != nil {
+		respondLibraryError(c, err, "create folder")
+		return
+	}
+
+	auditlog.Info(c, "folder.created", map[string]any{"folder_id": folder.ID})
+	c.JSON(http.StatusCreated, gin.H{"folder": folder})
+}
+
+// RenameFolder renames a folder
+// PATCH /api/v1/folders/:id
+func (h *Handlers) RenameFolder(c *gin.Context) {
+	userID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+	folderID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid folder ID"})
+		return
+	}
+
+	var req FolderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	folder, err := h.libraryService.RenameFolder(userID, uint(folderID), req.Name)
+	if err != nil {
+		respondLibraryError(c, err, "rename folder")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"folder": folder})
+}
+
+// DeleteFolder deletes a folder. Its submissions are kept, unfiled.
+// DELETE /api/v1/folders/:id
+func (h *Handlers) DeleteFolder(c *gin.Context) {
+	userID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+	folderID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid folder ID"})
+		return
+	}
+
+	if err := h.libraryService.DeleteFolder(userID, uint(folderID)); err != nil {
+		respondLibraryError(c, err, "delete folder")
+		return
+	}
+
+	auditlog.Info(c, "folder.deleted", map[string]any{"folder_id": folderID})
+	c.JSON(http.StatusOK, gin.H{"message": "Folder deleted"})
+}
+
+// GetTags lists the user's tags with their submission counts
+// GET /api/v1/tags
+func (h *Handlers) GetTags(c *gin.Context) {
+	userID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	tags, err := h.libraryService.Tags(userID)
+	if err != nil {
+		log.Printf("Error fetching tags for user %d: %v", userID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch tags"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"tags": tags})
+}
+
+// CreateTag creates a tag
+// POST /api/v1/tags
+func (h *Handlers) CreateTag(c *gin.Context) {
+	userID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var req TagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.Name == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "name is required"})
+		return
+	}
+	color := ""
+	if req.Color != nil {
+		color = *req.Color
+	}
+	tag, err := h.libraryService.CreateTag(userID, *req.Name, color)
+	if err != nil {
+		respondLibraryError(c, err, "create tag")
+		return
+	}
+
+	auditlog.Info(c, "tag.created", map[string]any{"tag_id": tag.ID})
+	c.JSON(http.StatusCreated, gin.H{"tag": tag})
+}
+
+// UpdateTag renames or recolors a tag
+// PATCH /api/v1/tags/:id
+func (h *Handlers) UpdateTag(c *gin.Context) {
+	userID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+	tagID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid tag ID"})
+		return
+	}
+
+	var req TagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	tag, err := h.libraryService.UpdateTag(userID, uint(tagID), req.Name, req.Color)
+	if err != nil {
+		respondLibraryError(c, err, "update tag")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"tag": tag})
+}
+
+// DeleteTag deletes a tag and removes it from every submission
+// DELETE /api/v1/tags/:id
+func (h *Handlers) DeleteTag(c *gin.Context) {
+	userID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+	tagID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid tag ID"})
+		return
+	}
+
+	if err := h.libraryService.DeleteTag(userID, uint(tagID)); err != nil {
+		respondLibraryError(c, err, "delete tag")
+		return
+	}
+
+	auditlog.Info(c, "tag.deleted", map[string]any{"tag_id": tagID})
+	c.JSON(http.StatusOK, gin.H{"message": "Tag deleted"})
+}
+
+// UpdateSubmission sets a submission's title, folder, metadata and tags
+// PATCH /api/v1/submissions/:id
+func (h *Handlers) UpdateSubmission(c *gin.Context) {
+	submission, ok := h.findUserSubmission(c)
+	if !ok {
+		return
+	}
+
+	var req UpdateSubmissionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := h.libraryService.Update(submission.UserID, submission, library.Changes{
+		Title:    req.Title,
+		FolderID: req.FolderID,
+		Metadata: req.Metadata,
+		Tags:     req.Tags,
+	}); err != nil {
+		respondLibraryError(c, err, "update submission")
+		return
+	}
+
+	var updated models.Submission
+	if err := h.db.Preload("Tags").First(&updated, submission.ID).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch submission"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"submission": updated})
+}
+
+// BulkMoveSubmissions files submissions in a folder, or unfiles them
+// POST /api/v1/submissions/bulk/move
+func (h *Handlers) BulkMoveSubmissions(c *gin.Context) {
+	userID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var req BulkMoveRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	result, err := h.libraryService.Move(userID, req.SubmissionIDs, req.FolderID)
+	if err != nil {
+		respondLibraryError(c, err, "move submissions")
+		return
+	}
+
+	auditlog.Info(c, "submissions.moved", map[string]any{"folder_id": req.FolderID, "count": len(result.Updated)})
+	c.JSON(http.StatusOK, result)
+}
+
+// BulkTagSubmissions adds and removes tags on submissions
+// POST /api/v1/submissions/bulk/tags
+func (h *Handlers) BulkTagSubmissions(c *gin.Context) {
+	userID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var req BulkTagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if len(req.Add) == 0 && len(req.Remove) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "add or remove is required"})
+		return
+	}
+	result, err := h.libraryService.Retag(userID, req.SubmissionIDs, req.Add, req.Remove)
+	if err != nil {
+		respondLibraryError(c, err, "tag submissions")
+		return
+	}
+
+	auditlog.Info(c, "submissions.tagged", map[string]any{"added": req.Add, "removed": req.Remove, "count": len(result.Updated)})
+	c.JSON(http.StatusOK, result)
+}
+
+// libraryFilter reads the folder_id ("none" for unfiled), tag (repeated
+// or comma-separated) and meta[key] filters, writing an error response
+// when one is invalid.
+func libraryFilter(c *gin.Context) (library.Filter, bool) {
+	var filter library.Filter
+	switch raw := c.Query("folder_id"); raw {
+	case "":
+	case "none":
+		none := uint(0)
+		filter.FolderID = &none
+	default:
+		folderID, err := strconv.ParseUint(raw, 10, 32)
+		if err != nil || folderID == 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "folder_id must be a folder ID or none"})
+			return filter, false
+		}
+		id := uint(folderID)
+		filter.FolderID = &id
+	}
+	for _, raw := range c.QueryArray("tag") {
+		for _, tag := range strings.Split(raw, ",") {
+			if tag = strings.TrimSpace(tag); tag != "" {
+				filter.Tags = append(filter.Tags, tag)
+			}
+		}
+	}
+	if meta := c.QueryMap("meta"); len(meta) > 0 {
+		filter.Metadata = meta
+	}
+	return filter, true
+}
+
+func respondLibraryError(c *gin.Context, err error, action string) {
+	switch {
+	case errors.Is(err, library.ErrFolderNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Folder not found"})
+	case errors.Is(err, library.ErrTagNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Tag not found"})
+	case errors.Is(err, library.ErrNameTaken):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, library.ErrInvalidName),
+		errors.Is(err, library.ErrInvalidColor),
+		errors.Is(err, library.ErrInvalidTitle),
+		errors.Is(err, library.ErrInvalidMetadata),
+		errors.Is(err, library.ErrTooManyFolders),
+		errors.Is(err, library.ErrTooManyTags),
+		errors.Is(err, library.ErrTooManyOnOne),
+		errors.Is(err, library.ErrTooManySubmitted):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		log.Printf("Error trying to %s: %v", action, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to " + action})
+	}
+}
@@ -0,0 +1,185 @@
+package handlers
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"tamil-proofreading-platform/backend/internal/middleware"
+	"tamil-proofreading-platform/backend/internal/models"
+	"tamil-proofreading-platform/backend/internal/services/live"
+	"tamil-proofreading-platform/backend/internal/services/llm"
+	"tamil-proofreading-platform/backend/internal/services/style"
+	"tamil-proofreading-platform/backend/internal/util/auditlog"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/net/websocket"
+)
+
+const (
+	// liveMaxMessageBytes bounds one message from the editor.
+	liveMaxMessageBytes = 256 << 10
+	// liveIdleTimeout closes a connection that sends nothing, not even a
+	// ping, for this long.
+	liveIdleTimeout = 2 * time.Minute
+	// liveWriteTimeout bounds sending one event.
+	liveWriteTimeout = 10 * time.Second
+)
+
+// liveMessage is a message from the editor: an "update" with the
+// paragraphs added or changed and the IDs of those deleted, or a "ping".
+type liveMessage struct {
+	Type       string           `json:"type"`
+	Seq        int64            `json:"seq"`
+	Paragraphs []live.Paragraph `json:"paragraphs"`
+	Removed    []string         `json:"removed"`
+}
+
+// LiveProofread proofreads a document over a WebSocket while it is edited
+// GET /api/v1/live?style_profile=&pure_tamil=
+func (h *Handlers) LiveProofread(c *gin.Context) {
+	userID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+	if !strings.EqualFold(c.GetHeader("Upgrade"), "websocket") {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Expected a WebSocket upgrade"})
+		return
+	}
+
+	requestID := middleware.GetRequestID(c)
+	if requestID == "" {
+		requestID = strconv.FormatInt(time.Now().UnixNano(), 36)
+	}
+	profile, err := style.Resolve(h.db, userID, c.Query("style_profile"))
+	if err != nil {
+		h.respondStyleError(c, err)
+		return
+	}
+	pureTamil := c.Query("pure_tamil") == "true"
+
+	// The dictionary and style are fixed for the connection; the cache
+	// scope covers them so an edited dictionary is not served stale results
+	dict := h.loadUserDictionary(userID, requestID)
+	opts := proofreadOptions(dict, profile, pureTamil)
+	scope := sha256.Sum256([]byte(fmt.Sprintf("%d\x00%t\x00%s\x00%s", userID, pureTamil, opts.StyleGuide, strings.Join(opts.ProtectedTerms, "\x00"))))
+	proofread := func(ctx context.Context, text string) ([]llm.Suggestion, error) {
+		result, err := h.llmService.ProofreadTextWithOptions(ctx, text, len(strings.Fields(text)), false, requestID, opts)
+		if err != nil {
+			return nil, err
+		}
+		dict.Apply(result)
+		h.applyHouseRules(userID, profile, pureTamil, dict, text, requestID, result)
+		return result.Suggestions, nil
+	}
+
+	session, err := h.liveService.NewSession(userID, hex.EncodeToString(scope[:]), proofread)
+	if err != nil {
+		if errors.Is(err, live.ErrTooManySessions) {
+			c.JSON(http.StatusTooManyRequests, gin.H{"error": "Too many live proofreading connections open"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to start live proofreading"})
+		return
+	}
+	defer session.Close()
+
+	server := websocket.Server{
+		Handshake: h.checkWebSocketOrigin,
+		Handler: func(ws *websocket.Conn) {
+			h.serveLive(ws, session)
+		},
+	}
+	server.ServeHTTP(c.Writer, c.Request)
+
+	usage := session.Usage()
+	if usage.Words > 0 {
+		if err := h.db.Create(&models.Usage{
+			UserID:    userID,
+			WordCount: usage.Words,
+			ModelUsed: h.selectModel(usage.Words),
+			Date:      time.Now(),
+		}).Error; err != nil {
+			log.Printf("Error creating usage record: %v", err)
+		}
+	}
+	auditlog.Info(c, "live.session_closed", map[string]any{
+		"request_id": requestID,
+		"calls":      usage.Calls,
+		"words":      usage.Words,
+		"cache_hits": usage.CacheHits,
+	})
+}
+
+// checkWebSocketOrigin accepts the same browser origins as CORS. Clients
+// that send no Origin, which are not browsers, are accepted too.
+func (h *Handlers) checkWebSocketOrigin(config *websocket.Config, r *http.Request) error {
+	origin := r.Header.Get("Origin")
+	if origin == "" ||
+		strings.HasPrefix(origin, "http://localhost:") ||
+		strings.HasPrefix(origin, "http://127.0.0.1:") ||
+		origin == h.cfg.FrontendURL {
+		return nil
+	}
+	return fmt.Errorf("origin %q not allowed", origin)
+}
+
+// serveLive relays the editor's messages to the session and the session's
+// events back until either side closes.
+func (h *Handlers) serveLive(ws *websocket.Conn, session *live.Session) {
+	defer ws.Close()
+	ws.MaxPayloadBytes = liveMaxMessageBytes
+
+	go func() {
+		for event := range session.Events() {
+			ws.SetWriteDeadline(time.Now().Add(liveWriteTimeout))
+			if err := websocket.JSON.Send(ws, event); err != nil {
+				session.Close()
+				break
+			}
+		}
+		if errors.Is(session.Err(), live.ErrSlowClient) {
+			ws.SetWriteDeadline(time.Now().Add(liveWriteTimeout))
+			websocket.JSON.Send(ws, live.Event{Type: live.EventError, Code: live.CodeSlowClient, Message: live.ErrSlowClient.Error()})
+		}
+		// Unblocks the read loop below
+		ws.Close()
+	}()
+
+	for {
+		ws.SetReadDeadline(time.Now().Add(liveIdleTimeout))
+		var data []byte
+		if err := websocket.Message.Receive(ws, &data); err != nil {
+			if errors.Is(err, websocket.ErrFrameTooLarge) {
+				session.Notify(live.Event{Type: live.EventError, Code: live.CodeInvalidMessage,
+					Message: fmt.Sprintf("messages may be at most %d bytes", liveMaxMessageBytes)})
+				continue
+			}
+			return
+		}
+
+		var msg liveMessage
+		if err := json.Unmarshal(data, &msg); err != nil {
+			session.Notify(live.Event{Type: live.EventError, Code: live.CodeInvalidMessage, Message: "Invalid JSON"})
+			continue
+		}
+		switch msg.Type {
+		case "update":
+			session.Update(msg.Seq, msg.Paragraphs, msg.Removed)
+		case "ping":
+			session.Notify(live.Event{Type: live.EventPong, Seq: msg.Seq})
+		default:
+			session.Notify(live.Event{Type: live.EventError, Seq: msg.Seq, Code: live.CodeInvalidMessage,
+				Message: fmt.Sprintf("unknown message type %q", msg.Type)})
+		}
+	}
+}
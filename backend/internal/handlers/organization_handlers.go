@@ -0,0 +1,464 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"tamil-proofreading-platform/backend/internal/middleware"
+	"tamil-proofreading-platform/backend/internal/models"
+	"tamil-proofreading-platform/backend/internal/util/auditlog"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// orgInviteTTL is how long an organization invitation can be accepted.
+const orgInviteTTL = 14 * 24 * time.Hour
+
+type CreateOrganizationRequest struct {
+	Name string `json:"name" binding:"required"`
+}
+
+type InviteOrganizationMemberRequest struct {
+	Email string         `json:"email" binding:"required,email"`
+	Role  models.OrgRole `json:"role"`
+}
+
+// CreateOrganization creates an organization owned by the current user
+// POST /api/v1/organizations
+func (h *Handlers) CreateOrganization(c *gin.Context) {
+	userID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var req CreateOrganizationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "name is required"})
+		return
+	}
+
+	if member, err := h.glossaryService.Membership(userID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check membership"})
+		return
+	} else if member != nil {
+		c.JSON(http.StatusConflict, gin.H{"error": "You already belong to an organization"})
+		return
+	}
+
+	org := models.Organization{Name: name, CreatedBy: userID}
+	err = h.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&org).Error; err != nil {
+			return err
+		}
+		return tx.Create(&models.OrganizationMember{
+			OrganizationID: org.ID,
+			UserID:         userID,
+			Role:           models.OrgOwner,
+		}).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create organization"})
+		return
+	}
+
+	auditlog.Info(c, "organization.created", map[string]any{
+		"organization_id": org.ID,
+		"user_id":         userID,
+	})
+
+	c.JSON(http.StatusCreated, gin.H{"organization": org})
+}
+
+// GetMyOrganization returns the current user's organization and members,
+// and pending invitations for owners and editors
+// GET /api/v1/organizations/me
+func (h *Handlers) GetMyOrganization(c *gin.Context) {
+	member, ok := h.requireOrgMember(c, false)
+	if !ok {
+		return
+	}
+
+	var org models.Organization
+	if err := h.db.Preload("Members.User").First(&org, member.OrganizationID).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch organization"})
+		return
+	}
+
+	response := gin.H{"organization": org, "role": member.Role}
+	if member.CanManage() {
+		var invites []models.OrganizationInvite
+		if err := h.db.Preload("User").
+			Where("organization_id = ? AND expires_at > ?", member.OrganizationID, time.Now()).
+			Order("created_at DESC").
+			Find(&invites).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch invitations"})
+			return
+		}
+		response["invites"] = invites
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// InviteOrganizationMember invites a user to the organization by email. The
+// response is the same whether or not the email has an account, so the
+// endpoint cannot be used to find out who is registered.
+// POST /api/v1/organizations/invites
+func (h *Handlers) InviteOrganizationMember(c *gin.Context) {
+	member, ok := h.requireOrgMember(c, true)
+	if !ok {
+		return
+	}
+
+	var req InviteOrganizationMemberRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	switch req.Role {
+	case "":
+		req.Role = models.OrgMember
+	case models.OrgMember, models.OrgEditor:
+	case models.OrgOwner:
+		if member.Role != models.OrgOwner {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Only owners can invite owners"})
+			return
+		}
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "role must be owner, editor or member"})
+		return
+	}
+
+	sent := gin.H{"message": "If that email belongs to an account, an invitation has been sent"}
+
+	var user models.User
+	err := h.db.Where("LOWER(email) = ?", strings.ToLower(strings.TrimSpace(req.Email))).First(&user).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusAccepted, sent)
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to send invitation"})
+		return
+	}
+	if user.ID == member.UserID {
+		c.JSON(http.StatusAccepted, sent)
+		return
+	}
+
+	// A repeated invite replaces the earlier one, refreshing its role and
+	// expiry.
+	invite := models.OrganizationInvite{
+		OrganizationID: member.OrganizationID,
+		UserID:         user.ID,
+		Role:           req.Role,
+		InvitedBy:      member.UserID,
+		ExpiresAt:      time.Now().Add(orgInviteTTL),
+	}
+	if err := h.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "organization_id"}, {Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"role", "invited_by", "expires_at", "created_at"}),
+	}).Create(&invite).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to send invitation"})
+		return
+	}
+
+	if h.emailService.IsConfigured() {
+		var org models.Organization
+		if err := h.db.First(&org, member.OrganizationID).Error; err == nil {
+			go func(to, name string) {
+				if err := h.emailService.SendOrganizationInvite(to, name); err != nil {
+					log.Printf("Failed to email organization invite to user %d: %v", user.ID, err)
+				}
+			}(user.Email, org.Name)
+		}
+	}
+
+	auditlog.Info(c, "organization.member_invited", map[string]any{
+		"organization_id": member.OrganizationID,
+		"member_user_id":  user.ID,
+		"role":            req.Role,
+	})
+
+	c.JSON(http.StatusAccepted, sent)
+}
+
+// RevokeOrganizationInvite withdraws a pending invitation
+// DELETE /api/v1/organizations/invites/:id
+func (h *Handlers) RevokeOrganizationInvite(c *gin.Context) {
+	member, ok := h.requireOrgMember(c, true)
+	if !ok {
+		return
+	}
+
+	result := h.db.Where("id = ? AND organization_id = ?", c.Param("id"), member.OrganizationID).
+		Delete(&models.OrganizationInvite{})
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to revoke invitation"})
+		return
+	}
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Invitation not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Invitation revoked"})
+}
+
+// GetMyOrganizationInvites lists the current user's pending invitations
+// GET /api/v1/organization-invites
+func (h *Handlers) GetMyOrganizationInvites(c *gin.Context) {
+	userID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var invites []models.OrganizationInvite
+	if err := h.db.Preload("Organization").
+		Where("user_id = ? AND expires_at > ?", userID, time.Now()).
+		Order("created_at DESC").
+		Find(&invites).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch invitations"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"invites": invites})
+}
+
+// AcceptOrganizationInvite joins the organization that sent the invitation
+// POST /api/v1/organization-invites/:id/accept
+func (h *Handlers) AcceptOrganizationInvite(c *gin.Context) {
+	userID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var invite models.OrganizationInvite
+	err = h.db.Where("id = ? AND user_id = ? AND expires_at > ?", c.Param("id"), userID, time.Now()).First(&invite).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Invitation not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch invitation"})
+		return
+	}
+
+	if existing, err := h.glossaryService.Membership(userID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check membership"})
+		return
+	} else if existing != nil {
+		c.JSON(http.StatusConflict, gin.H{"error": "Leave your current organization before joining another"})
+		return
+	}
+
+	newMember := models.OrganizationMember{
+		OrganizationID: invite.OrganizationID,
+		UserID:         userID,
+		Role:           invite.Role,
+	}
+	err = h.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&newMember).Error; err != nil {
+			return err
+		}
+		return tx.Where("user_id = ?", userID).Delete(&models.OrganizationInvite{}).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to join organization"})
+		return
+	}
+
+	auditlog.Info(c, "organization.member_added", map[string]any{
+		"organization_id": invite.OrganizationID,
+		"member_user_id":  userID,
+		"role":            invite.Role,
+		"invited_by":      invite.InvitedBy,
+	})
+
+	c.JSON(http.StatusCreated, gin.H{"member": newMember})
+}
+
+// DeclineOrganizationInvite discards an invitation
+// DELETE /api/v1/organization-invites/:id
+func (h *Handlers) DeclineOrganizationInvite(c *gin.Context) {
+	userID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	result := h.db.Where("id = ? AND user_id = ?", c.Param("id"), userID).Delete(&models.OrganizationInvite{})
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to decline invitation"})
+		return
+	}
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Invitation not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Invitation declined"})
+}
+
+// LeaveOrganization removes the current user from their organization. The
+// last owner cannot leave while other members remain; a sole member leaving
+// deletes the organization.
+// DELETE /api/v1/organizations/members/me
+func (h *Handlers) LeaveOrganization(c *gin.Context) {
+	member, ok := h.requireOrgMember(c, false)
+	if !ok {
+		return
+	}
+
+	var others, owners int64
+	if err := h.db.Model(&models.OrganizationMember{}).
+		Where("organization_id = ? AND user_id <> ?", member.OrganizationID, member.UserID).
+		Count(&others).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to leave organization"})
+		return
+	}
+	if member.Role == models.OrgOwner && others > 0 {
+		if err := h.db.Model(&models.OrganizationMember{}).
+			Where("organization_id = ? AND role = ?", member.OrganizationID, models.OrgOwner).
+			Count(&owners).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to leave organization"})
+			return
+		}
+		if owners <= 1 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Make another member an owner before leaving"})
+			return
+		}
+	}
+
+	err := h.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(member).Error; err != nil {
+			return err
+		}
+		if others > 0 {
+			return nil
+		}
+		if err := tx.Where("organization_id = ?", member.OrganizationID).Delete(&models.OrganizationInvite{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.Organization{}, member.OrganizationID).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to leave organization"})
+		return
+	}
+
+	auditlog.Info(c, "organization.member_left", map[string]any{
+		"organization_id": member.OrganizationID,
+		"member_user_id":  member.UserID,
+	})
+
+	c.JSON(http.StatusOK, gin.H{"message": "You have left the organization"})
+}
+
+// RemoveOrganizationMember removes a user from the organization
+// DELETE /api/v1/organizations/members/:user_id
+func (h *Handlers) RemoveOrganizationMember(c *gin.Context) {
+	member, ok := h.requireOrgMember(c, true)
+	if !ok {
+		return
+	}
+
+	targetID, err := strconv.ParseUint(c.Param("user_id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	var target models.OrganizationMember
+	if err := h.db.Where("organization_id = ? AND user_id = ?", member.OrganizationID, targetID).First(&target).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Member not found"})
+		return
+	}
+	if target.Role == models.OrgOwner {
+		var owners int64
+		h.db.Model(&models.OrganizationMember{}).
+			Where("organization_id = ? AND role = ?", member.OrganizationID, models.OrgOwner).
+			Count(&owners)
+		if owners <= 1 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "An organization needs at least one owner"})
+			return
+		}
+		if member.Role != models.OrgOwner {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Only owners can remove owners"})
+			return
+		}
+	}
+
+	if err := h.db.Delete(&target).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to remove member"})
+		return
+	}
+
+	auditlog.Info(c, "organization.member_removed", map[string]any{
+		"organization_id": member.OrganizationID,
+		"member_user_id":  targetID,
+	})
+
+	c.JSON(http.StatusOK, gin.H{"message": "Member removed"})
+}
+
+// requireOrgMember loads the current user's membership, writing an error
+// response when there is none or when manage is set and the user may not
+// edit organization settings.
+func (h *Handlers) requireOrgMember(c *gin.Context, manage bool) (*models.OrganizationMember, bool) {
+	userID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return nil, false
+	}
+
+	member, err := h.glossaryService.Membership(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check membership"})
+		return nil, false
+	}
+	if member == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "You do not belong to an organization"})
+		return nil, false
+	}
+	if manage && !member.CanManage() {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Organization owner or editor role required"})
+		return nil, false
+	}
+	return member, true
+}
+
+// loadOrgGlossary loads a glossary from the member's organization.
+func (h *Handlers) loadOrgGlossary(c *gin.Context, member *models.OrganizationMember) (*models.Glossary, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid glossary ID"})
+		return nil, false
+	}
+
+	var glossary models.Glossary
+	err = h.db.Where("id = ? AND organization_id = ?", id, member.OrganizationID).First(&glossary).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Glossary not found"})
+		return nil, false
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch glossary"})
+		return nil, false
+	}
+	return &glossary, true
+}
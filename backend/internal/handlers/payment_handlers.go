@@ -187,10 +187,15 @@ func (h *Handlers) StripeWebhook(c *gin.Context) {
 			return
 		}
 
-		// Update payment status
+		// Update payment status, publishing only the first delivery of
+		// a Stripe event that is retried
 		payment, err := h.paymentService.GetPaymentByGatewayID(paymentIntent.ID)
 		if err == nil {
-			h.paymentService.UpdatePaymentStatus(payment.ID, models.PaymentStatusCompleted, paymentIntent.ID)
+			changed, err := h.paymentService.ChangePaymentStatus(payment.ID, models.PaymentStatusCompleted, paymentIntent.ID)
+			if err == nil && changed {
+				payment.Status = models.PaymentStatusCompleted
+				h.publishWebhook(payment.UserID, models.EventPaymentCompleted, paymentWebhookData(*payment))
+			}
 		}
 
 	case "payment_intent.payment_failed":
@@ -201,10 +206,15 @@ func (h *Handlers) StripeWebhook(c *gin.Context) {
 			return
 		}
 
-		// Update payment status
+		// Update payment status, publishing only the first delivery of
+		// a Stripe event that is retried
 		payment, err := h.paymentService.GetPaymentByGatewayID(paymentIntent.ID)
 		if err == nil {
-			h.paymentService.UpdatePaymentStatus(payment.ID, models.PaymentStatusFailed, paymentIntent.ID)
+			changed, err := h.paymentService.ChangePaymentStatus(payment.ID, models.PaymentStatusFailed, paymentIntent.ID)
+			if err == nil && changed {
+				payment.Status = models.PaymentStatusFailed
+				h.publishWebhook(payment.UserID, models.EventPaymentFailed, paymentWebhookData(*payment))
+			}
 		}
 	}
 
@@ -0,0 +1,209 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+	"strings"
+
+	"tamil-proofreading-platform/backend/internal/models"
+	"tamil-proofreading-platform/backend/internal/services/llm"
+	"tamil-proofreading-platform/backend/internal/services/style"
+	"tamil-proofreading-platform/backend/internal/services/suggestions"
+	"tamil-proofreading-platform/backend/internal/util/auditlog"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// ReprocessRequest overrides how a submission is proofread again. Omitted
+// fields keep the submission's current settings.
+type ReprocessRequest struct {
+	// Model is one of llm.Models, or "auto" to choose by length again.
+	Model               *string `json:"model"`
+	StyleProfile        *string `json:"style_profile"`
+	PureTamil           *bool   `json:"pure_tamil"`
+	IncludeAlternatives *bool   `json:"include_alternatives"`
+}
+
+// CancelSubmission stops a pending or processing submission. Proofreading
+// in flight is abandoned and its results are never stored.
+// POST /api/v1/submissions/:id/cancel
+func (h *Handlers) CancelSubmission(c *gin.Context) {
+	submission, ok := h.findUserSubmission(c)
+	if !ok {
+		return
+	}
+
+	var cancelled bool
+	err := h.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.Submission{}).
+			Where("id = ? AND status IN ?", submission.ID, []models.SubmissionStatus{models.StatusPending, models.StatusProcessing}).
+			Update("status", models.StatusCancelled)
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+		cancelled = true
+		_, err := h.jobQueue.Cancel(tx, submission.ID)
+		return err
+	})
+	if err != nil {
+		log.Printf("Error cancelling submission %d: %v", submission.ID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to cancel submission"})
+		return
+	}
+	if !cancelled {
+		c.JSON(http.StatusConflict, gin.H{"error": "Only pending or processing submissions can be cancelled"})
+		return
+	}
+
+	h.streamHub.broadcast(submission.ID, submissionEvent{
+		Event: "status",
+		Data:  gin.H{"status": models.StatusCancelled, "request_id": submission.RequestID},
+	})
+	h.streamHub.broadcast(submission.ID, submissionEvent{
+		Event: "end",
+		Data:  gin.H{"status": models.StatusCancelled, "request_id": submission.RequestID},
+	})
+	submission.Status = models.StatusCancelled
+	h.submissionFinished(*submission)
+
+	auditlog.Info(c, "submission.cancelled", map[string]any{
+		"submission_id": submission.ID,
+		"request_id":    submission.RequestID,
+	})
+
+	c.JSON(http.StatusOK, gin.H{"submission": submission, "message": "Submission cancelled"})
+}
+
+// maxReprocesses is how many times one submission can be reprocessed.
+const maxReprocesses = 5
+
+// ReprocessSubmission proofreads a finished, failed or cancelled submission
+// again, optionally with a different model or style. Its previous
+// suggestions and decisions are discarded. Each reprocess calls the model
+// again, so its words are recorded as usage again, and a submission can be
+// reprocessed at most maxReprocesses times.
+// POST /api/v1/submissions/:id/reprocess
+func (h *Handlers) ReprocessSubmission(c *gin.Context) {
+	submission, ok := h.findUserSubmission(c)
+	if !ok {
+		return
+	}
+
+	var req ReprocessRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	switch submission.Status {
+	case models.StatusCompleted, models.StatusFailed, models.StatusCancelled:
+	default:
+		c.JSON(http.StatusConflict, gin.H{"error": "Submission is still being processed"})
+		return
+	}
+	if submission.ReprocessCount >= maxReprocesses {
+		c.JSON(http.StatusTooManyRequests, gin.H{"error": "This submission has been reprocessed the maximum number of times"})
+		return
+	}
+
+	updates := map[string]interface{}{
+		"status":          models.StatusPending,
+		"error":           "",
+		"proofread_text":  "",
+		"suggestions":     "[]",
+		"alternatives":    "[]",
+		"final_text":      "",
+		"code_mix_count":  0,
+		"processing_time": 0,
+		"reprocess_count": gorm.Expr("reprocess_count + 1"),
+	}
+	if req.Model != nil {
+		model := strings.TrimSpace(*req.Model)
+		switch {
+		case model == "auto" || model == "":
+			updates["model"] = ""
+		case llm.ValidModel(model):
+			_, plan, err := h.paymentService.CheckSubscriptionStatus(submission.UserID)
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check subscription"})
+				return
+			}
+			if !llm.PlanAllowsModel(*plan, model) {
+				c.JSON(http.StatusForbidden, gin.H{"error": "Your plan does not include " + model})
+				return
+			}
+			updates["model"] = model
+		default:
+			c.JSON(http.StatusBadRequest, gin.H{"error": "model must be auto or one of " + strings.Join(llm.Models, ", ")})
+			return
+		}
+	}
+	if req.StyleProfile != nil {
+		profile, err := style.Resolve(h.db, submission.UserID, strings.TrimSpace(*req.StyleProfile))
+		if err != nil {
+			h.respondStyleError(c, err)
+			return
+		}
+		updates["style_profile"] = ""
+		if profile != nil {
+			updates["style_profile"] = profile.Key
+		}
+	}
+	if req.PureTamil != nil {
+		updates["pure_tamil"] = *req.PureTamil
+	}
+	if req.IncludeAlternatives != nil {
+		updates["include_alternatives"] = *req.IncludeAlternatives
+	}
+
+	var reset bool
+	err := h.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.Submission{}).
+			Where("id = ? AND status IN ? AND reprocess_count < ?", submission.ID,
+				[]models.SubmissionStatus{models.StatusCompleted, models.StatusFailed, models.StatusCancelled}, maxReprocesses).
+			Updates(updates)
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+		reset = true
+		if err := suggestions.Store(tx, submission.ID, nil); err != nil {
+			return err
+		}
+		return h.jobQueue.Enqueue(tx, submission.ID)
+	})
+	if err != nil {
+		log.Printf("Error reprocessing submission %d: %v", submission.ID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to reprocess submission"})
+		return
+	}
+	if !reset {
+		c.JSON(http.StatusConflict, gin.H{"error": "Submission is still being processed"})
+		return
+	}
+
+	if err := h.db.First(submission, submission.ID).Error; err != nil {
+		log.Printf("Error reloading submission %d: %v", submission.ID, err)
+	}
+	h.streamHub.broadcast(submission.ID, submissionEvent{
+		Event: "status",
+		Data:  gin.H{"status": models.StatusPending, "request_id": submission.RequestID},
+	})
+	go h.recordUsage(*submission)
+
+	auditlog.Info(c, "submission.reprocessed", map[string]any{
+		"submission_id":   submission.ID,
+		"request_id":      submission.RequestID,
+		"model":           submission.Model,
+		"style_profile":   submission.StyleProfile,
+		"reprocess_count": submission.ReprocessCount,
+	})
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"submission": submission,
+		"message":    "Submission queued for proofreading",
+		"request_id": submission.RequestID,
+	})
+}
@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"tamil-proofreading-platform/backend/internal/middleware"
+	"tamil-proofreading-platform/backend/internal/models"
+	"tamil-proofreading-platform/backend/internal/services/search"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SearchSubmissions searches the user's submissions by original and
+// proofread text, with filters, highlighted snippets and cursor paging
+// GET /api/v1/submissions/search?q=&status=&model=&from=&to=&archived=&folder_id=&tag=&meta[key]=&sort=&limit=&cursor=
+func (h *Handlers) SearchSubmissions(c *gin.Context) {
+	userID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var ok bool
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit <= 0 || limit > 100 {
+		limit = 20
+	}
+	query := search.Query{
+		Text:     c.Query("q"),
+		Model:    c.Query("model"),
+		Archived: c.DefaultQuery("archived", search.ArchivedExclude),
+		Sort:     c.Query("sort"),
+		Limit:    limit,
+		Cursor:   c.Query("cursor"),
+	}
+	switch query.Archived {
+	case search.ArchivedExclude, search.ArchivedOnly, search.ArchivedAll:
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "archived must be true, false or all"})
+		return
+	}
+	if query.Sort != "" && query.Sort != search.SortRelevance && query.Sort != search.SortNewest {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "sort must be relevance or newest"})
+		return
+	}
+	if raw := c.Query("status"); raw != "" {
+		for _, status := range strings.Split(raw, ",") {
+			status := models.SubmissionStatus(strings.TrimSpace(status))
+			switch status {
+			case models.StatusPending, models.StatusProcessing, models.StatusCompleted, models.StatusFailed, models.StatusCancelled:
+				query.Statuses = append(query.Statuses, status)
+			default:
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown status: " + string(status)})
+				return
+			}
+		}
+	}
+	if query.Filter, ok = libraryFilter(c); !ok {
+		return
+	}
+	if query.From, err = parseSearchDate(c.Query("from"), false); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "from must be a date (YYYY-MM-DD) or RFC 3339 time"})
+		return
+	}
+	if query.To, err = parseSearchDate(c.Query("to"), true); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "to must be a date (YYYY-MM-DD) or RFC 3339 time"})
+		return
+	}
+
+	page, err := h.searchService.Search(userID, query)
+	if err != nil {
+		switch {
+		case errors.Is(err, search.ErrInvalidCursor):
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid cursor"})
+		case errors.Is(err, search.ErrTooManyTerms):
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Search for at most " + strconv.Itoa(search.MaxTerms) + " terms"})
+		default:
+			log.Printf("Error searching submissions for user %d: %v", userID, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to search submissions"})
+		}
+		return
+	}
+	c.JSON(http.StatusOK, page)
+}
+
+// parseSearchDate parses a date or RFC 3339 time. A date given as the end
+// of a range includes the whole day.
+func parseSearchDate(raw string, end bool) (*time.Time, error) {
+	if raw == "" {
+		return nil, nil
+	}
+	if t, err := time.Parse(time.RFC3339, raw); err == nil {
+		return &t, nil
+	}
+	t, err := time.Parse(time.DateOnly, raw)
+	if err != nil {
+		return nil, err
+	}
+	if end {
+		t = t.AddDate(0, 0, 1)
+	}
+	return &t, nil
+}
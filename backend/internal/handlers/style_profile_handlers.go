@@ -0,0 +1,207 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"tamil-proofreading-platform/backend/internal/middleware"
+	"tamil-proofreading-platform/backend/internal/models"
+	"tamil-proofreading-platform/backend/internal/services/style"
+	"tamil-proofreading-platform/backend/internal/util/auditlog"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetStyleProfiles lists the built-in profiles and the organization's own
+// GET /api/v1/style-profiles
+func (h *Handlers) GetStyleProfiles(c *gin.Context) {
+	userID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	member, err := h.glossaryService.Membership(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check membership"})
+		return
+	}
+
+	var orgID *uint
+	defaultKey := ""
+	if member != nil {
+		orgID = &member.OrganizationID
+		var org models.Organization
+		if err := h.db.First(&org, member.OrganizationID).Error; err == nil {
+			defaultKey = org.DefaultStyleProfile
+		}
+	}
+
+	profiles, err := style.List(h.db, orgID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch style profiles"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"profiles": profiles, "default": defaultKey})
+}
+
+// CreateStyleProfile adds a custom profile for the organization
+// POST /api/v1/style-profiles
+func (h *Handlers) CreateStyleProfile(c *gin.Context) {
+	member, ok := h.requireOrgMember(c, true)
+	if !ok {
+		return
+	}
+
+	var profile models.StyleProfile
+	if err := c.ShouldBindJSON(&profile); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	profile.ID = 0
+	profile.OrganizationID = &member.OrganizationID
+	if err := style.Validate(&profile); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if _, err := style.Lookup(h.db, profile.OrganizationID, profile.Key); err == nil {
+		c.JSON(http.StatusConflict, gin.H{"error": "A style profile with this key already exists"})
+		return
+	}
+
+	if err := h.db.Create(&profile).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create style profile"})
+		return
+	}
+
+	auditlog.Info(c, "style_profile.created", map[string]any{
+		"organization_id": member.OrganizationID,
+		"key":             profile.Key,
+	})
+
+	c.JSON(http.StatusCreated, gin.H{"profile": profile})
+}
+
+// UpdateStyleProfile replaces a custom profile's settings
+// PUT /api/v1/style-profiles/:key
+func (h *Handlers) UpdateStyleProfile(c *gin.Context) {
+	member, ok := h.requireOrgMember(c, true)
+	if !ok {
+		return
+	}
+	existing, ok := h.loadCustomStyleProfile(c, member)
+	if !ok {
+		return
+	}
+
+	var profile models.StyleProfile
+	if err := c.ShouldBindJSON(&profile); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	profile.ID = existing.ID
+	profile.Key = existing.Key
+	profile.OrganizationID = existing.OrganizationID
+	profile.CreatedAt = existing.CreatedAt
+	if err := style.Validate(&profile); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.db.Save(&profile).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update style profile"})
+		return
+	}
+
+	auditlog.Info(c, "style_profile.updated", map[string]any{
+		"organization_id": member.OrganizationID,
+		"key":             profile.Key,
+	})
+
+	c.JSON(http.StatusOK, gin.H{"profile": profile})
+}
+
+// DeleteStyleProfile removes a custom profile
+// DELETE /api/v1/style-profiles/:key
+func (h *Handlers) DeleteStyleProfile(c *gin.Context) {
+	member, ok := h.requireOrgMember(c, true)
+	if !ok {
+		return
+	}
+	existing, ok := h.loadCustomStyleProfile(c, member)
+	if !ok {
+		return
+	}
+
+	if err := h.db.Delete(existing).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete style profile"})
+		return
+	}
+	// Submissions fall back to no profile rather than failing.
+	h.db.Model(&models.Organization{}).
+		Where("id = ? AND default_style_profile = ?", member.OrganizationID, existing.Key).
+		Update("default_style_profile", "")
+
+	auditlog.Info(c, "style_profile.deleted", map[string]any{
+		"organization_id": member.OrganizationID,
+		"key":             existing.Key,
+	})
+
+	c.JSON(http.StatusOK, gin.H{"message": "Style profile deleted"})
+}
+
+// SetOrganizationStyleProfile sets the default profile for the organization's
+// submissions; an empty key clears it.
+// PUT /api/v1/organizations/style-profile
+func (h *Handlers) SetOrganizationStyleProfile(c *gin.Context) {
+	member, ok := h.requireOrgMember(c, true)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		StyleProfile string `json:"style_profile"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	key := strings.TrimSpace(req.StyleProfile)
+	if key != "" {
+		if _, err := style.Lookup(h.db, &member.OrganizationID, key); err != nil {
+			h.respondStyleError(c, err)
+			return
+		}
+	}
+
+	if err := h.db.Model(&models.Organization{}).Where("id = ?", member.OrganizationID).
+		Update("default_style_profile", key).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update organization"})
+		return
+	}
+
+	auditlog.Info(c, "organization.style_profile_set", map[string]any{
+		"organization_id": member.OrganizationID,
+		"style_profile":   key,
+	})
+
+	c.JSON(http.StatusOK, gin.H{"style_profile": key})
+}
+
+func (h *Handlers) loadCustomStyleProfile(c *gin.Context, member *models.OrganizationMember) (*models.StyleProfile, bool) {
+	key := c.Param("key")
+	if style.IsBuiltin(key) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Built-in style profiles cannot be changed"})
+		return nil, false
+	}
+
+	var profile models.StyleProfile
+	if err := h.db.Where("organization_id = ? AND key = ?", member.OrganizationID, key).First(&profile).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Style profile not found"})
+		return nil, false
+	}
+	return &profile, true
+}
@@ -4,18 +4,25 @@ import (
         "context"
         "encoding/json"
         "errors"
-        "html"
+        "fmt"
         "io"
         "log"
         "net/http"
-        "regexp"
         "strconv"
         "strings"
         "time"
 
         "tamil-proofreading-platform/backend/internal/middleware"
         "tamil-proofreading-platform/backend/internal/models"
+        "tamil-proofreading-platform/backend/internal/services/broker"
+        "tamil-proofreading-platform/backend/internal/services/htmltext"
+        "tamil-proofreading-platform/backend/internal/services/llm"
+        "tamil-proofreading-platform/backend/internal/services/rules"
+        "tamil-proofreading-platform/backend/internal/services/style"
+        "tamil-proofreading-platform/backend/internal/services/suggestions"
+        "tamil-proofreading-platform/backend/internal/services/userdict"
         "tamil-proofreading-platform/backend/internal/util/auditlog"
+        "tamil-proofreading-platform/backend/internal/util/htmlsanitize"
 
         "github.com/gin-gonic/gin"
         "gorm.io/gorm"
@@ -26,30 +33,11 @@ type SubmitTextRequest struct {
         HTML                string `json:"html"`
         IncludeAlternatives bool   `json:"include_alternatives"`
         SaveDraft           *bool  `json:"save_draft"`
-}
-
-var htmlTagRegex = regexp.MustCompile("<[^>]+>")
-var scriptTagRegex = regexp.MustCompile(`(?is)<script.*?>.*?</script>`)
-var eventAttrRegex = regexp.MustCompile(`(?i)\s+on[a-z]+\s*=\s*(".*?"|'.*?')`)
-var javascriptProtoRegex = regexp.MustCompile(`(?i)javascript:`)
-
-func stripHTML(input string) string {
-        if strings.TrimSpace(input) == "" {
-                return ""
-        }
-        noTags := htmlTagRegex.ReplaceAllString(input, " ")
-        collapsed := strings.Join(strings.Fields(noTags), " ")
-        return html.UnescapeString(collapsed)
-}
-
-func sanitizeHTML(input string) string {
-        if strings.TrimSpace(input) == "" {
-                return ""
-        }
-        safe := scriptTagRegex.ReplaceAllString(input, "")
-        safe = eventAttrRegex.ReplaceAllString(safe, "")
-        safe = javascriptProtoRegex.ReplaceAllString(safe, "")
-        return safe
+        StyleProfile        string `json:"style_profile"`
+        PureTamil           bool   `json:"pure_tamil"`
+        // DocumentID adds the text as a new revision of an existing document;
+        // otherwise a new document is started.
+        DocumentID *uint `json:"document_id"`
 }
 
 // SubmitText handles text submission for proofreading
@@ -69,30 +57,11 @@ func (h *Handlers) SubmitText(c *gin.Context) {
                 return
         }
 
-        // Sanitize and validate input
-        req.Text = strings.TrimSpace(req.Text)
-        req.HTML = sanitizeHTML(strings.TrimSpace(req.HTML))
-
-        if req.Text == "" && req.HTML != "" {
-                req.Text = stripHTML(req.HTML)
-        }
-
-        if req.Text == "" {
-                c.JSON(http.StatusBadRequest, gin.H{"error": "Text cannot be empty"})
-                return
-        }
-
-        if len(req.Text) > 100000 {
-                c.JSON(http.StatusBadRequest, gin.H{"error": "Text is too long (max 100KB)"})
-                return
-        }
-
-        // Count words
-        wordCount := h.nlpService.CountWords(req.Text)
-        if wordCount == 0 {
-                c.JSON(http.StatusBadRequest, gin.H{"error": "No valid words found in text"})
+        text, safeHTML, wordCount, ok := h.prepareSubmissionText(c, req.Text, req.HTML)
+        if !ok {
                 return
         }
+        req.Text, req.HTML = text, safeHTML
 
         saveDraft := true
         if req.SaveDraft != nil {
@@ -101,20 +70,48 @@ func (h *Handlers) SubmitText(c *gin.Context) {
 
         // For inline analysis (demo/homepage), no auth required
         if !saveDraft {
-                result, err := h.llmService.ProofreadText(c.Request.Context(), req.Text, wordCount, req.IncludeAlternatives, requestID)
+                // Signed-in users still get their personal dictionary,
+                // organization glossary and style profiles applied
+                var dict *userdict.Dictionary
+                var profile *models.StyleProfile
+                var styleErr error
+                userID, authErr := middleware.GetUserFromContext(c)
+                if authErr == nil {
+                        dict = h.loadUserDictionary(userID, requestID)
+                        profile, styleErr = style.Resolve(h.db, userID, req.StyleProfile)
+                } else if req.StyleProfile != "" {
+                        profile, styleErr = style.Lookup(h.db, nil, req.StyleProfile)
+                }
+                if styleErr != nil {
+                        h.respondStyleError(c, styleErr)
+                        return
+                }
+
+                result, err := h.llmService.ProofreadTextWithOptions(c.Request.Context(), req.Text, wordCount, req.IncludeAlternatives, requestID, proofreadOptions(dict, profile, req.PureTamil))
                 if err != nil {
                         c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "request_id": requestID})
                         return
                 }
+                dict.Apply(result)
+                codeMixCount := h.applyHouseRules(userID, profile, req.PureTamil, dict, req.Text, requestID, result)
                 auditlog.Info(c, "submission.inline_completed", map[string]any{
                         "request_id": requestID,
                         "word_count": wordCount,
                 })
-                c.JSON(http.StatusOK, gin.H{
-                        "request_id": requestID,
-                        "result":     result,
-                        "message":    "Proofreading completed",
-                })
+                response := gin.H{
+                        "request_id":     requestID,
+                        "result":         result,
+                        "code_mix_count": codeMixCount,
+                        "message":        "Proofreading completed",
+                }
+                if req.HTML != "" {
+                        rows := suggestions.Build(0, req.Text, result.Suggestions)
+                        if corrected, positions, _, err := suggestionHTML(req.HTML, rows, false); err == nil {
+                                response["html"] = corrected
+                                response["html_positions"] = positions
+                        }
+                }
+                c.JSON(http.StatusOK, response)
                 return
         }
 
@@ -125,26 +122,33 @@ func (h *Handlers) SubmitText(c *gin.Context) {
                 return
         }
 
-        // Determine model to use
-        modelType := h.selectModel(wordCount)
+        profile, err := style.Resolve(h.db, userID, req.StyleProfile)
+        if err != nil {
+                h.respondStyleError(c, err)
+                return
+        }
+        styleKey := ""
+        if profile != nil {
+                styleKey = profile.Key
+        }
 
-        // Create submission record with pending status first
-        submission := &models.Submission{
-                UserID:              userID,
-                OriginalText:        req.Text,
-                OriginalHTML:        req.HTML,
-                RequestID:           requestID,
-                WordCount:           wordCount,
-                ModelUsed:           modelType,
-                Status:              models.StatusPending,
-                Cost:                0,
-                Suggestions:         "[]",
-                Alternatives:        "[]",
-                IncludeAlternatives: req.IncludeAlternatives,
+        doc := models.Document{UserID: userID}
+        if req.DocumentID != nil {
+                existing, err := h.documentService.Get(userID, *req.DocumentID)
+                if err != nil {
+                        respondDocumentError(c, err)
+                        return
+                }
+                doc = *existing
         }
 
-        // Save submission to database
-        if err := h.db.Create(submission).Error; err != nil {
+        submission := h.newSubmission(userID, requestID, req.Text, req.HTML, wordCount, req.IncludeAlternatives, styleKey, req.PureTamil)
+        revision := &models.DocumentRevision{
+                Text:      req.Text,
+                HTML:      req.HTML,
+                WordCount: wordCount,
+        }
+        if err := h.documentService.AddRevision(&doc, revision, submission); err != nil {
                 log.Printf("Error creating submission: %v", err)
                 c.JSON(http.StatusInternalServerError, gin.H{
                         "error":   "Failed to create submission",
@@ -153,62 +157,156 @@ func (h *Handlers) SubmitText(c *gin.Context) {
                 return
         }
 
-        // Verify submission was created
-        if submission.ID == 0 {
-                c.JSON(http.StatusInternalServerError, gin.H{
-                        "error": "Failed to create submission - no ID returned",
-                })
-                return
+        h.startSubmission(c, submission)
+
+        // Return success response immediately with the created submission record
+        c.JSON(http.StatusAccepted, gin.H{
+                "submission": submission,
+                "document":   doc,
+                "revision":   revision.Number,
+                "message":    "Submission received, proofreading started...",
+                "request_id": requestID,
+        })
+}
+
+// prepareSubmissionText sanitizes and validates submitted text, writing an
+// error response when it cannot be proofread
+func (h *Handlers) prepareSubmissionText(c *gin.Context, text, rawHTML string) (string, string, int, bool) {
+        text, safeHTML, wordCount, err := h.cleanSubmissionText(text, rawHTML)
+        if err != nil {
+                c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+                return "", "", 0, false
+        }
+        return text, safeHTML, wordCount, true
+}
+
+// cleanSubmissionText sanitizes and validates submitted text, returning the
+// text to proofread, its sanitized HTML and its word count. The error
+// explains why the text cannot be proofread.
+func (h *Handlers) cleanSubmissionText(text, rawHTML string) (string, string, int, error) {
+        text = strings.TrimSpace(text)
+        safeHTML := htmlsanitize.Sanitize(rawHTML)
+
+        // Rich text is proofread as the text of its nodes, so suggestion
+        // offsets can be mapped back into the markup
+        if safeHTML != "" {
+                doc, err := htmltext.Parse(safeHTML)
+                if err != nil {
+                        return "", "", 0, errors.New("Invalid HTML")
+                }
+                if strings.TrimSpace(doc.Text) != "" {
+                        text, safeHTML = doc.Text, doc.HTML()
+                } else {
+                        safeHTML = ""
+                }
+        }
+
+        if text == "" {
+                return "", "", 0, errors.New("Text cannot be empty")
+        }
+
+        if len(text) > 100000 {
+                return "", "", 0, errors.New("Text is too long (max 100KB)")
         }
 
+        // Count words
+        wordCount := h.nlpService.CountWords(text)
+        if wordCount == 0 {
+                return "", "", 0, errors.New("No valid words found in text")
+        }
+
+        return text, safeHTML, wordCount, nil
+}
+
+// newSubmission builds a pending submission; it is saved by the caller
+func (h *Handlers) newSubmission(userID uint, requestID, text, safeHTML string, wordCount int, includeAlternatives bool, styleKey string, pureTamil bool) *models.Submission {
+        return &models.Submission{
+                UserID:              userID,
+                OriginalText:        text,
+                OriginalHTML:        safeHTML,
+                RequestID:           requestID,
+                WordCount:           wordCount,
+                ModelUsed:           h.selectModel(wordCount),
+                Status:              models.StatusPending,
+                Cost:                0,
+                Suggestions:         "[]",
+                Alternatives:        "[]",
+                IncludeAlternatives: includeAlternatives,
+                StyleProfile:        styleKey,
+                PureTamil:           pureTamil,
+        }
+}
+
+// startSubmission starts proofreading a saved submission in the background
+// and records its usage
+func (h *Handlers) startSubmission(c *gin.Context, submission *models.Submission) {
         auditlog.Info(c, "submission.enqueued", map[string]any{
                 "submission_id": submission.ID,
-                "request_id":    requestID,
-                "word_count":    wordCount,
+                "request_id":    submission.RequestID,
+                "word_count":    submission.WordCount,
         })
 
-        // Start proofreading process immediately in background
-        go h.processSubmission(context.Background(), submission.ID, requestID, req.Text, wordCount, modelType, req.IncludeAlternatives)
+        // Queue proofreading; if this fails the sweeper queues it later
+        if err := h.jobQueue.Enqueue(h.db, submission.ID); err != nil {
+                log.Printf("Error queueing submission %d: %v", submission.ID, err)
+        }
 
         // Record usage asynchronously (non-blocking)
-        go func() {
-                usage := &models.Usage{
-                        UserID:       userID,
-                        WordCount:    wordCount,
-                        ModelUsed:    modelType,
-                        SubmissionID: &submission.ID,
-                        Date:         time.Now(),
-                }
-                if err := h.db.Create(usage).Error; err != nil {
-                        log.Printf("Error creating usage record: %v", err)
-                        // Don't fail submission if usage tracking fails
-                }
-        }()
+        go h.recordUsage(*submission)
+}
 
-        // Return success response immediately with the created submission record
-        c.JSON(http.StatusAccepted, gin.H{
-                "submission": submission,
-                "message":    "Submission received, proofreading started...",
-                "request_id": requestID,
-        })
+// recordUsage records the words of saved submissions against their users'
+// usage. Failures are logged; they never fail the submission.
+func (h *Handlers) recordUsage(submissions ...models.Submission) {
+        usage := make([]models.Usage, 0, len(submissions))
+        for i := range submissions {
+                usage = append(usage, models.Usage{
+                        UserID:       submissions[i].UserID,
+                        WordCount:    submissions[i].WordCount,
+                        ModelUsed:    submissions[i].ModelUsed,
+                        SubmissionID: &submissions[i].ID,
+                        Date:         time.Now(),
+                })
+        }
+        if len(usage) == 0 {
+                return
+        }
+        if err := h.db.Create(&usage).Error; err != nil {
+                log.Printf("Error creating usage record: %v", err)
+        }
 }
 
-// processSubmission processes the text submission asynchronously
-func (h *Handlers) processSubmission(ctx context.Context, submissionID uint, requestID, text string, wordCount int, modelType models.ModelType, includeAlternatives bool) {
+// errSubmissionNotProcessing is returned by processSubmission when the
+// submission was cancelled before or while it was proofread.
+var errSubmissionNotProcessing = errors.New("submission is no longer being processed")
+
+// processSubmission proofreads a submission and stores the results. It is
+// run by the job queue; an error leaves the submission to be retried.
+func (h *Handlers) processSubmission(ctx context.Context, submission models.Submission) error {
+        submissionID := submission.ID
+        requestID := submission.RequestID
+        wordCount := submission.WordCount
+        modelType := submission.ModelUsed
+
         log.Printf("Starting proofreading for submission ID: %d (request_id=%s)", submissionID, requestID)
         auditlog.LogStandalone(auditlog.LevelInfo, "submission.processing_started", requestID, map[string]any{
                 "submission_id": submissionID,
                 "word_count":    wordCount,
                 "model":         modelType,
         })
-        defer h.streamHub.close(submissionID)
 
-        // Update status to processing
-        if err := h.db.Model(&models.Submission{}).
-                Where("id = ?", submissionID).
-                Update("status", models.StatusProcessing).Error; err != nil {
-                log.Printf("Error updating submission status to processing: %v", err)
-                return
+        // Update status to processing, unless the submission was cancelled
+        // while it waited
+        started := h.db.Model(&models.Submission{}).
+                Where("id = ? AND status IN ?", submissionID, []models.SubmissionStatus{models.StatusPending, models.StatusProcessing}).
+                Update("status", models.StatusProcessing)
+        if started.Error != nil {
+                log.Printf("Error updating submission status to processing: %v", started.Error)
+                return started.Error
+        }
+        if started.RowsAffected == 0 {
+                log.Printf("Submission %d is no longer pending, skipping (request_id=%s)", submissionID, requestID)
+                return errSubmissionNotProcessing
         }
 
         h.streamHub.broadcast(submissionID, submissionEvent{
@@ -216,39 +314,27 @@ func (h *Handlers) processSubmission(ctx context.Context, submissionID uint, req
                 Data:  gin.H{"status": models.StatusProcessing},
         })
 
-        // Process with LLM service
-        result, err := h.llmService.ProofreadText(ctx, text, wordCount, includeAlternatives, requestID)
+        // Process with LLM service, protecting the user's dictionary words
+        // and following the submission's style profile
+        dict := h.loadUserDictionary(submission.UserID, requestID)
+        profile := h.loadStyleProfile(submission, requestID)
+        opts := proofreadOptions(dict, profile, submission.PureTamil)
+        opts.Model = submission.Model
+        result, err := h.llmService.ProofreadTextWithOptions(ctx, submission.OriginalText, wordCount, submission.IncludeAlternatives, requestID, opts)
         if err != nil {
                 log.Printf("Error processing submission %d (request_id=%s): %v", submissionID, requestID, err)
                 auditlog.LogStandalone(auditlog.LevelWarn, "submission.processing_failed", requestID, map[string]any{
                         "submission_id": submissionID,
                         "error":         err.Error(),
                 })
+                return err
+        }
 
-                // Update submission with error
-                if updateErr := h.db.Model(&models.Submission{}).
-                        Where("id = ?", submissionID).
-                        Updates(map[string]interface{}{
-                                "status": models.StatusFailed,
-                                "error":  err.Error(),
-                        }).Error; updateErr != nil {
-                        log.Printf("Error updating submission with error status: %v", updateErr)
-                }
-
-                h.streamHub.broadcast(submissionID, submissionEvent{
-                        Event: "status",
-                        Data:  gin.H{"status": models.StatusFailed, "request_id": requestID},
-                })
-                h.streamHub.broadcast(submissionID, submissionEvent{
-                        Event: "failure",
-                        Data:  gin.H{"message": err.Error(), "request_id": requestID},
-                })
-                h.streamHub.broadcast(submissionID, submissionEvent{
-                        Event: "end",
-                        Data:  gin.H{"status": models.StatusFailed, "request_id": requestID},
-                })
-                return
+        if dropped := dict.Apply(result); dropped > 0 {
+                log.Printf("Dropped %d suggestions matching user dictionary (submission=%d, request_id=%s)", dropped, submissionID, requestID)
         }
+        codeMixCount := h.applyHouseRules(submission.UserID, profile, submission.PureTamil, dict, submission.OriginalText, requestID, result)
+        h.codeMixService.LearnInBackground(fmt.Sprintf("submission=%d, request_id=%s", submissionID, requestID), submission.OriginalText, dict.Words())
 
         // Serialize suggestions to JSON
         suggestionsJSON := "[]"
@@ -276,13 +362,32 @@ func (h *Handlers) processSubmission(ctx context.Context, submissionID uint, req
                 "suggestions":     suggestionsJSON,
                 "alternatives":    alternativesJSON,
                 "processing_time": result.ProcessingTime,
+                "code_mix_count":  codeMixCount,
+                "final_text":      submission.OriginalText,
+        }
+
+        // Results are only stored while the submission is still processing,
+        // so a cancel that lands mid-flight wins
+        rows := suggestions.Build(submissionID, submission.OriginalText, result.Suggestions)
+        err = h.db.Transaction(func(tx *gorm.DB) error {
+                stored := tx.Model(&models.Submission{}).
+                        Where("id = ? AND status = ?", submissionID, models.StatusProcessing).
+                        Updates(updates)
+                if stored.Error != nil {
+                        return stored.Error
+                }
+                if stored.RowsAffected == 0 {
+                        return errSubmissionNotProcessing
+                }
+                return suggestions.Store(tx, submissionID, rows)
+        })
+        if errors.Is(err, errSubmissionNotProcessing) {
+                log.Printf("Submission %d was cancelled, discarding results (request_id=%s)", submissionID, requestID)
+                return err
         }
-
-        if err := h.db.Model(&models.Submission{}).
-                Where("id = ?", submissionID).
-                Updates(updates).Error; err != nil {
+        if err != nil {
                 log.Printf("Error updating submission with results: %v", err)
-                return
+                return err
         }
 
         var updated models.Submission
@@ -308,6 +413,93 @@ func (h *Handlers) processSubmission(ctx context.Context, submissionID uint, req
         auditlog.LogStandalone(auditlog.LevelInfo, "submission.processing_completed", requestID, map[string]any{
                 "submission_id": submissionID,
         })
+        return nil
+}
+
+// loadUserDictionary loads a user's personal dictionary, logging and
+// continuing without it if the lookup fails.
+func (h *Handlers) loadUserDictionary(userID uint, requestID string) *userdict.Dictionary {
+        dict, err := userdict.Load(h.db, userID)
+        if err != nil {
+                log.Printf("Error loading user dictionary for user %d (request_id=%s): %v", userID, requestID, err)
+                return nil
+        }
+        return dict
+}
+
+// loadStyleProfile loads the style profile stored on a submission, logging
+// and continuing without it if it no longer exists.
+func (h *Handlers) loadStyleProfile(submission models.Submission, requestID string) *models.StyleProfile {
+        if submission.StyleProfile == "" {
+                return nil
+        }
+        profile, err := style.Resolve(h.db, submission.UserID, submission.StyleProfile)
+        if err != nil {
+                log.Printf("Error loading style profile %q (request_id=%s): %v", submission.StyleProfile, requestID, err)
+                return nil
+        }
+        return profile
+}
+
+func (h *Handlers) respondStyleError(c *gin.Context, err error) {
+        if errors.Is(err, style.ErrUnknownProfile) {
+                c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown style profile"})
+                return
+        }
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load style profile"})
+}
+
+// applyHouseRules runs the deterministic checks for a user's organization
+// glossary, grantha policy, code-mixing and style profile and merges their
+// suggestions into the LLM result. userID is 0 for anonymous requests. It
+// returns the number of code-mixed English words found.
+func (h *Handlers) applyHouseRules(userID uint, profile *models.StyleProfile, pureTamil bool, dict *userdict.Dictionary, text, requestID string, result *llm.ProofreadResult) int {
+        var checkers []rules.Checker
+        if userID != 0 {
+                terms, err := h.glossaryService.TermsForUser(userID)
+                if err != nil {
+                        log.Printf("Error loading glossary for user %d (request_id=%s): %v", userID, requestID, err)
+                } else if checker := rules.NewTerminologyChecker(terms); !checker.Empty() {
+                        checkers = append(checkers, checker)
+                }
+        }
+        if policy := style.GranthaPolicy(profile, pureTamil); policy != models.PolicyAllow {
+                mappings, err := h.granthaService.Mappings()
+                if err != nil {
+                        log.Printf("Error loading grantha mappings (request_id=%s): %v", requestID, err)
+                } else {
+                        checkers = append(checkers, rules.NewGranthaChecker(mappings, policy == models.PolicyAvoid))
+                }
+        }
+
+        codeMixCount := 0
+        if mappings, err := h.codeMixService.Mappings(); err != nil {
+                log.Printf("Error loading code-mix mappings (request_id=%s): %v", requestID, err)
+        } else {
+                checker := rules.NewCodeMixChecker(mappings, dict.Words(), style.FlagUnmappedCodeMix(profile))
+                codeMixCount = len(checker.Detect(text))
+                checkers = append(checkers, checker)
+        }
+        checkers = append(checkers, style.Checkers(profile)...)
+
+        // The user's ignore rules apply to rule suggestions too.
+        found := rules.Run(text, checkers...)
+        kept := found[:0]
+        for _, s := range found {
+                if !dict.Ignores(s) {
+                        kept = append(kept, s)
+                }
+        }
+        rules.Merge(result, kept)
+        return codeMixCount
+}
+
+// proofreadOptions builds the LLM options for a user's dictionary and style
+func proofreadOptions(dict *userdict.Dictionary, profile *models.StyleProfile, pureTamil bool) llm.ProofreadOptions {
+        return llm.ProofreadOptions{
+                ProtectedTerms: dict.Words(),
+                StyleGuide:     style.PromptGuide(profile, pureTamil),
+        }
 }
 
 // selectModel determines which model to use based on word count
@@ -375,8 +567,14 @@ func (h *Handlers) GetSubmissions(c *gin.Context) {
                 offset = 0
         }
 
-        if err := h.db.Where("user_id = ?", userID).
+        filter, ok := libraryFilter(c)
+        if !ok {
+                return
+        }
+
+        if err := filter.Apply(h.db.Where("user_id = ?", userID), userID).
                 Where("archived = ?", false).
+                Preload("Tags").
                 Order("created_at DESC").
                 Limit(limit).
                 Offset(offset).
@@ -408,6 +606,7 @@ func (h *Handlers) GetSubmission(c *gin.Context) {
 
         var submission models.Submission
         if err := h.db.Where("id = ? AND user_id = ?", submissionID, userID).
+                Preload("Tags").
                 First(&submission).Error; err != nil {
                 if errors.Is(err, gorm.ErrRecordNotFound) {
                         c.JSON(http.StatusNotFound, gin.H{"error": "Submission not found"})
@@ -486,8 +685,14 @@ func (h *Handlers) GetArchivedSubmissions(c *gin.Context) {
                 log.Printf("archive cleanup error: %v", err)
         }
 
+        filter, ok := libraryFilter(c)
+        if !ok {
+                return
+        }
+
         var submissions []models.Submission
-        if err := h.db.Where("user_id = ? AND archived = ?", userID, true).
+        if err := filter.Apply(h.db.Where("user_id = ? AND archived = ?", userID, true), userID).
+                Preload("Tags").
                 Order("archived_at DESC").
                 Find(&submissions).Error; err != nil {
                 c.JSON(http.StatusInternalServerError, gin.H{
@@ -504,7 +709,9 @@ func (h *Handlers) GetArchivedSubmissions(c *gin.Context) {
         })
 }
 
-// StreamSubmission streams submission updates using Server-Sent Events
+// StreamSubmission streams submission updates using Server-Sent Events.
+// Published events carry an ID; a client that reconnects with
+// Last-Event-ID first receives the events it missed.
 func (h *Handlers) StreamSubmission(c *gin.Context) {
         userID, err := middleware.GetUserFromContext(c)
         if err != nil {
@@ -520,6 +727,10 @@ func (h *Handlers) StreamSubmission(c *gin.Context) {
         }
         submissionID := uint(submissionIDUint64)
 
+        // Listen before taking the snapshot so no update falls between them
+        listener, unsubscribe := h.streamHub.register(submissionID)
+        defer unsubscribe()
+
         var submission models.Submission
         if err := h.db.Where("id = ? AND user_id = ?", submissionID, userID).First(&submission).Error; err != nil {
                 if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -541,10 +752,24 @@ func (h *Handlers) StreamSubmission(c *gin.Context) {
                 return
         }
 
-        listener, unsubscribe := h.streamHub.register(submissionID)
-        defer unsubscribe()
+        // Replay what a reconnecting client missed
+        sent := lastEventID(c)
+        if sent > 0 {
+                missed, err := h.streamHub.replay(submissionID, sent)
+                if err != nil {
+                        log.Printf("Error replaying events of submission %d: %v", submissionID, err)
+                }
+                for _, msg := range missed {
+                        writeStreamEvent(c, msg)
+                        sent = msg.ID
+                        if msg.Event == broker.EndEvent {
+                                flusher.Flush()
+                                return
+                        }
+                }
+        }
 
-        // Send initial snapshot
+        // Send the current state, which also covers events too old to replay
         payload := gin.H{"status": submission.Status, "request_id": submission.RequestID}
         c.SSEvent("status", payload)
         if submission.Status == models.StatusCompleted {
@@ -561,19 +786,28 @@ func (h *Handlers) StreamSubmission(c *gin.Context) {
                 return
         }
 
+        if submission.Status == models.StatusCancelled {
+                c.SSEvent("end", gin.H{"status": submission.Status, "request_id": submission.RequestID})
+                flusher.Flush()
+                return
+        }
+
         flusher.Flush()
 
         c.Stream(func(w io.Writer) bool {
                 select {
                 case <-c.Request.Context().Done():
                         return false
-                case event, ok := <-listener:
+                case msg, ok := <-listener:
                         if !ok {
                                 return false
                         }
-                        c.SSEvent(event.Event, event.Data)
+                        if msg.ID <= sent {
+                                return true // already replayed
+                        }
+                        writeStreamEvent(c, msg)
                         flusher.Flush()
-                        return true
+                        return msg.Event != broker.EndEvent
                 case <-time.After(25 * time.Second):
                         c.SSEvent("ping", gin.H{"time": time.Now().Unix(), "request_id": submission.RequestID})
                         flusher.Flush()
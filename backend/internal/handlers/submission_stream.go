@@ -1,117 +1,75 @@
 package handlers
 
-import "sync"
+import (
+	"log"
+	"strconv"
+
+	"tamil-proofreading-platform/backend/internal/services/broker"
+
+	"github.com/gin-contrib/sse"
+	"github.com/gin-gonic/gin"
+)
 
 type submissionEvent struct {
 	Event string      `json:"event"`
 	Data  interface{} `json:"data"`
 }
 
-type submissionBroadcaster struct {
-	mu      sync.RWMutex
-	clients map[chan submissionEvent]struct{}
+// submissionStreamHub publishes the events of one kind of stream, such as
+// submissions or batches, through the broker so that clients connected to
+// any instance receive them.
+type submissionStreamHub struct {
+	broker broker.Broker
+	kind   string
 }
 
-func newSubmissionBroadcaster() *submissionBroadcaster {
-	return &submissionBroadcaster{
-		clients: make(map[chan submissionEvent]struct{}),
-	}
+func newSubmissionStreamHub(b broker.Broker, kind string) *submissionStreamHub {
+	return &submissionStreamHub{broker: b, kind: kind}
 }
 
-func (b *submissionBroadcaster) addListener() (chan submissionEvent, func()) {
-	ch := make(chan submissionEvent, 8)
-
-	b.mu.Lock()
-	b.clients[ch] = struct{}{}
-	b.mu.Unlock()
-
-	return ch, func() {
-		b.mu.Lock()
-		if _, ok := b.clients[ch]; ok {
-			delete(b.clients, ch)
-			close(ch)
-		}
-		b.mu.Unlock()
-	}
+func (h *submissionStreamHub) topic(id uint) string {
+	return h.kind + ":" + strconv.FormatUint(uint64(id), 10)
 }
 
-func (b *submissionBroadcaster) broadcast(event submissionEvent) {
-	b.mu.RLock()
-	defer b.mu.RUnlock()
-
-	for ch := range b.clients {
-		select {
-		case ch <- event:
-		default:
-			// drop if slow consumer
-		}
-	}
+// register listens to a stream. The channel is closed after its "end"
+// event, or earlier if the listener falls behind; a handler then ends the
+// response so the client reconnects with Last-Event-ID and replays the
+// events it missed.
+func (h *submissionStreamHub) register(id uint) (<-chan broker.Message, func()) {
+	return h.broker.Subscribe(h.topic(id))
 }
 
-func (b *submissionBroadcaster) closeAll() {
-	b.mu.Lock()
-	defer b.mu.Unlock()
-
-	for ch := range b.clients {
-		close(ch)
-		delete(b.clients, ch)
+func (h *submissionStreamHub) broadcast(id uint, event submissionEvent) {
+	if err := h.broker.Publish(h.topic(id), event.Event, event.Data); err != nil {
+		log.Printf("Error publishing %s event to %s: %v", event.Event, h.topic(id), err)
 	}
 }
 
-type submissionStreamHub struct {
-	mu      sync.RWMutex
-	streams map[uint]*submissionBroadcaster
-}
-
-func newSubmissionStreamHub() *submissionStreamHub {
-	return &submissionStreamHub{
-		streams: make(map[uint]*submissionBroadcaster),
-	}
+// replay returns the stream's recent events after lastEventID.
+func (h *submissionStreamHub) replay(id uint, lastEventID int64) ([]broker.Message, error) {
+	return h.broker.Since(h.topic(id), lastEventID)
 }
 
-func (h *submissionStreamHub) register(submissionID uint) (chan submissionEvent, func()) {
-	h.mu.Lock()
-	broadcaster, ok := h.streams[submissionID]
-	if !ok {
-		broadcaster = newSubmissionBroadcaster()
-		h.streams[submissionID] = broadcaster
-	}
-	h.mu.Unlock()
-
-	listener, cancel := broadcaster.addListener()
-	unsubscribe := func() {
-		cancel()
-
-		h.mu.Lock()
-		if len(broadcaster.clients) == 0 {
-			delete(h.streams, submissionID)
-		}
-		h.mu.Unlock()
+// lastEventID is the ID of the last event a reconnecting client saw, from
+// the Last-Event-ID header browsers send or a last_event_id parameter.
+func lastEventID(c *gin.Context) int64 {
+	raw := c.GetHeader("Last-Event-ID")
+	if raw == "" {
+		raw = c.Query("last_event_id")
 	}
-
-	return listener, unsubscribe
-}
-
-func (h *submissionStreamHub) broadcast(submissionID uint, event submissionEvent) {
-	h.mu.RLock()
-	broadcaster, ok := h.streams[submissionID]
-	h.mu.RUnlock()
-	if !ok {
-		return
+	id, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil || id < 0 {
+		return 0
 	}
-
-	broadcaster.broadcast(event)
+	return id
 }
 
-func (h *submissionStreamHub) close(submissionID uint) {
-	h.mu.Lock()
-	broadcaster, ok := h.streams[submissionID]
-	if ok {
-		delete(h.streams, submissionID)
-	}
-	h.mu.Unlock()
-
-	if ok {
-		broadcaster.closeAll()
-	}
+// writeStreamEvent sends a published event with its ID, so the client can
+// resume after it.
+func writeStreamEvent(c *gin.Context, msg broker.Message) {
+	c.Render(-1, sse.Event{
+		Id:    strconv.FormatInt(msg.ID, 10),
+		Event: msg.Event,
+		Data:  msg.Data,
+	})
 }
@@ -0,0 +1,154 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"tamil-proofreading-platform/backend/internal/models"
+	"tamil-proofreading-platform/backend/internal/services/fileformat"
+	"tamil-proofreading-platform/backend/internal/services/subtitle"
+	"tamil-proofreading-platform/backend/internal/services/suggestions"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// GetSubtitleReport checks the cues of an uploaded subtitle file for reading
+// speed and line length, after applying its suggestions as export would
+// GET /api/v1/submissions/:id/subtitle-report?include=all|accepted|none&max_cps=&max_line_length=&max_lines=
+func (h *Handlers) GetSubtitleReport(c *gin.Context) {
+	submission, ok := h.loadUserSubmission(c)
+	if !ok {
+		return
+	}
+
+	limits, ok := subtitleLimits(c)
+	if !ok {
+		return
+	}
+	include := c.DefaultQuery("include", "all")
+	if include != "all" && include != "accepted" && include != "none" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "include must be all, accepted or none"})
+		return
+	}
+
+	var file models.SubmissionFile
+	if err := h.db.Where("submission_id = ?", submission.ID).First(&file).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Submission has no uploaded file"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load file"})
+		return
+	}
+	if file.Format != string(subtitle.SRT) && file.Format != string(subtitle.WebVTT) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Submission is not a subtitle file"})
+		return
+	}
+
+	data := file.Data
+	if include != "none" {
+		rows, err := suggestions.Load(h.db, *submission)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch suggestions"})
+			return
+		}
+		format, err := fileformat.Lookup(file.Format)
+		if err != nil {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+			return
+		}
+		data, err = format.Apply(file.Data, fileEdits(rows, include == "accepted", file.TextOffset), fileformat.ApplyOptions{})
+		if err != nil {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	cues, err := fileformat.Cues(file.Format, data)
+	if err != nil {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"report": subtitle.Check(cues, limits)})
+}
+
+// CheckSubtitles checks an uploaded SRT or WebVTT file without proofreading
+// it
+// POST /api/v1/subtitles/check?max_cps=&max_line_length=&max_lines=
+func (h *Handlers) CheckSubtitles(c *gin.Context) {
+	limits, ok := subtitleLimits(c)
+	if !ok {
+		return
+	}
+
+	header, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "file is required"})
+		return
+	}
+	format := strings.TrimPrefix(strings.ToLower(filepath.Ext(header.Filename)), ".")
+	if format == "webvtt" {
+		format = string(subtitle.WebVTT)
+	}
+
+	f, err := header.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read upload"})
+		return
+	}
+	defer f.Close()
+	data, err := io.ReadAll(io.LimitReader(f, maxUploadBytes+1))
+	if err != nil || len(data) > maxUploadBytes {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read upload"})
+		return
+	}
+
+	cues, err := fileformat.Cues(format, data)
+	if errors.Is(err, fileformat.ErrUnsupported) {
+		c.JSON(http.StatusUnsupportedMediaType, gin.H{"error": "file must be .srt or .vtt"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"report": subtitle.Check(cues, limits)})
+}
+
+// subtitleLimits reads the limits from the query, falling back to the
+// defaults. A value of 0 disables a check.
+func subtitleLimits(c *gin.Context) (subtitle.Limits, bool) {
+	limits := subtitle.DefaultLimits
+	if v := c.Query("max_cps"); v != "" {
+		cps, err := strconv.ParseFloat(v, 64)
+		if err != nil || cps < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid max_cps"})
+			return limits, false
+		}
+		limits.MaxCPS = cps
+	}
+	for _, p := range []struct {
+		name string
+		dst  *int
+	}{
+		{"max_line_length", &limits.MaxLineLength},
+		{"max_lines", &limits.MaxLines},
+	} {
+		if v := c.Query(p.name); v != "" {
+			n, err := strconv.Atoi(v)
+			if err != nil || n < 0 {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid " + p.name})
+				return limits, false
+			}
+			*p.dst = n
+		}
+	}
+	return limits, true
+}
@@ -0,0 +1,245 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"tamil-proofreading-platform/backend/internal/middleware"
+	"tamil-proofreading-platform/backend/internal/models"
+	"tamil-proofreading-platform/backend/internal/services/suggestions"
+	"tamil-proofreading-platform/backend/internal/util/auditlog"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type AcceptAllSuggestionsRequest struct {
+	Type string `json:"type" binding:"required"`
+}
+
+// GetSubmissionSuggestions lists a submission's suggestions with their
+// state, optionally filtered by ?state= and ?type=
+// GET /api/v1/submissions/:id/suggestions
+func (h *Handlers) GetSubmissionSuggestions(c *gin.Context) {
+	submission, ok := h.loadUserSubmission(c)
+	if !ok {
+		return
+	}
+
+	rows, err := suggestions.Load(h.db, *submission)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch suggestions"})
+		return
+	}
+
+	state := c.Query("state")
+	kind := c.Query("type")
+	filtered := rows[:0:0]
+	counts := map[models.SuggestionState]int{}
+	for _, s := range rows {
+		counts[s.State]++
+		if (state == "" || string(s.State) == state) && (kind == "" || s.Type == kind) {
+			filtered = append(filtered, s)
+		}
+	}
+
+	finalText := submission.FinalText
+	if finalText == "" {
+		finalText = suggestions.Apply(submission.OriginalText, rows)
+	}
+
+	response := gin.H{
+		"suggestions": filtered,
+		"counts":      counts,
+		"final_text":  finalText,
+	}
+	if submission.OriginalHTML != "" {
+		if finalHTML, _, _, err := suggestionHTML(submission.OriginalHTML, rows, true); err == nil {
+			response["final_html"] = finalHTML
+		}
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// AcceptSuggestion applies one suggestion to the submission's final text
+// POST /api/v1/submissions/:id/suggestions/:suggestion_id/accept
+func (h *Handlers) AcceptSuggestion(c *gin.Context) {
+	h.decideSuggestion(c, models.SuggestionAccepted)
+}
+
+// RejectSuggestion rejects one suggestion, removing it from the final text
+// if it was accepted before
+// POST /api/v1/submissions/:id/suggestions/:suggestion_id/reject
+func (h *Handlers) RejectSuggestion(c *gin.Context) {
+	h.decideSuggestion(c, models.SuggestionRejected)
+}
+
+func (h *Handlers) decideSuggestion(c *gin.Context, state models.SuggestionState) {
+	suggestionID, err := strconv.ParseUint(c.Param("suggestion_id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid suggestion ID"})
+		return
+	}
+
+	submission, ok := h.loadUserSubmission(c)
+	if !ok {
+		return
+	}
+
+	changed, err := suggestions.Decide(h.db, submission, []uint{uint(suggestionID)}, state, false)
+	if err != nil {
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "Suggestion not found"})
+		case errors.Is(err, suggestions.ErrNotApplicable):
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		case errors.Is(err, suggestions.ErrConflict):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update suggestion"})
+		}
+		return
+	}
+
+	h.recordSuggestionEvents(c, submission.UserID, submission.ID, changed, state)
+
+	var suggestion models.SubmissionSuggestion
+	if err := h.db.Where("submission_id = ?", submission.ID).First(&suggestion, suggestionID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Suggestion not found"})
+			return
+		}
+		log.Printf("Error loading suggestion %d: %v", suggestionID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load suggestion"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"suggestion": suggestion,
+		"final_text": submission.FinalText,
+	})
+}
+
+// AcceptAllSuggestions accepts every pending suggestion of one type.
+// Suggestions that cannot be applied or that overlap an accepted one are
+// left pending.
+// POST /api/v1/submissions/:id/suggestions/accept-all
+func (h *Handlers) AcceptAllSuggestions(c *gin.Context) {
+	var req AcceptAllSuggestionsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	req.Type = strings.TrimSpace(req.Type)
+
+	submission, ok := h.loadUserSubmission(c)
+	if !ok {
+		return
+	}
+
+	var ids []uint
+	if err := h.db.Model(&models.SubmissionSuggestion{}).
+		Where("submission_id = ? AND type = ? AND state = ?", submission.ID, req.Type, models.SuggestionPending).
+		Order("position ASC").
+		Pluck("id", &ids).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch suggestions"})
+		return
+	}
+
+	changed, err := suggestions.Decide(h.db, submission, ids, models.SuggestionAccepted, true)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update suggestions"})
+		return
+	}
+
+	h.recordSuggestionEvents(c, submission.UserID, submission.ID, changed, models.SuggestionAccepted)
+
+	c.JSON(http.StatusOK, gin.H{
+		"accepted":   len(changed),
+		"skipped":    len(ids) - len(changed),
+		"final_text": submission.FinalText,
+	})
+}
+
+// loadUserSubmission loads the current user's completed :id submission,
+// writing an error response if it cannot.
+func (h *Handlers) loadUserSubmission(c *gin.Context) (*models.Submission, bool) {
+	submission, ok := h.findUserSubmission(c)
+	if !ok {
+		return nil, false
+	}
+	if submission.Status != models.StatusCompleted {
+		c.JSON(http.StatusConflict, gin.H{"error": "Submission has not finished processing"})
+		return nil, false
+	}
+	return submission, true
+}
+
+// findUserSubmission loads the current user's submission named by the :id
+// path parameter in any status, writing an error response on failure.
+func (h *Handlers) findUserSubmission(c *gin.Context) (*models.Submission, bool) {
+	userID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return nil, false
+	}
+
+	submissionID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid submission ID"})
+		return nil, false
+	}
+
+	var submission models.Submission
+	if err := h.db.Where("id = ? AND user_id = ?", submissionID, userID).First(&submission).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Submission not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch submission"})
+		}
+		return nil, false
+	}
+	return &submission, true
+}
+
+// recordSuggestionEvents logs an activity event per decided suggestion so
+// analytics can be traced back to the suggestion.
+func (h *Handlers) recordSuggestionEvents(c *gin.Context, userID, submissionID uint, changed []models.SubmissionSuggestion, state models.SuggestionState) {
+	if len(changed) == 0 {
+		return
+	}
+
+	eventType := models.EventSuggestionAccept
+	if state == models.SuggestionRejected {
+		eventType = models.EventSuggestionReject
+	}
+
+	now := time.Now()
+	events := make([]models.ActivityEvent, 0, len(changed))
+	for _, s := range changed {
+		metadata, _ := json.Marshal(map[string]any{
+			"submission_id": submissionID,
+			"suggestion_id": s.ID,
+			"type":          s.Type,
+		})
+		events = append(events, models.ActivityEvent{
+			UserID:     userID,
+			EventType:  eventType,
+			Metadata:   string(metadata),
+			OccurredAt: now,
+		})
+	}
+	if err := h.db.Create(&events).Error; err != nil {
+		log.Printf("Error recording suggestion events (submission=%d): %v", submissionID, err)
+	}
+
+	auditlog.Info(c, "submission.suggestions_"+string(state), map[string]any{
+		"submission_id": submissionID,
+		"count":         len(changed),
+	})
+}
@@ -1,11 +1,15 @@
 package handlers
 
 import (
+        "encoding/json"
         "net/http"
         "strconv"
         "strings"
 
+        "tamil-proofreading-platform/backend/internal/middleware"
         "tamil-proofreading-platform/backend/internal/models"
+        "tamil-proofreading-platform/backend/internal/services/moderation"
+        "tamil-proofreading-platform/backend/internal/util/auditlog"
 
         "github.com/gin-gonic/gin"
 )
@@ -56,6 +60,7 @@ func (h *Handlers) AutocompleteTamil(c *gin.Context) {
         // Transliterations are stored lowercase, so we don't need LOWER()
         err := h.db.
                 Where("transliteration LIKE ?", query+"%").
+                Where("review_status = ?", models.WordApproved).
                 Order("frequency DESC, user_confirmed DESC").
                 Limit(limit).
                 Find(&words).Error
@@ -94,9 +99,16 @@ func (h *Handlers) AutocompleteTamil(c *gin.Context) {
         c.JSON(http.StatusOK, response)
 }
 
-// AddTamilWord allows adding a new Tamil word to the database
+// AddTamilWord records a user-contributed Tamil word for moderation.
+// New words are stored as pending and only served once a reviewer approves them.
 // POST /api/v1/tamil-words
 func (h *Handlers) AddTamilWord(c *gin.Context) {
+        userID, err := middleware.GetUserFromContext(c)
+        if err != nil {
+                c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized - please login"})
+                return
+        }
+
         var req struct {
                 TamilText          string   `json:"tamil_text" binding:"required"`
                 Transliteration    string   `json:"transliteration" binding:"required"`
@@ -104,7 +116,7 @@ func (h *Handlers) AddTamilWord(c *gin.Context) {
                 Frequency          int      `json:"frequency"`
                 Category           string   `json:"category"`
                 Meaning            string   `json:"meaning"`
-                Source             string   `json:"source"`
+                Example            string   `json:"example"`
         }
 
         if err := c.ShouldBindJSON(&req); err != nil {
@@ -113,12 +125,34 @@ func (h *Handlers) AddTamilWord(c *gin.Context) {
         }
 
         // Normalize transliteration to lowercase for consistency
-        normalizedTranslit := strings.ToLower(req.Transliteration)
-        
+        tamilText := strings.TrimSpace(req.TamilText)
+        normalizedTranslit := strings.ToLower(strings.TrimSpace(req.Transliteration))
+
+        if err := moderation.ValidateScript(tamilText, normalizedTranslit); err != nil {
+                c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+                return
+        }
+
         // Check if word already exists (case-insensitive check)
         var existingWord models.TamilWord
-        err := h.db.Where("transliteration = ?", normalizedTranslit).First(&existingWord).Error
+        err = h.db.Where("transliteration = ?", normalizedTranslit).First(&existingWord).Error
         if err == nil {
+                switch existingWord.ReviewStatus {
+                case models.WordRejected:
+                        c.JSON(http.StatusConflict, gin.H{
+                                "error":  "This word was rejected by a reviewer",
+                                "reason": existingWord.ReviewNote,
+                        })
+                        return
+                case models.WordPending:
+                        h.db.Model(&existingWord).Update("user_confirmed", existingWord.UserConfirmed+1)
+                        c.JSON(http.StatusOK, gin.H{
+                                "message": "Word is already awaiting review, confirmation count increased",
+                                "word":    existingWord,
+                        })
+                        return
+                }
+
                 // Word exists, increment user_confirmed count
                 h.db.Model(&existingWord).Update("user_confirmed", existingWord.UserConfirmed+1)
                 c.JSON(http.StatusOK, gin.H{
@@ -128,22 +162,37 @@ func (h *Handlers) AddTamilWord(c *gin.Context) {
                 return
         }
 
-        // Create new word
-        alternateSpellings := ""
-        if len(req.AlternateSpellings) > 0 {
-                alternateSpellings = strings.Join(req.AlternateSpellings, ",")
+        category := models.WordCategory(strings.TrimSpace(req.Category))
+        if category == "" {
+                category = models.CategoryCommon
         }
 
         word := models.TamilWord{
-                TamilText:          req.TamilText,
-                Transliteration:    normalizedTranslit,
-                AlternateSpellings: alternateSpellings,
-                Frequency:          req.Frequency,
-                Category:           models.WordCategory(req.Category),
-                Meaning:            req.Meaning,
-                Source:             req.Source,
-                UserConfirmed:      1,
-                IsVerified:         req.Source == "manual",
+                TamilText:       tamilText,
+                Transliteration: normalizedTranslit,
+                Frequency:       req.Frequency,
+                Category:        category,
+                Meaning:         strings.TrimSpace(req.Meaning),
+                Example:         strings.TrimSpace(req.Example),
+                Source:          "user_contribution",
+                UserConfirmed:   1,
+                IsVerified:      false,
+                ReviewStatus:    models.WordPending,
+                SubmittedBy:     &userID,
+        }
+        word.SetAlternates(req.AlternateSpellings)
+
+        checks, err := h.moderationService.Check(&word)
+        if err != nil {
+                c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check word"})
+                return
+        }
+        if flags, err := json.Marshal(checks.Notes); err == nil && len(checks.Flags) > 0 {
+                word.CheckFlags = string(flags)
+        }
+        if checks.Blocked {
+                word.ReviewStatus = models.WordRejected
+                word.ReviewNote = "automatic: contains a blocked term"
         }
 
         if err := h.db.Create(&word).Error; err != nil {
@@ -151,9 +200,24 @@ func (h *Handlers) AddTamilWord(c *gin.Context) {
                 return
         }
 
-        c.JSON(http.StatusCreated, gin.H{
-                "message": "Tamil word added successfully",
+        auditlog.Info(c, "tamil_word.contributed", map[string]any{
+                "word_id": word.ID,
+                "status":  word.ReviewStatus,
+                "flags":   checks.Flags,
+        })
+
+        if checks.Blocked {
+                c.JSON(http.StatusUnprocessableEntity, gin.H{
+                        "error":  "Word was rejected by automatic checks",
+                        "checks": checks,
+                })
+                return
+        }
+
+        c.JSON(http.StatusAccepted, gin.H{
+                "message": "Thanks! The word will be available once a reviewer approves it",
                 "word":    word,
+                "checks":  checks,
         })
 }
 
@@ -172,8 +236,8 @@ func (h *Handlers) ConfirmTamilWord(c *gin.Context) {
 
         // Find and update the word
         var word models.TamilWord
-        err := h.db.Where("transliteration = ? AND tamil_text = ?", 
-                strings.ToLower(req.Transliteration), req.TamilText).First(&word).Error
+        err := h.db.Where("transliteration = ? AND tamil_text = ? AND review_status = ?",
+                strings.ToLower(req.Transliteration), req.TamilText, models.WordApproved).First(&word).Error
         
         if err != nil {
                 // Word doesn't exist in database, we could add it
@@ -0,0 +1,235 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"tamil-proofreading-platform/backend/internal/middleware"
+	"tamil-proofreading-platform/backend/internal/models"
+	"tamil-proofreading-platform/backend/internal/services/userdict"
+	"tamil-proofreading-platform/backend/internal/util/auditlog"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm/clause"
+)
+
+const maxDictionaryEntries = 5000
+
+type DictionaryWordRequest struct {
+	Word string                     `json:"word"`
+	Kind models.DictionaryEntryKind `json:"kind"`
+	Note string                     `json:"note"`
+	// Words adds several entries at once with the same kind.
+	Words []string `json:"words"`
+}
+
+type IgnoreRuleRequest struct {
+	Pattern        string                 `json:"pattern" binding:"required"`
+	MatchType      models.IgnoreMatchType `json:"match_type"`
+	SuggestionType string                 `json:"suggestion_type"`
+	Note           string                 `json:"note"`
+}
+
+// GetDictionaryWords lists the user's personal dictionary
+// GET /api/v1/dictionary/words
+func (h *Handlers) GetDictionaryWords(c *gin.Context) {
+	userID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var entries []models.UserDictionaryEntry
+	if err := h.db.Where("user_id = ?", userID).Order("word ASC").Find(&entries).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch dictionary"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"words": entries, "total": len(entries)})
+}
+
+// AddDictionaryWords adds one word, or a list via "words". Existing words are
+// left unchanged.
+// POST /api/v1/dictionary/words
+func (h *Handlers) AddDictionaryWords(c *gin.Context) {
+	userID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var req DictionaryWordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	switch req.Kind {
+	case "":
+		req.Kind = models.DictionaryWord
+	case models.DictionaryWord, models.DictionaryName, models.DictionaryBrand:
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "kind must be word, name or brand"})
+		return
+	}
+
+	seen := make(map[string]bool)
+	var entries []models.UserDictionaryEntry
+	for _, word := range append([]string{req.Word}, req.Words...) {
+		word = strings.TrimSpace(word)
+		if word == "" || seen[word] {
+			continue
+		}
+		if len(word) > 255 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Words must be at most 255 bytes"})
+			return
+		}
+		seen[word] = true
+		entries = append(entries, models.UserDictionaryEntry{
+			UserID: userID,
+			Word:   word,
+			Kind:   req.Kind,
+			Note:   strings.TrimSpace(req.Note),
+		})
+	}
+	if len(entries) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "word or words is required"})
+		return
+	}
+
+	var count int64
+	h.db.Model(&models.UserDictionaryEntry{}).Where("user_id = ?", userID).Count(&count)
+	if int(count)+len(entries) > maxDictionaryEntries {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Personal dictionary is limited to " + strconv.Itoa(maxDictionaryEntries) + " words"})
+		return
+	}
+
+	result := h.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&entries)
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save dictionary words"})
+		return
+	}
+
+	auditlog.Info(c, "dictionary.words_added", map[string]any{
+		"user_id": userID,
+		"added":   result.RowsAffected,
+	})
+
+	c.JSON(http.StatusCreated, gin.H{
+		"added":   result.RowsAffected,
+		"skipped": int64(len(entries)) - result.RowsAffected,
+	})
+}
+
+// DeleteDictionaryWord removes a word from the user's dictionary
+// DELETE /api/v1/dictionary/words/:id
+func (h *Handlers) DeleteDictionaryWord(c *gin.Context) {
+	h.deleteUserDictionaryRow(c, &models.UserDictionaryEntry{}, "Dictionary word", "dictionary.word_deleted")
+}
+
+// GetIgnoreRules lists the user's ignore rules
+// GET /api/v1/dictionary/ignore-rules
+func (h *Handlers) GetIgnoreRules(c *gin.Context) {
+	userID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var rules []models.UserIgnoreRule
+	if err := h.db.Where("user_id = ?", userID).Order("id ASC").Find(&rules).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch ignore rules"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"rules": rules})
+}
+
+// CreateIgnoreRule adds a rule that drops matching suggestions
+// POST /api/v1/dictionary/ignore-rules
+func (h *Handlers) CreateIgnoreRule(c *gin.Context) {
+	userID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var req IgnoreRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	rule := models.UserIgnoreRule{
+		UserID:         userID,
+		Pattern:        strings.TrimSpace(req.Pattern),
+		MatchType:      req.MatchType,
+		SuggestionType: strings.TrimSpace(req.SuggestionType),
+		Note:           strings.TrimSpace(req.Note),
+	}
+	if rule.Pattern == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "pattern is required"})
+		return
+	}
+
+	switch rule.MatchType {
+	case "":
+		rule.MatchType = models.IgnoreExact
+	case models.IgnoreExact, models.IgnorePrefix:
+	case models.IgnoreRegex:
+		if _, err := userdict.CompilePattern(rule.Pattern); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid pattern: " + err.Error()})
+			return
+		}
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "match_type must be exact, prefix or regex"})
+		return
+	}
+
+	if err := h.db.Create(&rule).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save ignore rule"})
+		return
+	}
+
+	auditlog.Info(c, "dictionary.ignore_rule_created", map[string]any{
+		"user_id":    userID,
+		"rule_id":    rule.ID,
+		"match_type": rule.MatchType,
+	})
+
+	c.JSON(http.StatusCreated, gin.H{"rule": rule})
+}
+
+// DeleteIgnoreRule removes one of the user's ignore rules
+// DELETE /api/v1/dictionary/ignore-rules/:id
+func (h *Handlers) DeleteIgnoreRule(c *gin.Context) {
+	h.deleteUserDictionaryRow(c, &models.UserIgnoreRule{}, "Ignore rule", "dictionary.ignore_rule_deleted")
+}
+
+func (h *Handlers) deleteUserDictionaryRow(c *gin.Context, model interface{}, label, event string) {
+	userID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	result := h.db.Where("id = ? AND user_id = ?", id, userID).Delete(model)
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete " + strings.ToLower(label)})
+		return
+	}
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": label + " not found"})
+		return
+	}
+
+	auditlog.Info(c, event, map[string]any{"user_id": userID, "id": id})
+	c.JSON(http.StatusOK, gin.H{"message": label + " deleted"})
+}
@@ -0,0 +1,468 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"tamil-proofreading-platform/backend/internal/middleware"
+	"tamil-proofreading-platform/backend/internal/models"
+	"tamil-proofreading-platform/backend/internal/services/webhooks"
+	"tamil-proofreading-platform/backend/internal/util/auditlog"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+const maxWebhookEndpoints = 10
+
+type WebhookEndpointRequest struct {
+	URL         string   `json:"url" binding:"required"`
+	Events      []string `json:"events" binding:"required"`
+	Description string   `json:"description"`
+	// Organization registers the endpoint for the user's organization, so
+	// it receives every member's events. It needs the owner or editor role.
+	Organization bool `json:"organization"`
+}
+
+type UpdateWebhookEndpointRequest struct {
+	URL         *string  `json:"url"`
+	Events      []string `json:"events"`
+	Description *string  `json:"description"`
+	Active      *bool    `json:"active"`
+}
+
+// GetWebhookEndpoints lists the user's endpoints and, for organization
+// owners and editors, the organization's
+// GET /api/v1/webhook-endpoints
+func (h *Handlers) GetWebhookEndpoints(c *gin.Context) {
+	userID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	query := h.db.Where("user_id = ?", userID)
+	if member, err := h.glossaryService.Membership(userID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check membership"})
+		return
+	} else if member != nil && member.CanManage() {
+		query = h.db.Where("user_id = ? OR organization_id = ?", userID, member.OrganizationID)
+	}
+
+	var endpoints []models.WebhookEndpoint
+	if err := query.Order("id ASC").Find(&endpoints).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch webhook endpoints"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"endpoints": endpoints, "events": models.WebhookEvents})
+}
+
+// CreateWebhookEndpoint registers an endpoint. Its signing secret is only
+// returned here and when it is rotated.
+// POST /api/v1/webhook-endpoints
+func (h *Handlers) CreateWebhookEndpoint(c *gin.Context) {
+	userID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var req WebhookEndpointRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	endpoint := models.WebhookEndpoint{
+		CreatedBy:   userID,
+		Description: strings.TrimSpace(req.Description),
+		Active:      true,
+	}
+	if req.Organization {
+		member, ok := h.requireOrgMember(c, true)
+		if !ok {
+			return
+		}
+		endpoint.OrganizationID = &member.OrganizationID
+	} else {
+		endpoint.UserID = &userID
+	}
+	if !h.setWebhookEndpointFields(c, &endpoint, &req.URL, req.Events, nil) {
+		return
+	}
+
+	var count int64
+	owner := h.db.Model(&models.WebhookEndpoint{})
+	if endpoint.OrganizationID != nil {
+		owner = owner.Where("organization_id = ?", *endpoint.OrganizationID)
+	} else {
+		owner = owner.Where("user_id = ?", userID)
+	}
+	if err := owner.Count(&count).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save webhook endpoint"})
+		return
+	}
+	if count >= maxWebhookEndpoints {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "At most " + strconv.Itoa(maxWebhookEndpoints) + " webhook endpoints are allowed"})
+		return
+	}
+
+	secret, err := webhooks.NewSecret()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate secret"})
+		return
+	}
+	endpoint.Secret = secret
+	if err := h.db.Create(&endpoint).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save webhook endpoint"})
+		return
+	}
+
+	auditlog.Info(c, "webhook.endpoint_created", map[string]any{
+		"user_id":         userID,
+		"endpoint_id":     endpoint.ID,
+		"organization_id": endpoint.OrganizationID,
+		"events":          endpoint.Events,
+	})
+
+	c.JSON(http.StatusCreated, gin.H{"endpoint": endpoint, "secret": secret})
+}
+
+// UpdateWebhookEndpoint changes an endpoint's URL, events, description or
+// whether it is active
+// PATCH /api/v1/webhook-endpoints/:id
+func (h *Handlers) UpdateWebhookEndpoint(c *gin.Context) {
+	endpoint, ok := h.loadWebhookEndpoint(c)
+	if !ok {
+		return
+	}
+
+	var req UpdateWebhookEndpointRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if !h.setWebhookEndpointFields(c, endpoint, req.URL, req.Events, req.Description) {
+		return
+	}
+	if req.Active != nil {
+		endpoint.Active = *req.Active
+	}
+
+	if err := h.db.Select("url", "events", "description", "active").Save(endpoint).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update webhook endpoint"})
+		return
+	}
+
+	auditlog.Info(c, "webhook.endpoint_updated", map[string]any{
+		"endpoint_id": endpoint.ID,
+		"active":      endpoint.Active,
+	})
+
+	c.JSON(http.StatusOK, gin.H{"endpoint": endpoint})
+}
+
+// RotateWebhookSecret replaces an endpoint's signing secret
+// POST /api/v1/webhook-endpoints/:id/rotate-secret
+func (h *Handlers) RotateWebhookSecret(c *gin.Context) {
+	endpoint, ok := h.loadWebhookEndpoint(c)
+	if !ok {
+		return
+	}
+
+	secret, err := webhooks.NewSecret()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate secret"})
+		return
+	}
+	if err := h.db.Model(endpoint).Update("secret", secret).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to rotate secret"})
+		return
+	}
+
+	auditlog.Info(c, "webhook.secret_rotated", map[string]any{"endpoint_id": endpoint.ID})
+	c.JSON(http.StatusOK, gin.H{"endpoint": endpoint, "secret": secret})
+}
+
+// DeleteWebhookEndpoint removes an endpoint; its pending deliveries fail
+// DELETE /api/v1/webhook-endpoints/:id
+func (h *Handlers) DeleteWebhookEndpoint(c *gin.Context) {
+	endpoint, ok := h.loadWebhookEndpoint(c)
+	if !ok {
+		return
+	}
+
+	if err := h.db.Delete(endpoint).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete webhook endpoint"})
+		return
+	}
+
+	auditlog.Info(c, "webhook.endpoint_deleted", map[string]any{"endpoint_id": endpoint.ID})
+	c.JSON(http.StatusOK, gin.H{"message": "Webhook endpoint deleted"})
+}
+
+// GetWebhookDeliveries lists an endpoint's deliveries, newest first
+// GET /api/v1/webhook-endpoints/:id/deliveries?state=&event=&limit=&offset=
+func (h *Handlers) GetWebhookDeliveries(c *gin.Context) {
+	endpoint, ok := h.loadWebhookEndpoint(c)
+	if !ok {
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if err != nil || limit <= 0 || limit > 200 {
+		limit = 50
+	}
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+
+	query := h.db.Model(&models.WebhookDelivery{}).Where("endpoint_id = ?", endpoint.ID)
+	if state := c.Query("state"); state != "" {
+		query = query.Where("state = ?", state)
+	}
+	if event := c.Query("event"); event != "" {
+		query = query.Where("event = ?", event)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch deliveries"})
+		return
+	}
+	var deliveries []models.WebhookDelivery
+	if err := query.Order("id DESC").Limit(limit).Offset(max(offset, 0)).Find(&deliveries).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch deliveries"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"deliveries": deliveries,
+		"total":      total,
+		"limit":      limit,
+		"offset":     offset,
+	})
+}
+
+// ReplayWebhookDelivery sends a delivery's event to the endpoint again
+// POST /api/v1/webhook-endpoints/:id/deliveries/:delivery_id/replay
+func (h *Handlers) ReplayWebhookDelivery(c *gin.Context) {
+	endpoint, ok := h.loadWebhookEndpoint(c)
+	if !ok {
+		return
+	}
+
+	deliveryID, err := strconv.ParseUint(c.Param("delivery_id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid delivery ID"})
+		return
+	}
+	var original models.WebhookDelivery
+	if err := h.db.Where("id = ? AND endpoint_id = ?", deliveryID, endpoint.ID).First(&original).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Delivery not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch delivery"})
+		return
+	}
+
+	delivery, err := h.webhookService.Replay(&original)
+	if errors.Is(err, webhooks.ErrEndpointStopped) {
+		c.JSON(http.StatusConflict, gin.H{"error": "Webhook endpoint is disabled"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to replay delivery"})
+		return
+	}
+
+	auditlog.Info(c, "webhook.delivery_replayed", map[string]any{
+		"endpoint_id": endpoint.ID,
+		"delivery_id": original.ID,
+		"replay_id":   delivery.ID,
+	})
+
+	c.JSON(http.StatusAccepted, gin.H{"delivery": delivery})
+}
+
+// loadWebhookEndpoint loads the :id endpoint if it belongs to the current
+// user, or to their organization and they may manage it.
+func (h *Handlers) loadWebhookEndpoint(c *gin.Context) (*models.WebhookEndpoint, bool) {
+	userID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return nil, false
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid webhook endpoint ID"})
+		return nil, false
+	}
+
+	var endpoint models.WebhookEndpoint
+	if err := h.db.First(&endpoint, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Webhook endpoint not found"})
+			return nil, false
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch webhook endpoint"})
+		return nil, false
+	}
+
+	allowed := endpoint.UserID != nil && *endpoint.UserID == userID
+	if !allowed && endpoint.OrganizationID != nil {
+		member, err := h.glossaryService.Membership(userID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check membership"})
+			return nil, false
+		}
+		allowed = member != nil && member.OrganizationID == *endpoint.OrganizationID && member.CanManage()
+	}
+	if !allowed {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Webhook endpoint not found"})
+		return nil, false
+	}
+	return &endpoint, true
+}
+
+// setWebhookEndpointFields validates and applies the given fields, writing
+// an error response if any is invalid. Nil fields are left unchanged.
+func (h *Handlers) setWebhookEndpointFields(c *gin.Context, endpoint *models.WebhookEndpoint, rawURL *string, events []string, description *string) bool {
+	if rawURL != nil {
+		normalized, err := h.webhookService.ValidateURL(*rawURL)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return false
+		}
+		endpoint.URL = normalized
+	}
+	if events != nil {
+		known := make(map[string]bool, len(models.WebhookEvents))
+		for _, event := range models.WebhookEvents {
+			known[event] = true
+		}
+		seen := make(map[string]bool)
+		cleaned := make([]string, 0, len(events))
+		for _, event := range events {
+			event = strings.TrimSpace(event)
+			if event != "*" && !known[event] {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown event: " + event})
+				return false
+			}
+			if endpoint.OrganizationID != nil && !models.OrgShareableEvent(event) {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Organization endpoints cannot receive " + event})
+				return false
+			}
+			if !seen[event] {
+				seen[event] = true
+				cleaned = append(cleaned, event)
+			}
+		}
+		if len(cleaned) == 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "events must list at least one event"})
+			return false
+		}
+		endpoint.Events = cleaned
+	}
+	if description != nil {
+		endpoint.Description = strings.TrimSpace(*description)
+	}
+	if len(endpoint.Description) > 255 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "description must be at most 255 bytes"})
+		return false
+	}
+	return true
+}
+
+// SetOrganizationWebhookEvents sets which of the members' event types are
+// sent to the organization's endpoints; an empty list stops sharing.
+// Payment events cannot be shared.
+// PUT /api/v1/organizations/webhook-events
+func (h *Handlers) SetOrganizationWebhookEvents(c *gin.Context) {
+	member, ok := h.requireOrgMember(c, true)
+	if !ok {
+		return
+	}
+	if member.Role != models.OrgOwner {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only owners can change which events are shared"})
+		return
+	}
+
+	var req struct {
+		Events []string `json:"events"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	events := make([]string, 0, len(req.Events))
+	seen := make(map[string]bool)
+	for _, event := range req.Events {
+		event = strings.TrimSpace(event)
+		if !slices.Contains(models.WebhookEvents, event) || !models.OrgShareableEvent(event) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Event cannot be shared: " + event})
+			return
+		}
+		if !seen[event] {
+			seen[event] = true
+			events = append(events, event)
+		}
+	}
+
+	if err := h.db.Model(&models.Organization{ID: member.OrganizationID}).Select("webhook_events").
+		Updates(&models.Organization{WebhookEvents: events}).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update organization"})
+		return
+	}
+
+	auditlog.Info(c, "organization.webhook_events_set", map[string]any{
+		"organization_id": member.OrganizationID,
+		"events":          events,
+	})
+
+	c.JSON(http.StatusOK, gin.H{"webhook_events": events})
+}
+
+// publishWebhook queues an event for the user's webhook endpoints, logging
+// rather than failing if it cannot.
+func (h *Handlers) publishWebhook(userID uint, event string, data any) {
+	if err := h.webhookService.Publish(userID, event, data); err != nil {
+		log.Printf("Error publishing %s webhook for user %d: %v", event, userID, err)
+	}
+}
+
+// submissionWebhookData is the data of a submission event. Receivers fetch
+// the text and suggestions from the API.
+func submissionWebhookData(submission models.Submission) gin.H {
+	data := gin.H{
+		"submission_id": submission.ID,
+		"request_id":    submission.RequestID,
+		"status":        submission.Status,
+		"word_count":    submission.WordCount,
+		"model_used":    submission.ModelUsed,
+		"created_at":    submission.CreatedAt,
+	}
+	if submission.Error != "" {
+		data["error"] = submission.Error
+	}
+	return data
+}
+
+// paymentWebhookData is the data of a payment event.
+func paymentWebhookData(payment models.Payment) gin.H {
+	return gin.H{
+		"payment_id":     payment.ID,
+		"transaction_id": payment.TransactionID,
+		"status":         payment.Status,
+		"amount":         payment.Amount,
+		"currency":       payment.Currency,
+		"payment_type":   payment.PaymentType,
+		"invoice_number": payment.InvoiceNumber,
+	}
+}
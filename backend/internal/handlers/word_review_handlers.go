@@ -0,0 +1,233 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"tamil-proofreading-platform/backend/internal/middleware"
+	"tamil-proofreading-platform/backend/internal/models"
+	"tamil-proofreading-platform/backend/internal/services/moderation"
+	"tamil-proofreading-platform/backend/internal/util/auditlog"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// ReviewWordEdit carries reviewer corrections to a contributed word. Nil
+// fields are left unchanged.
+type ReviewWordEdit struct {
+	TamilText          *string  `json:"tamil_text"`
+	Transliteration    *string  `json:"transliteration"`
+	AlternateSpellings []string `json:"alternate_spellings"`
+	Category           *string  `json:"category"`
+	Meaning            *string  `json:"meaning"`
+	Example            *string  `json:"example"`
+	Frequency          *int     `json:"frequency"`
+	Reason             string   `json:"reason"`
+}
+
+// ReviewListTamilWords returns the moderation queue
+// GET /api/v1/review/tamil-words?status=pending&limit=20&offset=0
+func (h *Handlers) ReviewListTamilWords(c *gin.Context) {
+	status := models.WordReviewStatus(c.DefaultQuery("status", string(models.WordPending)))
+	switch status {
+	case models.WordPending, models.WordApproved, models.WordRejected:
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "status must be pending, approved or rejected"})
+		return
+	}
+
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if limit <= 0 || limit > 200 {
+		limit = 20
+	}
+
+	query := h.db.Model(&models.TamilWord{}).Where("review_status = ?", status)
+	if status == models.WordApproved {
+		// Approved contributions only; seeded and imported words have no submitter.
+		query = query.Where("submitted_by IS NOT NULL")
+	}
+
+	var total int64
+	query.Count(&total)
+
+	order := "created_at ASC"
+	if status != models.WordPending {
+		order = "reviewed_at DESC"
+	}
+
+	var words []models.TamilWord
+	if err := query.Order(order).Limit(limit).Offset(offset).Find(&words).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch review queue"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"words":  words,
+		"total":  total,
+		"limit":  limit,
+		"offset": offset,
+	})
+}
+
+// ReviewEditTamilWord corrects a contributed word without changing its status
+// PUT /api/v1/review/tamil-words/:id
+func (h *Handlers) ReviewEditTamilWord(c *gin.Context) {
+	word, ok := h.loadReviewWord(c)
+	if !ok {
+		return
+	}
+
+	var req ReviewWordEdit
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if strings.TrimSpace(req.Reason) == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "A reason is required when editing a word"})
+		return
+	}
+
+	if !h.applyReviewEdit(c, word, &req) {
+		return
+	}
+	h.saveReviewDecision(c, word, word.ReviewStatus, req.Reason, "tamil_word.review_edited")
+}
+
+// ReviewApproveTamilWord approves a word, optionally applying edits first
+// POST /api/v1/review/tamil-words/:id/approve
+func (h *Handlers) ReviewApproveTamilWord(c *gin.Context) {
+	word, ok := h.loadReviewWord(c)
+	if !ok {
+		return
+	}
+
+	var req ReviewWordEdit
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	if !h.applyReviewEdit(c, word, &req) {
+		return
+	}
+	word.IsVerified = true
+	h.saveReviewDecision(c, word, models.WordApproved, req.Reason, "tamil_word.review_approved")
+}
+
+// ReviewRejectTamilWord rejects a word with a reason shown to the contributor
+// POST /api/v1/review/tamil-words/:id/reject
+func (h *Handlers) ReviewRejectTamilWord(c *gin.Context) {
+	word, ok := h.loadReviewWord(c)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		Reason string `json:"reason" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "A reason is required when rejecting a word"})
+		return
+	}
+
+	word.IsVerified = false
+	h.saveReviewDecision(c, word, models.WordRejected, req.Reason, "tamil_word.review_rejected")
+}
+
+func (h *Handlers) loadReviewWord(c *gin.Context) (*models.TamilWord, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid word ID"})
+		return nil, false
+	}
+
+	var word models.TamilWord
+	if err := h.db.First(&word, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Word not found"})
+			return nil, false
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch word"})
+		return nil, false
+	}
+	return &word, true
+}
+
+// applyReviewEdit validates and applies edits in memory. It writes the error
+// response and returns false when the edit is invalid.
+func (h *Handlers) applyReviewEdit(c *gin.Context, word *models.TamilWord, req *ReviewWordEdit) bool {
+	if req.TamilText != nil {
+		word.TamilText = strings.TrimSpace(*req.TamilText)
+	}
+	if req.Transliteration != nil {
+		translit := strings.ToLower(strings.TrimSpace(*req.Transliteration))
+		if translit != word.Transliteration {
+			var count int64
+			h.db.Model(&models.TamilWord{}).Where("transliteration = ? AND id <> ?", translit, word.ID).Count(&count)
+			if count > 0 {
+				c.JSON(http.StatusConflict, gin.H{"error": "Another word already uses this transliteration"})
+				return false
+			}
+		}
+		word.Transliteration = translit
+	}
+	if err := moderation.ValidateScript(word.TamilText, word.Transliteration); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return false
+	}
+
+	if req.AlternateSpellings != nil {
+		word.SetAlternates(req.AlternateSpellings)
+	}
+	if req.Category != nil {
+		word.Category = models.WordCategory(strings.TrimSpace(*req.Category))
+	}
+	if req.Meaning != nil {
+		word.Meaning = strings.TrimSpace(*req.Meaning)
+	}
+	if req.Example != nil {
+		word.Example = strings.TrimSpace(*req.Example)
+	}
+	if req.Frequency != nil {
+		word.Frequency = *req.Frequency
+	}
+	return true
+}
+
+func (h *Handlers) saveReviewDecision(c *gin.Context, word *models.TamilWord, status models.WordReviewStatus, reason, event string) {
+	reviewerID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	now := time.Now()
+	word.ReviewStatus = status
+	word.ReviewedBy = &reviewerID
+	word.ReviewedAt = &now
+	if reason = strings.TrimSpace(reason); reason != "" {
+		word.ReviewNote = reason
+	}
+
+	if err := h.db.Save(word).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save review decision"})
+		return
+	}
+
+	h.hunspellGenerator.Invalidate()
+
+	auditlog.Info(c, event, map[string]any{
+		"word_id":     word.ID,
+		"reviewer_id": reviewerID,
+		"status":      status,
+	})
+
+	c.JSON(http.StatusOK, gin.H{"word": word})
+}
@@ -127,6 +127,37 @@ func AdminMiddleware(db *gorm.DB) gin.HandlerFunc {
 	}
 }
 
+// ReviewerMiddleware allows reviewers and admins through, e.g. for the word
+// moderation queue.
+func ReviewerMiddleware(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, exists := c.Get("user_id")
+		if !exists {
+			auditlog.Warn(c, "auth.reviewer_missing_user", nil)
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "User ID not found"})
+			c.Abort()
+			return
+		}
+
+		var user models.User
+		if err := db.First(&user, userID).Error; err != nil {
+			auditlog.Warn(c, "auth.reviewer_user_not_found", map[string]any{"user_id": userID})
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+			c.Abort()
+			return
+		}
+
+		if user.Role != models.RoleReviewer && user.Role != models.RoleAdmin {
+			auditlog.Warn(c, "auth.reviewer_forbidden", map[string]any{"user_id": user.ID})
+			c.JSON(http.StatusForbidden, gin.H{"error": "Reviewer access required"})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
 // GetUserFromContext extracts user ID from context (set by AuthMiddleware)
 func GetUserFromContext(c *gin.Context) (uint, error) {
 	userID, exists := c.Get("user_id")
@@ -0,0 +1,36 @@
+package models
+
+import (
+	"time"
+)
+
+type BatchStatus string
+
+const (
+	BatchProcessing BatchStatus = "processing"
+	BatchCompleted  BatchStatus = "completed"
+)
+
+// Batch is a set of items submitted for proofreading in one request. Each
+// item is proofread as its own submission; a batch is completed once every
+// item has completed or failed.
+type Batch struct {
+	ID          uint        `gorm:"primaryKey" json:"id"`
+	UserID      uint        `gorm:"not null;index" json:"user_id"`
+	Name        string      `gorm:"size:255" json:"name,omitempty"`
+	Status      BatchStatus `gorm:"size:16;not null;default:'processing'" json:"status"`
+	Total       int         `gorm:"not null" json:"total"`
+	CompletedAt *time.Time  `json:"completed_at,omitempty"`
+	CreatedAt   time.Time   `json:"created_at"`
+	UpdatedAt   time.Time   `json:"updated_at"`
+}
+
+// BatchItem links one item of a batch to its submission. ClientID is the
+// caller's own ID for the item, unique within the batch.
+type BatchItem struct {
+	ID           uint   `gorm:"primaryKey" json:"id"`
+	BatchID      uint   `gorm:"not null;uniqueIndex:idx_batch_client" json:"batch_id"`
+	ClientID     string `gorm:"size:128;not null;uniqueIndex:idx_batch_client" json:"client_id"`
+	Position     int    `gorm:"not null" json:"position"`
+	SubmissionID uint   `gorm:"not null;index" json:"submission_id"`
+}
@@ -0,0 +1,27 @@
+package models
+
+import (
+	"time"
+)
+
+type CodeMixSource string
+
+const (
+	CodeMixSeed  CodeMixSource = "seed"
+	CodeMixAdmin CodeMixSource = "admin"
+	CodeMixLLM   CodeMixSource = "llm"
+)
+
+// CodeMixMapping maps an English word to the Tamil-script spellings writers
+// use for it (e.g. மீட்டிங்) and to Tamil equivalents, best first.
+type CodeMixMapping struct {
+	ID          uint          `gorm:"primaryKey" json:"id"`
+	English     string        `gorm:"size:100;not null;uniqueIndex" json:"english"`
+	Spellings   []string      `gorm:"serializer:json;type:jsonb" json:"spellings"`
+	Equivalents []string      `gorm:"serializer:json;type:jsonb" json:"equivalents"`
+	Source      CodeMixSource `gorm:"size:20;default:'admin'" json:"source"`
+	IsActive    bool          `gorm:"default:true" json:"is_active"`
+	UpdatedBy   *uint         `json:"updated_by,omitempty"`
+	CreatedAt   time.Time     `json:"created_at"`
+	UpdatedAt   time.Time     `json:"updated_at"`
+}
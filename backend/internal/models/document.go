@@ -0,0 +1,35 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Document groups the revisions of one piece of writing. CurrentRevision is
+// the number of the latest revision.
+type Document struct {
+	ID              uint           `gorm:"primaryKey" json:"id"`
+	UserID          uint           `gorm:"not null;index" json:"user_id"`
+	Title           string         `gorm:"size:255;not null" json:"title"`
+	CurrentRevision int            `gorm:"not null;default:0" json:"current_revision"`
+	CreatedAt       time.Time      `json:"created_at"`
+	UpdatedAt       time.Time      `json:"updated_at"`
+	DeletedAt       gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+// DocumentRevision is one saved state of a document, numbered from 1.
+// SubmissionID is set when the revision was sent for proofreading, and
+// RestoredFrom when it was created by restoring an earlier revision.
+type DocumentRevision struct {
+	ID           uint      `gorm:"primaryKey" json:"id"`
+	DocumentID   uint      `gorm:"not null;uniqueIndex:idx_document_revision" json:"document_id"`
+	Number       int       `gorm:"not null;uniqueIndex:idx_document_revision" json:"number"`
+	Text         string    `gorm:"type:text;not null" json:"text"`
+	HTML         string    `gorm:"type:text" json:"html,omitempty"`
+	WordCount    int       `gorm:"not null;default:0" json:"word_count"`
+	Note         string    `gorm:"size:255" json:"note,omitempty"`
+	SubmissionID *uint     `gorm:"index" json:"submission_id,omitempty"`
+	RestoredFrom *int      `json:"restored_from,omitempty"`
+	CreatedAt    time.Time `json:"created_at"`
+}
@@ -0,0 +1,31 @@
+package models
+
+import "time"
+
+// Folder holds some of a user's submissions. A submission is in at most one
+// folder; deleting a folder leaves its submissions unfiled. Names are unique
+// per user, ignoring case, by an index on LOWER(name) that library.Migrate
+// creates.
+type Folder struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	UserID    uint      `gorm:"not null;index" json:"user_id"`
+	Name      string    `gorm:"size:120;not null" json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Tag labels any number of a user's submissions. Names are unique per user,
+// ignoring case, like folder names; Color is an optional #rrggbb hint for
+// clients.
+type Tag struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	UserID    uint      `gorm:"not null;index" json:"user_id"`
+	Name      string    `gorm:"size:40;not null" json:"name"`
+	Color     string    `gorm:"size:7" json:"color,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Metadata is user-defined key-value pairs on a submission, such as a
+// client or job number.
+type Metadata map[string]string
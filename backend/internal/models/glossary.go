@@ -0,0 +1,105 @@
+package models
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type GlossaryTermKind string
+
+const (
+	// TermPreferred lists variants that should be replaced by the term.
+	TermPreferred GlossaryTermKind = "preferred"
+	// TermForbidden is a term that must not appear, with an optional replacement.
+	TermForbidden GlossaryTermKind = "forbidden"
+)
+
+type GlossaryMatchMode string
+
+const (
+	// MatchWord matches whole words only.
+	MatchWord GlossaryMatchMode = "word"
+	// MatchStem also matches inflected forms (the term followed by suffixes).
+	MatchStem GlossaryMatchMode = "stem"
+)
+
+// Glossary is an organization's list of enforced terminology. Version is
+// bumped and a GlossaryVersion snapshot written on every change.
+type Glossary struct {
+	ID             uint           `gorm:"primaryKey" json:"id"`
+	OrganizationID uint           `gorm:"not null;index" json:"organization_id"`
+	Name           string         `gorm:"size:160;not null" json:"name"`
+	Description    string         `gorm:"type:text" json:"description,omitempty"`
+	IsActive       bool           `gorm:"default:true" json:"is_active"`
+	Version        int            `gorm:"not null;default:0" json:"version"`
+	CreatedAt      time.Time      `json:"created_at"`
+	UpdatedAt      time.Time      `json:"updated_at"`
+	DeletedAt      gorm.DeletedAt `gorm:"index" json:"-"`
+
+	Terms []GlossaryTerm `gorm:"foreignKey:GlossaryID" json:"terms,omitempty"`
+}
+
+// GlossaryTerm is one entry. For preferred terms, Variants are the spellings
+// to replace with Term; for forbidden terms, Term is flagged and Replacement
+// suggested when set.
+type GlossaryTerm struct {
+	ID          uint              `gorm:"primaryKey" json:"id"`
+	GlossaryID  uint              `gorm:"not null;index" json:"glossary_id"`
+	Term        string            `gorm:"size:255;not null" json:"term"`
+	Kind        GlossaryTermKind  `gorm:"size:20;not null;default:'preferred'" json:"kind"`
+	Variants    []string          `gorm:"serializer:json;type:jsonb" json:"variants"`
+	Replacement string            `gorm:"size:255" json:"replacement,omitempty"`
+	MatchMode   GlossaryMatchMode `gorm:"size:20;default:'word'" json:"match_mode"`
+	Note        string            `gorm:"type:text" json:"note,omitempty"`
+	CreatedAt   time.Time         `json:"created_at"`
+	UpdatedAt   time.Time         `json:"updated_at"`
+}
+
+// Normalize trims fields, fills defaults and drops variants equal to the term.
+func (t *GlossaryTerm) Normalize() {
+	t.Term = strings.TrimSpace(t.Term)
+	t.Replacement = strings.TrimSpace(t.Replacement)
+	t.Note = strings.TrimSpace(t.Note)
+	if t.Kind == "" {
+		t.Kind = TermPreferred
+	}
+	if t.MatchMode == "" {
+		t.MatchMode = MatchWord
+	}
+
+	seen := map[string]bool{t.Term: true}
+	variants := make([]string, 0, len(t.Variants))
+	for _, v := range t.Variants {
+		v = strings.TrimSpace(v)
+		if v == "" || seen[v] {
+			continue
+		}
+		seen[v] = true
+		variants = append(variants, v)
+	}
+	t.Variants = variants
+}
+
+// SnapshotTerms encodes terms for a GlossaryVersion.
+func SnapshotTerms(terms []GlossaryTerm) (string, error) {
+	data, err := json.Marshal(terms)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// GlossaryVersion is a snapshot of a glossary's terms after a change.
+type GlossaryVersion struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	GlossaryID uint      `gorm:"not null;uniqueIndex:idx_glossary_version" json:"glossary_id"`
+	Version    int       `gorm:"not null;uniqueIndex:idx_glossary_version" json:"version"`
+	Terms      string    `gorm:"type:jsonb;not null" json:"terms"`
+	TermCount  int       `json:"term_count"`
+	Summary    string    `gorm:"size:500" json:"summary"`
+	CreatedBy  uint      `json:"created_by"`
+	CreatedAt  time.Time `json:"created_at"`
+}
@@ -0,0 +1,18 @@
+package models
+
+import (
+	"time"
+)
+
+// GranthaMapping maps a word spelled with grantha letters or of Sanskrit
+// origin to pure Tamil (தனித்தமிழ்) alternatives, best first.
+type GranthaMapping struct {
+	ID           uint      `gorm:"primaryKey" json:"id"`
+	Word         string    `gorm:"size:255;not null;uniqueIndex" json:"word"`
+	Alternatives []string  `gorm:"serializer:json;type:jsonb;not null" json:"alternatives"`
+	Note         string    `gorm:"type:text" json:"note,omitempty"`
+	IsActive     bool      `gorm:"default:true" json:"is_active"`
+	UpdatedBy    *uint     `json:"updated_by,omitempty"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
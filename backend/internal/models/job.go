@@ -0,0 +1,50 @@
+package models
+
+import (
+	"time"
+)
+
+type JobState string
+
+const (
+	JobQueued  JobState = "queued"
+	JobRunning JobState = "running"
+	JobDone    JobState = "done"
+	// JobCancelled is a job stopped because its submission was cancelled.
+	JobCancelled JobState = "cancelled"
+	// JobDead is the dead-letter state of a job that failed every attempt.
+	JobDead JobState = "dead"
+)
+
+// Job is a queued request to proofread a submission. A running job is
+// leased to one worker until LockedUntil; a worker that stops renewing its
+// lease, for example because the process restarted, loses the job to the
+// next worker. FinishedAt is set once the job is done, cancelled or dead,
+// and a submission has at most one unfinished job.
+//
+// The scheduling fields are fixed when the job is queued from the owner's
+// plan: Priority picks the lane, MaxConcurrent caps the owner's running
+// jobs (0 is no cap), and VirtualStart and VirtualFinish are the job's
+// weighted fair queuing tags, with Cost being its word count.
+type Job struct {
+	ID            uint             `gorm:"primaryKey" json:"id"`
+	SubmissionID  uint             `gorm:"not null;uniqueIndex:idx_jobs_active_submission,where:finished_at IS NULL" json:"submission_id"`
+	UserID        uint             `gorm:"index" json:"user_id"`
+	Plan          SubscriptionPlan `gorm:"size:16" json:"plan"`
+	Priority      int              `gorm:"not null;default:0" json:"priority"`
+	MaxConcurrent int              `gorm:"not null;default:0" json:"max_concurrent"`
+	Cost          int              `gorm:"not null;default:0" json:"cost"`
+	VirtualStart  float64          `gorm:"not null;default:0" json:"virtual_start"`
+	VirtualFinish float64          `gorm:"not null;default:0" json:"virtual_finish"`
+	StartedAt     *time.Time       `json:"started_at,omitempty"` // first claimed; with CreatedAt gives the wait
+	State         JobState         `gorm:"size:16;not null;default:'queued';index:idx_jobs_claim,priority:1" json:"state"`
+	Attempts      int              `gorm:"not null;default:0" json:"attempts"`
+	MaxAttempts   int              `gorm:"not null" json:"max_attempts"`
+	RunAt         time.Time        `gorm:"not null;index:idx_jobs_claim,priority:2" json:"run_at"`
+	LockedBy      string           `gorm:"size:128" json:"locked_by,omitempty"`
+	LockedUntil   *time.Time       `json:"locked_until,omitempty"`
+	LastError     string           `gorm:"type:text" json:"last_error,omitempty"`
+	FinishedAt    *time.Time       `gorm:"index" json:"finished_at,omitempty"`
+	CreatedAt     time.Time        `json:"created_at"`
+	UpdatedAt     time.Time        `json:"updated_at"`
+}
@@ -0,0 +1,66 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type OrgRole string
+
+const (
+	OrgOwner  OrgRole = "owner"
+	OrgEditor OrgRole = "editor"
+	OrgMember OrgRole = "member"
+)
+
+// Organization groups users who share house style settings such as
+// glossaries.
+type Organization struct {
+	ID        uint   `gorm:"primaryKey" json:"id"`
+	Name      string `gorm:"size:160;not null" json:"name"`
+	CreatedBy uint   `gorm:"not null" json:"created_by"`
+	// DefaultStyleProfile is the style profile key used when a submission
+	// does not choose one.
+	DefaultStyleProfile string `gorm:"size:64" json:"default_style_profile,omitempty"`
+	// WebhookEvents are the members' event types sent to the organization's
+	// webhook endpoints. Sharing is opt-in, so it starts empty.
+	WebhookEvents []string       `gorm:"serializer:json;type:jsonb" json:"webhook_events"`
+	CreatedAt     time.Time      `json:"created_at"`
+	UpdatedAt     time.Time      `json:"updated_at"`
+	DeletedAt     gorm.DeletedAt `gorm:"index" json:"-"`
+
+	Members []OrganizationMember `gorm:"foreignKey:OrganizationID" json:"members,omitempty"`
+}
+
+// OrganizationMember links a user to their organization. A user belongs to
+// at most one organization.
+type OrganizationMember struct {
+	ID             uint      `gorm:"primaryKey" json:"id"`
+	OrganizationID uint      `gorm:"not null;index" json:"organization_id"`
+	UserID         uint      `gorm:"not null;uniqueIndex" json:"user_id"`
+	Role           OrgRole   `gorm:"size:20;default:'member'" json:"role"`
+	CreatedAt      time.Time `json:"created_at"`
+
+	User User `gorm:"foreignKey:UserID" json:"user,omitempty"`
+}
+
+// CanManage reports whether the member may edit organization settings.
+func (m *OrganizationMember) CanManage() bool {
+	return m.Role == OrgOwner || m.Role == OrgEditor
+}
+
+// OrganizationInvite offers a user membership in an organization. The user
+// joins only by accepting it.
+type OrganizationInvite struct {
+	ID             uint      `gorm:"primaryKey" json:"id"`
+	OrganizationID uint      `gorm:"not null;uniqueIndex:idx_org_invite" json:"organization_id"`
+	UserID         uint      `gorm:"not null;uniqueIndex:idx_org_invite;index" json:"user_id"`
+	Role           OrgRole   `gorm:"size:20;default:'member'" json:"role"`
+	InvitedBy      uint      `gorm:"not null" json:"invited_by"`
+	ExpiresAt      time.Time `gorm:"not null" json:"expires_at"`
+	CreatedAt      time.Time `json:"created_at"`
+
+	Organization Organization `gorm:"foreignKey:OrganizationID" json:"organization,omitempty"`
+	User         User         `gorm:"foreignKey:UserID" json:"user,omitempty"`
+}
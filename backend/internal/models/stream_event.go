@@ -0,0 +1,16 @@
+package models
+
+import (
+	"time"
+)
+
+// StreamEvent is a recent event of a submission or batch stream, kept
+// briefly so clients that reconnect can replay what they missed and so
+// every server instance can read events published by the others.
+type StreamEvent struct {
+	ID        int64     `gorm:"primaryKey;index:idx_stream_events_topic,priority:2" json:"id"`
+	Topic     string    `gorm:"size:64;not null;index:idx_stream_events_topic,priority:1" json:"topic"`
+	Event     string    `gorm:"size:32;not null" json:"event"`
+	Data      string    `gorm:"type:jsonb;not null" json:"data"`
+	CreatedAt time.Time `gorm:"index" json:"created_at"`
+}
@@ -0,0 +1,54 @@
+package models
+
+import (
+	"time"
+)
+
+type StyleRegister string
+
+const (
+	// RegisterLiterary is formal written Tamil (செந்தமிழ்).
+	RegisterLiterary StyleRegister = "literary"
+	// RegisterStandard is plain modern written Tamil, as in newspapers.
+	RegisterStandard StyleRegister = "standard"
+	// RegisterColloquial keeps spoken forms (பேச்சுத்தமிழ்).
+	RegisterColloquial StyleRegister = "colloquial"
+)
+
+// StylePolicy controls how grantha letters or loanwords are treated.
+type StylePolicy string
+
+const (
+	PolicyAllow       StylePolicy = "allow"
+	PolicyPreferTamil StylePolicy = "prefer_tamil"
+	PolicyAvoid       StylePolicy = "avoid"
+)
+
+type QuoteStyle string
+
+const (
+	QuotesAny      QuoteStyle = "any"
+	QuotesCurly    QuoteStyle = "curly"
+	QuotesStraight QuoteStyle = "straight"
+)
+
+// StyleProfile is a named set of proofreading conventions. The built-in
+// profiles live in code; organizations may add their own.
+type StyleProfile struct {
+	ID             uint          `gorm:"primaryKey" json:"id,omitempty"`
+	OrganizationID *uint         `gorm:"uniqueIndex:idx_style_profile_key" json:"organization_id,omitempty"`
+	Key            string        `gorm:"size:64;not null;uniqueIndex:idx_style_profile_key" json:"key"`
+	Name           string        `gorm:"size:160;not null" json:"name"`
+	Description    string        `gorm:"type:text" json:"description,omitempty"`
+	Register       StyleRegister `gorm:"size:20;not null;default:'standard'" json:"register"`
+	GranthaPolicy  StylePolicy   `gorm:"size:20;not null;default:'allow'" json:"grantha_policy"`
+	LoanwordPolicy StylePolicy   `gorm:"size:20;not null;default:'allow'" json:"loanword_policy"`
+	QuoteStyle     QuoteStyle    `gorm:"size:20;not null;default:'any'" json:"quote_style"`
+	// AllowSpaceBeforePunct disables the check for spaces before , . ? ! ; :
+	AllowSpaceBeforePunct bool `gorm:"default:false" json:"allow_space_before_punct"`
+	// Instructions are extra free-text guidance passed to the model.
+	Instructions string    `gorm:"type:text" json:"instructions,omitempty"`
+	Builtin      bool      `gorm:"-" json:"builtin"`
+	CreatedAt    time.Time `json:"created_at,omitempty"`
+	UpdatedAt    time.Time `json:"updated_at,omitempty"`
+}
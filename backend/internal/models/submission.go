@@ -13,6 +13,7 @@ const (
         StatusProcessing SubmissionStatus = "processing"
         StatusCompleted  SubmissionStatus = "completed"
         StatusFailed     SubmissionStatus = "failed"
+        StatusCancelled  SubmissionStatus = "cancelled"
 )
 
 type ModelType string
@@ -25,16 +26,26 @@ const (
 type Submission struct {
         ID                  uint             `gorm:"primaryKey" json:"id"`
         UserID              uint             `gorm:"not null;index" json:"user_id"`
+        DocumentID          *uint            `gorm:"index" json:"document_id,omitempty"`
         OriginalText        string           `gorm:"type:text;not null" json:"original_text"`
         OriginalHTML        string           `gorm:"type:text" json:"original_html,omitempty"`
         RequestID           string           `gorm:"size:64;index" json:"request_id,omitempty"`
+        Title               string           `gorm:"size:255" json:"title,omitempty"`
+        FolderID            *uint            `gorm:"index" json:"folder_id,omitempty"`
+        Metadata            Metadata         `gorm:"serializer:json;type:jsonb" json:"metadata,omitempty"`
         ProofreadText       string           `gorm:"type:text" json:"proofread_text,omitempty"`
+        FinalText           string           `gorm:"type:text" json:"final_text,omitempty"` // OriginalText with accepted suggestions applied
         WordCount           int              `gorm:"not null" json:"word_count"`
         ModelUsed           ModelType        `gorm:"not null" json:"model_used"`
+        Model               string           `gorm:"size:64" json:"model,omitempty"` // Gemini model requested on reprocess; empty chooses by length
         Status              SubmissionStatus `gorm:"default:'pending'" json:"status"`
         Suggestions         string           `gorm:"type:jsonb" json:"suggestions,omitempty"` // JSON array of suggestions
         Alternatives        string           `gorm:"type:jsonb" json:"alternatives,omitempty"`
         IncludeAlternatives bool             `gorm:"default:false" json:"include_alternatives"`
+        StyleProfile        string           `gorm:"size:64" json:"style_profile,omitempty"`
+        PureTamil           bool             `gorm:"default:false" json:"pure_tamil"`
+        CodeMixCount        int              `gorm:"default:0" json:"code_mix_count"`
+        ReprocessCount      int              `gorm:"default:0" json:"reprocess_count"`
         Error               string           `gorm:"type:text" json:"error,omitempty"`
         ProcessingTime      *float64         `json:"processing_time,omitempty"`
         Cost                float64          `gorm:"default:0" json:"cost"`
@@ -45,7 +56,8 @@ type Submission struct {
         DeletedAt           gorm.DeletedAt   `gorm:"index" json:"-"`
 
         // Relationships
-        User User `gorm:"foreignKey:UserID" json:"user,omitempty"`
+        User User  `gorm:"foreignKey:UserID" json:"user,omitempty"`
+        Tags []Tag `gorm:"many2many:submission_tags" json:"tags,omitempty"`
 }
 
 type ContactMessage struct {
@@ -0,0 +1,22 @@
+package models
+
+import (
+	"time"
+)
+
+// SubmissionFile is the uploaded file a submission's text was extracted
+// from, kept so corrections can be written back into it. TextOffset is the
+// number of leading bytes trimmed from the extracted text, so suggestion
+// offsets map back to the file's text.
+type SubmissionFile struct {
+	ID           uint      `gorm:"primaryKey" json:"id"`
+	SubmissionID uint      `gorm:"not null;uniqueIndex" json:"submission_id"`
+	UserID       uint      `gorm:"not null;index" json:"user_id"`
+	Filename     string    `gorm:"size:255;not null" json:"filename"`
+	Format       string    `gorm:"size:20;not null" json:"format"`
+	ContentType  string    `gorm:"size:120" json:"content_type"`
+	Size         int       `gorm:"not null" json:"size"`
+	TextOffset   int       `gorm:"not null;default:0" json:"-"`
+	Data         []byte    `gorm:"type:bytea;not null" json:"-"`
+	CreatedAt    time.Time `json:"created_at"`
+}
@@ -0,0 +1,37 @@
+package models
+
+import (
+	"time"
+)
+
+type SuggestionState string
+
+const (
+	SuggestionPending  SuggestionState = "pending"
+	SuggestionAccepted SuggestionState = "accepted"
+	SuggestionRejected SuggestionState = "rejected"
+)
+
+// SubmissionSuggestion is one suggested edit to a submission. Offsets are
+// byte offsets into the submission's OriginalText; they are -1 when the
+// suggestion could not be located in the text and so cannot be applied.
+type SubmissionSuggestion struct {
+	ID           uint            `gorm:"primaryKey" json:"id"`
+	SubmissionID uint            `gorm:"not null;index" json:"submission_id"`
+	Position     int             `gorm:"not null" json:"position"`
+	Original     string          `gorm:"type:text;not null" json:"original"`
+	Corrected    string          `gorm:"type:text" json:"corrected"`
+	Reason       string          `gorm:"type:text" json:"reason"`
+	Type         string          `gorm:"size:50;index" json:"type"`
+	StartIndex   int             `gorm:"not null" json:"start_index"`
+	EndIndex     int             `gorm:"not null" json:"end_index"`
+	State        SuggestionState `gorm:"size:20;default:'pending';index" json:"state"`
+	DecidedAt    *time.Time      `json:"decided_at,omitempty"`
+	CreatedAt    time.Time       `json:"created_at"`
+	UpdatedAt    time.Time       `json:"updated_at"`
+}
+
+// Applicable reports whether the suggestion's offsets are usable.
+func (s SubmissionSuggestion) Applicable() bool {
+	return s.StartIndex >= 0 && s.EndIndex >= s.StartIndex
+}
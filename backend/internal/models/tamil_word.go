@@ -1,6 +1,8 @@
 package models
 
 import (
+        "encoding/json"
+        "strings"
         "time"
 
         "gorm.io/gorm"
@@ -22,6 +24,14 @@ const (
         CategoryPhrase      WordCategory = "phrase"
 )
 
+type WordReviewStatus string
+
+const (
+        WordPending  WordReviewStatus = "pending"
+        WordApproved WordReviewStatus = "approved"
+        WordRejected WordReviewStatus = "rejected"
+)
+
 type TamilWord struct {
         ID             uint         `gorm:"primaryKey" json:"id"`
         TamilText      string       `gorm:"size:255;not null;index:idx_tamil_text" json:"tamil_text"`
@@ -34,6 +44,12 @@ type TamilWord struct {
         IsVerified     bool         `gorm:"default:false;index:idx_verified" json:"is_verified"` // Human-verified vs AI-generated
         Source         string       `gorm:"size:100" json:"source,omitempty"` // e.g., "manual", "wiktionary", "ai_gemini", "user_confirmed"
         UserConfirmed  int          `gorm:"default:0" json:"user_confirmed"` // How many times users selected this transliteration
+        ReviewStatus   WordReviewStatus `gorm:"size:20;default:'approved';index:idx_review_status" json:"review_status"` // Only approved words are served
+        SubmittedBy    *uint        `gorm:"index" json:"submitted_by,omitempty"` // Contributor for user-submitted words
+        ReviewedBy     *uint        `json:"reviewed_by,omitempty"`
+        ReviewedAt     *time.Time   `json:"reviewed_at,omitempty"`
+        ReviewNote     string       `gorm:"type:text" json:"review_note,omitempty"` // Reviewer's reason for approve/reject/edit
+        CheckFlags     string       `gorm:"type:text" json:"check_flags,omitempty"` // JSON array of automatic check findings
         CreatedAt      time.Time    `json:"created_at"`
         UpdatedAt      time.Time    `json:"updated_at"`
         DeletedAt      gorm.DeletedAt `gorm:"index" json:"-"`
@@ -43,3 +59,55 @@ type TamilWord struct {
 func (TamilWord) TableName() string {
         return "tamil_words"
 }
+
+// Alternates decodes AlternateSpellings, which is stored either as a JSON array
+// (seeder, lexicon tool) or as a comma-separated list (legacy AddTamilWord rows).
+func (w *TamilWord) Alternates() []string {
+        raw := strings.TrimSpace(w.AlternateSpellings)
+        if raw == "" {
+                return nil
+        }
+
+        var values []string
+        if strings.HasPrefix(raw, "[") {
+                if err := json.Unmarshal([]byte(raw), &values); err != nil {
+                        return nil
+                }
+        } else {
+                values = strings.Split(raw, ",")
+        }
+
+        result := make([]string, 0, len(values))
+        for _, v := range values {
+                v = strings.ToLower(strings.TrimSpace(v))
+                if v != "" {
+                        result = append(result, v)
+                }
+        }
+        return result
+}
+
+// SetAlternates stores alternates as a JSON array, dropping blanks, duplicates
+// and the primary transliteration itself.
+func (w *TamilWord) SetAlternates(values []string) {
+        seen := map[string]struct{}{strings.ToLower(w.Transliteration): {}}
+        cleaned := make([]string, 0, len(values))
+        for _, v := range values {
+                v = strings.ToLower(strings.TrimSpace(v))
+                if v == "" {
+                        continue
+                }
+                if _, ok := seen[v]; ok {
+                        continue
+                }
+                seen[v] = struct{}{}
+                cleaned = append(cleaned, v)
+        }
+
+        if len(cleaned) == 0 {
+                w.AlternateSpellings = ""
+                return
+        }
+        encoded, _ := json.Marshal(cleaned)
+        w.AlternateSpellings = string(encoded)
+}
@@ -0,0 +1,49 @@
+package models
+
+import (
+	"time"
+)
+
+type DictionaryEntryKind string
+
+const (
+	DictionaryWord  DictionaryEntryKind = "word"
+	DictionaryName  DictionaryEntryKind = "name"
+	DictionaryBrand DictionaryEntryKind = "brand"
+)
+
+type IgnoreMatchType string
+
+const (
+	IgnoreExact  IgnoreMatchType = "exact"
+	IgnorePrefix IgnoreMatchType = "prefix"
+	IgnoreRegex  IgnoreMatchType = "regex"
+)
+
+// UserDictionaryEntry is a word the user never wants corrected, such as a
+// proper noun, technical term or brand spelling.
+type UserDictionaryEntry struct {
+	ID        uint                `gorm:"primaryKey" json:"id"`
+	UserID    uint                `gorm:"not null;uniqueIndex:idx_user_dictionary_word" json:"user_id"`
+	Word      string              `gorm:"size:255;not null;uniqueIndex:idx_user_dictionary_word" json:"word"`
+	Kind      DictionaryEntryKind `gorm:"size:20;default:'word'" json:"kind"`
+	Note      string              `gorm:"size:500" json:"note,omitempty"`
+	CreatedAt time.Time           `json:"created_at"`
+	UpdatedAt time.Time           `json:"updated_at"`
+
+	User User `gorm:"foreignKey:UserID" json:"-"`
+}
+
+// UserIgnoreRule drops suggestions whose original text matches Pattern,
+// optionally only for one suggestion type (e.g. "punctuation").
+type UserIgnoreRule struct {
+	ID             uint            `gorm:"primaryKey" json:"id"`
+	UserID         uint            `gorm:"not null;index" json:"user_id"`
+	Pattern        string          `gorm:"size:255;not null" json:"pattern"`
+	MatchType      IgnoreMatchType `gorm:"size:20;default:'exact'" json:"match_type"`
+	SuggestionType string          `gorm:"size:50" json:"suggestion_type,omitempty"`
+	Note           string          `gorm:"size:500" json:"note,omitempty"`
+	CreatedAt      time.Time       `json:"created_at"`
+
+	User User `gorm:"foreignKey:UserID" json:"-"`
+}
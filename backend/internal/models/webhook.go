@@ -0,0 +1,97 @@
+package models
+
+import (
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Webhook event types.
+const (
+	EventSubmissionCompleted = "submission.completed"
+	EventSubmissionFailed    = "submission.failed"
+	EventSubmissionCancelled = "submission.cancelled"
+	EventBatchCompleted      = "batch.completed"
+	EventPaymentCompleted    = "payment.completed"
+	EventPaymentFailed       = "payment.failed"
+)
+
+// WebhookEvents lists every event an endpoint can subscribe to.
+var WebhookEvents = []string{
+	EventSubmissionCompleted,
+	EventSubmissionFailed,
+	EventSubmissionCancelled,
+	EventBatchCompleted,
+	EventPaymentCompleted,
+	EventPaymentFailed,
+}
+
+// OrgShareableEvent reports whether an event may be sent to organization
+// endpoints. Payment events go only to the paying user's own endpoints.
+func OrgShareableEvent(event string) bool {
+	return !strings.HasPrefix(event, "payment.")
+}
+
+// WebhookEndpoint is a URL that is sent the events it subscribes to. It
+// belongs to either a user or an organization; an organization's endpoints
+// receive members' events only of the types the organization's owners have
+// opted in to sharing. Secret signs each delivery.
+type WebhookEndpoint struct {
+	ID             uint           `gorm:"primaryKey" json:"id"`
+	UserID         *uint          `gorm:"index" json:"user_id,omitempty"`
+	OrganizationID *uint          `gorm:"index" json:"organization_id,omitempty"`
+	CreatedBy      uint           `gorm:"not null" json:"created_by"`
+	URL            string         `gorm:"size:2048;not null" json:"url"`
+	Description    string         `gorm:"size:255" json:"description,omitempty"`
+	Events         []string       `gorm:"serializer:json;type:jsonb;not null" json:"events"`
+	Secret         string         `gorm:"size:128;not null" json:"-"`
+	Active         bool           `gorm:"not null;default:true" json:"active"`
+	CreatedAt      time.Time      `json:"created_at"`
+	UpdatedAt      time.Time      `json:"updated_at"`
+	DeletedAt      gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+// Subscribes reports whether the endpoint wants the event.
+func (e *WebhookEndpoint) Subscribes(event string) bool {
+	for _, name := range e.Events {
+		if name == event || name == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+type DeliveryState string
+
+const (
+	DeliveryPending    DeliveryState = "pending"
+	DeliveryDelivering DeliveryState = "delivering"
+	DeliverySucceeded  DeliveryState = "succeeded"
+	// DeliveryFailed is a delivery that failed every attempt.
+	DeliveryFailed DeliveryState = "failed"
+)
+
+// WebhookDelivery is one event sent to one endpoint, with the outcome of
+// its latest attempt. EventID is shared by every delivery of the same
+// event, including replays, so receivers can drop duplicates.
+type WebhookDelivery struct {
+	ID            uint          `gorm:"primaryKey" json:"id"`
+	EndpointID    uint          `gorm:"not null;index" json:"endpoint_id"`
+	EventID       string        `gorm:"size:64;not null;index" json:"event_id"`
+	Event         string        `gorm:"size:64;not null" json:"event"`
+	Payload       string        `gorm:"type:jsonb;not null" json:"payload"`
+	State         DeliveryState `gorm:"size:16;not null;default:'pending';index:idx_webhook_deliveries_due,priority:1" json:"state"`
+	Attempts      int           `gorm:"not null;default:0" json:"attempts"`
+	NextAttemptAt time.Time     `gorm:"not null;index:idx_webhook_deliveries_due,priority:2" json:"next_attempt_at"`
+	LockedUntil   *time.Time    `json:"-"`
+	ReplayOf      *uint         `json:"replay_of,omitempty"`
+	// The latest attempt's response, or Error if there was none
+	ResponseStatus int        `json:"response_status,omitempty"`
+	ResponseBody   string     `gorm:"type:text" json:"response_body,omitempty"`
+	Error          string     `gorm:"type:text" json:"error,omitempty"`
+	DurationMS     int64      `json:"duration_ms,omitempty"`
+	DeliveredAt    *time.Time `json:"delivered_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
@@ -0,0 +1,235 @@
+// Package batch tracks batches of submissions proofread together and
+// combines their results.
+package batch
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"tamil-proofreading-platform/backend/internal/models"
+	"tamil-proofreading-platform/backend/internal/services/suggestions"
+
+	"gorm.io/gorm"
+)
+
+// MaxItems is the most items one batch may hold.
+const MaxItems = 500
+
+// Progress counts a batch's items by submission status.
+type Progress struct {
+	Total      int `json:"total"`
+	Pending    int `json:"pending"`
+	Processing int `json:"processing"`
+	Completed  int `json:"completed"`
+	Failed     int `json:"failed"`
+	Cancelled  int `json:"cancelled"`
+}
+
+// Done reports whether every item has completed, failed or been cancelled.
+func (p Progress) Done() bool {
+	return p.Completed+p.Failed+p.Cancelled >= p.Total
+}
+
+// Item is one item of a batch with the status of its submission.
+type Item struct {
+	ClientID     string                  `json:"client_id"`
+	SubmissionID uint                    `json:"submission_id"`
+	Status       models.SubmissionStatus `json:"status"`
+	Error        string                  `json:"error,omitempty"`
+}
+
+// Result is an item with its text, corrected text and suggestions.
+type Result struct {
+	Item
+	Text          string                        `json:"text"`
+	CorrectedText string                        `json:"corrected_text"`
+	Suggestions   []models.SubmissionSuggestion `json:"suggestions"`
+}
+
+type BatchService struct {
+	db *gorm.DB
+}
+
+func NewBatchService(db *gorm.DB) *BatchService {
+	return &BatchService{db: db}
+}
+
+// Get returns one of the user's batches.
+func (s *BatchService) Get(userID, batchID uint) (*models.Batch, error) {
+	var b models.Batch
+	if err := s.db.Where("id = ? AND user_id = ?", batchID, userID).First(&b).Error; err != nil {
+		return nil, err
+	}
+	return &b, nil
+}
+
+// Progress counts the batch's items by the status of their submissions.
+func (s *BatchService) Progress(batchID uint) (Progress, error) {
+	var counts []struct {
+		Status models.SubmissionStatus
+		Count  int
+	}
+	err := s.db.Table("batch_items").
+		Select("submissions.status AS status, COUNT(*) AS count").
+		Joins("JOIN submissions ON submissions.id = batch_items.submission_id").
+		Where("batch_items.batch_id = ?", batchID).
+		Group("submissions.status").
+		Scan(&counts).Error
+	if err != nil {
+		return Progress{}, err
+	}
+
+	var p Progress
+	for _, c := range counts {
+		p.Total += c.Count
+		switch c.Status {
+		case models.StatusProcessing:
+			p.Processing += c.Count
+		case models.StatusCompleted:
+			p.Completed += c.Count
+		case models.StatusFailed:
+			p.Failed += c.Count
+		case models.StatusCancelled:
+			p.Cancelled += c.Count
+		default:
+			p.Pending += c.Count
+		}
+	}
+	return p, nil
+}
+
+// Items lists the batch's items in the order they were submitted.
+func (s *BatchService) Items(batchID uint) ([]Item, error) {
+	var items []Item
+	err := s.items(batchID).
+		Select("batch_items.client_id, batch_items.submission_id, submissions.status, submissions.error").
+		Scan(&items).Error
+	return items, err
+}
+
+func (s *BatchService) items(batchID uint) *gorm.DB {
+	return s.db.Table("batch_items").
+		Joins("JOIN submissions ON submissions.id = batch_items.submission_id").
+		Where("batch_items.batch_id = ?", batchID).
+		Order("batch_items.position ASC")
+}
+
+// Finish marks the batch completed if every item is done. It returns true
+// only for the call that completed it.
+func (s *BatchService) Finish(batchID uint) (bool, error) {
+	p, err := s.Progress(batchID)
+	if err != nil || !p.Done() {
+		return false, err
+	}
+	result := s.db.Model(&models.Batch{}).
+		Where("id = ? AND status = ?", batchID, models.BatchProcessing).
+		Updates(map[string]interface{}{"status": models.BatchCompleted, "completed_at": time.Now()})
+	return result.RowsAffected == 1, result.Error
+}
+
+// Results returns every item with its suggestions. The corrected text
+// applies the accepted suggestions when acceptedOnly is set, and otherwise
+// all that were not rejected.
+func (s *BatchService) Results(batchID uint, acceptedOnly bool) ([]Result, error) {
+	var rows []struct {
+		Item
+		OriginalText string
+	}
+	err := s.items(batchID).
+		Select("batch_items.client_id, batch_items.submission_id, submissions.status, submissions.error, submissions.original_text").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]uint, len(rows))
+	for i, r := range rows {
+		ids[i] = r.SubmissionID
+	}
+	var all []models.SubmissionSuggestion
+	if len(ids) > 0 {
+		if err := s.db.Where("submission_id IN ?", ids).Order("submission_id ASC, position ASC").Find(&all).Error; err != nil {
+			return nil, err
+		}
+	}
+	bySubmission := make(map[uint][]models.SubmissionSuggestion)
+	for _, row := range all {
+		bySubmission[row.SubmissionID] = append(bySubmission[row.SubmissionID], row)
+	}
+
+	results := make([]Result, len(rows))
+	for i, r := range rows {
+		found := bySubmission[r.SubmissionID]
+		if found == nil {
+			found = []models.SubmissionSuggestion{}
+		}
+		results[i] = Result{
+			Item:          r.Item,
+			Text:          r.OriginalText,
+			CorrectedText: corrected(r.OriginalText, found, acceptedOnly),
+			Suggestions:   found,
+		}
+	}
+	return results, nil
+}
+
+func corrected(text string, rows []models.SubmissionSuggestion, acceptedOnly bool) string {
+	if !acceptedOnly {
+		applied := make([]models.SubmissionSuggestion, len(rows))
+		for i, row := range rows {
+			if row.State != models.SuggestionRejected {
+				row.State = models.SuggestionAccepted
+			}
+			applied[i] = row
+		}
+		rows = applied
+	}
+	return suggestions.Apply(text, rows)
+}
+
+// WriteCSV writes results as CSV with one row per item. Changes lists each
+// suggestion as "original → corrected", separated by "; ". The file starts
+// with a UTF-8 byte order mark so Excel shows Tamil text correctly, and text
+// cells are guarded against formula injection.
+func WriteCSV(w io.Writer, results []Result) error {
+	if _, err := io.WriteString(w, "\ufeff"); err != nil {
+		return err
+	}
+	writer := csv.NewWriter(w)
+	if err := writer.Write([]string{"client_id", "submission_id", "status", "text", "corrected_text", "suggestions", "changes", "error"}); err != nil {
+		return err
+	}
+	for _, r := range results {
+		changes := make([]string, 0, len(r.Suggestions))
+		for _, s := range r.Suggestions {
+			changes = append(changes, s.Original+" → "+s.Corrected)
+		}
+		record := []string{
+			csvText(r.ClientID),
+			strconv.FormatUint(uint64(r.SubmissionID), 10),
+			string(r.Status),
+			csvText(r.Text),
+			csvText(r.CorrectedText),
+			strconv.Itoa(len(r.Suggestions)),
+			csvText(strings.Join(changes, "; ")),
+			csvText(r.Error),
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// csvText prefixes a cell that a spreadsheet would read as a formula with
+// a quote, so client-supplied text is shown rather than run.
+func csvText(v string) string {
+	if v != "" && strings.ContainsRune("=+-@|\t\r", rune(v[0])) {
+		return "'" + v
+	}
+	return v
+}
@@ -0,0 +1,119 @@
+// Package broker carries stream events between server instances. Events are
+// published to a topic, such as one submission's stream, delivered to the
+// topic's subscribers on every instance, and kept for a short while so a
+// client that reconnects can replay what it missed.
+package broker
+
+import (
+	"encoding/json"
+	"sync"
+	"time"
+)
+
+// EndEvent is the last event of a topic. Subscribers' channels are closed
+// once it has been delivered.
+const EndEvent = "end"
+
+// DefaultRetention is how long events are kept for replay.
+const DefaultRetention = 10 * time.Minute
+
+// Message is one published event. ID increases with each event, across all
+// topics, and is what clients send back as Last-Event-ID.
+type Message struct {
+	ID    int64
+	Topic string
+	Event string
+	Data  json.RawMessage
+}
+
+// Broker publishes events to topics and delivers them to subscribers.
+type Broker interface {
+	// Publish sends an event to the topic's subscribers on every instance
+	// and records it for replay.
+	Publish(topic, event string, data any) error
+	// Subscribe returns a channel of the topic's events and a function to
+	// stop listening. A subscriber that falls behind has its channel
+	// closed rather than miss events silently; it can resubscribe and
+	// fetch what it missed with Since.
+	Subscribe(topic string) (<-chan Message, func())
+	// Since returns the topic's recorded events after the one with the
+	// given ID, oldest first.
+	Since(topic string, afterID int64) ([]Message, error)
+}
+
+// fanout delivers messages to the local subscribers of each topic.
+type fanout struct {
+	mu     sync.Mutex
+	topics map[string]map[chan Message]struct{}
+}
+
+func newFanout() *fanout {
+	return &fanout{topics: make(map[string]map[chan Message]struct{})}
+}
+
+func (f *fanout) subscribe(topic string) (<-chan Message, func()) {
+	ch := make(chan Message, 16)
+
+	f.mu.Lock()
+	if f.topics[topic] == nil {
+		f.topics[topic] = make(map[chan Message]struct{})
+	}
+	f.topics[topic][ch] = struct{}{}
+	f.mu.Unlock()
+
+	return ch, func() {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		if _, ok := f.topics[topic][ch]; ok {
+			delete(f.topics[topic], ch)
+			close(ch)
+			if len(f.topics[topic]) == 0 {
+				delete(f.topics, topic)
+			}
+		}
+	}
+}
+
+// has reports whether anyone here listens to the topic.
+func (f *fanout) has(topic string) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.topics[topic]) > 0
+}
+
+// active lists the topics with local subscribers.
+func (f *fanout) active() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	topics := make([]string, 0, len(f.topics))
+	for topic := range f.topics {
+		topics = append(topics, topic)
+	}
+	return topics
+}
+
+func (f *fanout) dispatch(msg Message) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for ch := range f.topics[msg.Topic] {
+		select {
+		case ch <- msg:
+		default:
+			// The subscriber is behind; end its subscription so the gap
+			// shows instead of events going missing
+			delete(f.topics[msg.Topic], ch)
+			close(ch)
+		}
+	}
+	if len(f.topics[msg.Topic]) == 0 {
+		delete(f.topics, msg.Topic)
+		return
+	}
+	if msg.Event == EndEvent {
+		for ch := range f.topics[msg.Topic] {
+			close(ch)
+		}
+		delete(f.topics, msg.Topic)
+	}
+}
@@ -0,0 +1,47 @@
+package broker
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSlowSubscriberIsClosed(t *testing.T) {
+	b := NewMemoryBroker(time.Minute)
+	slow, stopSlow := b.Subscribe("submission:1")
+	defer stopSlow()
+	fast, stopFast := b.Subscribe("submission:1")
+	defer stopFast()
+
+	const published = 40
+	for i := 0; i < published; i++ {
+		event := "status"
+		if i == published-1 {
+			event = EndEvent
+		}
+		if err := b.Publish("submission:1", event, i); err != nil {
+			t.Fatal(err)
+		}
+		if _, ok := <-fast; !ok {
+			t.Fatalf("subscriber that keeps up was closed after %d events", i)
+		}
+	}
+
+	var last int64
+	buffered := 0
+	for msg := range slow {
+		buffered++
+		last = msg.ID
+	}
+	if buffered >= published {
+		t.Fatalf("slow subscriber got all %d events without reading", buffered)
+	}
+
+	// What the slow subscriber missed can be replayed
+	missed, err := b.Since("submission:1", last)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if buffered+len(missed) != published {
+		t.Errorf("buffered %d + replayed %d, want %d", buffered, len(missed), published)
+	}
+}
@@ -0,0 +1,90 @@
+package broker
+
+import (
+	"encoding/json"
+	"sync"
+	"time"
+)
+
+// memoryLogSize is how many events the memory broker keeps per topic.
+const memoryLogSize = 64
+
+// MemoryBroker delivers events within this process only. It suits a single
+// instance and runs without a database.
+type MemoryBroker struct {
+	fanout    *fanout
+	retention time.Duration
+
+	mu     sync.Mutex
+	nextID int64
+	logs   map[string][]loggedMessage
+	pruned time.Time
+}
+
+type loggedMessage struct {
+	Message
+	at time.Time
+}
+
+func NewMemoryBroker(retention time.Duration) *MemoryBroker {
+	return &MemoryBroker{
+		fanout:    newFanout(),
+		retention: retention,
+		logs:      make(map[string][]loggedMessage),
+	}
+}
+
+func (b *MemoryBroker) Publish(topic, event string, data any) error {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	b.mu.Lock()
+	b.nextID++
+	msg := Message{ID: b.nextID, Topic: topic, Event: event, Data: raw}
+	now := time.Now()
+	log := append(b.logs[topic], loggedMessage{Message: msg, at: now})
+	if len(log) > memoryLogSize {
+		log = log[len(log)-memoryLogSize:]
+	}
+	b.logs[topic] = log
+	b.prune(now)
+	b.mu.Unlock()
+
+	b.fanout.dispatch(msg)
+	return nil
+}
+
+func (b *MemoryBroker) Subscribe(topic string) (<-chan Message, func()) {
+	return b.fanout.subscribe(topic)
+}
+
+func (b *MemoryBroker) Since(topic string, afterID int64) ([]Message, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var msgs []Message
+	cutoff := time.Now().Add(-b.retention)
+	for _, logged := range b.logs[topic] {
+		if logged.ID > afterID && logged.at.After(cutoff) {
+			msgs = append(msgs, logged.Message)
+		}
+	}
+	return msgs, nil
+}
+
+// prune drops topics whose latest event is past retention, at most once a
+// minute. b.mu is held.
+func (b *MemoryBroker) prune(now time.Time) {
+	if now.Sub(b.pruned) < time.Minute {
+		return
+	}
+	b.pruned = now
+	cutoff := now.Add(-b.retention)
+	for topic, log := range b.logs {
+		if log[len(log)-1].at.Before(cutoff) {
+			delete(b.logs, topic)
+		}
+	}
+}
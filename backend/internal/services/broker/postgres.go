@@ -0,0 +1,195 @@
+package broker
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"tamil-proofreading-platform/backend/internal/models"
+
+	"github.com/jackc/pgx/v5"
+	"gorm.io/gorm"
+)
+
+// notifyChannel is the Postgres channel events are announced on.
+const notifyChannel = "stream_events"
+
+// PostgresBroker shares events between every instance using the same
+// database. Each event is stored in the stream_events table and announced
+// with NOTIFY; instances LISTEN on a connection of their own and read the
+// events their subscribers want. Only the ID and topic travel in the
+// notification, so payloads are not bound by its 8000-byte limit.
+type PostgresBroker struct {
+	db        *gorm.DB
+	dsn       string
+	retention time.Duration
+	fanout    *fanout
+
+	mu       sync.Mutex
+	lastSeen int64
+}
+
+func NewPostgresBroker(db *gorm.DB, dsn string, retention time.Duration) *PostgresBroker {
+	return &PostgresBroker{
+		db:        db,
+		dsn:       dsn,
+		retention: retention,
+		fanout:    newFanout(),
+	}
+}
+
+// Start listens for events and removes those past retention until ctx is
+// cancelled. A lost listening connection is re-established, and events
+// published meanwhile are delivered once it is.
+func (b *PostgresBroker) Start(ctx context.Context) {
+	go b.listenLoop(ctx)
+	go b.pruneLoop(ctx)
+}
+
+func (b *PostgresBroker) Publish(topic, event string, data any) error {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	return b.db.Transaction(func(tx *gorm.DB) error {
+		ev := models.StreamEvent{Topic: topic, Event: event, Data: string(raw)}
+		if err := tx.Create(&ev).Error; err != nil {
+			return err
+		}
+		// Delivered when the transaction commits
+		return tx.Exec("SELECT pg_notify(?, ?)", notifyChannel, strconv.FormatInt(ev.ID, 10)+" "+topic).Error
+	})
+}
+
+func (b *PostgresBroker) Subscribe(topic string) (<-chan Message, func()) {
+	return b.fanout.subscribe(topic)
+}
+
+func (b *PostgresBroker) Since(topic string, afterID int64) ([]Message, error) {
+	var events []models.StreamEvent
+	if err := b.db.Where("topic = ? AND id > ? AND created_at > ?", topic, afterID, time.Now().Add(-b.retention)).
+		Order("id ASC").Find(&events).Error; err != nil {
+		return nil, err
+	}
+	return toMessages(events), nil
+}
+
+func (b *PostgresBroker) listenLoop(ctx context.Context) {
+	wait := time.Second
+	for {
+		err := b.listen(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		log.Printf("broker: listening failed, retrying in %v: %v", wait, err)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(wait):
+		}
+		wait = min(wait*2, 30*time.Second)
+	}
+}
+
+// listen holds one LISTEN connection until it fails.
+func (b *PostgresBroker) listen(ctx context.Context) error {
+	conn, err := pgx.Connect(ctx, b.dsn)
+	if err != nil {
+		return err
+	}
+	defer conn.Close(context.Background())
+
+	if _, err := conn.Exec(ctx, "LISTEN "+notifyChannel); err != nil {
+		return err
+	}
+	b.catchUp()
+
+	for {
+		n, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			return err
+		}
+		b.handle(n.Payload)
+	}
+}
+
+// handle delivers the announced event if anyone here is listening to its
+// topic.
+func (b *PostgresBroker) handle(payload string) {
+	idText, topic, ok := strings.Cut(payload, " ")
+	id, err := strconv.ParseInt(idText, 10, 64)
+	if !ok || err != nil {
+		return
+	}
+	b.seen(id)
+	if !b.fanout.has(topic) {
+		return
+	}
+
+	var ev models.StreamEvent
+	if err := b.db.First(&ev, id).Error; err != nil {
+		log.Printf("broker: loading event %d failed: %v", id, err)
+		return
+	}
+	b.fanout.dispatch(toMessage(ev))
+}
+
+// catchUp delivers the events of subscribed topics published while the
+// listening connection was down.
+func (b *PostgresBroker) catchUp() {
+	b.mu.Lock()
+	after := b.lastSeen
+	b.mu.Unlock()
+	topics := b.fanout.active()
+	if after == 0 || len(topics) == 0 {
+		return
+	}
+
+	var events []models.StreamEvent
+	if err := b.db.Where("id > ? AND topic IN ?", after, topics).Order("id ASC").Find(&events).Error; err != nil {
+		log.Printf("broker: catching up failed: %v", err)
+		return
+	}
+	for _, ev := range events {
+		b.seen(ev.ID)
+		b.fanout.dispatch(toMessage(ev))
+	}
+}
+
+func (b *PostgresBroker) seen(id int64) {
+	b.mu.Lock()
+	b.lastSeen = max(b.lastSeen, id)
+	b.mu.Unlock()
+}
+
+func (b *PostgresBroker) pruneLoop(ctx context.Context) {
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		if err := b.db.Where("created_at < ?", time.Now().Add(-b.retention)).
+			Delete(&models.StreamEvent{}).Error; err != nil {
+			log.Printf("broker: pruning events failed: %v", err)
+		}
+	}
+}
+
+func toMessage(ev models.StreamEvent) Message {
+	return Message{ID: ev.ID, Topic: ev.Topic, Event: ev.Event, Data: json.RawMessage(ev.Data)}
+}
+
+func toMessages(events []models.StreamEvent) []Message {
+	msgs := make([]Message, len(events))
+	for i, ev := range events {
+		msgs[i] = toMessage(ev)
+	}
+	return msgs
+}
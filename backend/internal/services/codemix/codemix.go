@@ -0,0 +1,266 @@
+// Package codemix keeps the English → Tamil mapping table used to suggest
+// replacements for code-mixed English, learning new entries from the LLM.
+package codemix
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"tamil-proofreading-platform/backend/internal/models"
+	"tamil-proofreading-platform/backend/internal/services/llm"
+	"tamil-proofreading-platform/backend/internal/services/rules"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	// maxLookupWords caps LLM lookups per submission.
+	maxLookupWords = 20
+	// maxBackgroundLearns caps concurrent LearnInBackground lookups.
+	maxBackgroundLearns = 4
+	learnTimeout        = 2 * time.Minute
+)
+
+var defaults = []struct {
+	english     string
+	spellings   []string
+	equivalents []string
+}{
+	{"meeting", []string{"மீட்டிங்"}, []string{"கூட்டம்", "சந்திப்பு"}},
+	{"office", []string{"ஆபீஸ்", "ஆஃபீஸ்"}, []string{"அலுவலகம்"}},
+	{"computer", []string{"கம்ப்யூட்டர்"}, []string{"கணினி"}},
+	{"phone", []string{"போன்", "ஃபோன்"}, []string{"தொலைபேசி"}},
+	{"mobile", []string{"மொபைல்"}, []string{"கைபேசி", "அலைபேசி"}},
+	{"email", []string{"ஈமெயில்", "இமெயில்"}, []string{"மின்னஞ்சல்"}},
+	{"internet", []string{"இன்டர்நெட்"}, []string{"இணையம்"}},
+	{"website", []string{"வெப்சைட்"}, []string{"இணையதளம்"}},
+	{"message", []string{"மெசேஜ்"}, []string{"செய்தி"}},
+	{"bus", []string{"பஸ்"}, []string{"பேருந்து"}},
+	{"train", []string{"ட்ரெயின்", "ரயில்"}, []string{"தொடர்வண்டி"}},
+	{"ticket", []string{"டிக்கெட்"}, []string{"பயணச்சீட்டு", "நுழைவுச்சீட்டு"}},
+	{"doctor", []string{"டாக்டர்"}, []string{"மருத்துவர்"}},
+	{"hospital", []string{"ஹாஸ்பிடல்"}, []string{"மருத்துவமனை"}},
+	{"school", []string{"ஸ்கூல்"}, []string{"பள்ளி"}},
+	{"college", []string{"காலேஜ்"}, []string{"கல்லூரி"}},
+	{"teacher", []string{"டீச்சர்"}, []string{"ஆசிரியர்"}},
+	{"problem", []string{"ப்ராப்ளம்"}, []string{"சிக்கல்", "பிரச்சினை"}},
+	{"time", []string{"டைம்"}, []string{"நேரம்"}},
+	{"project", []string{"ப்ராஜெக்ட்"}, []string{"திட்டம்"}},
+	{"report", []string{"ரிப்போர்ட்"}, []string{"அறிக்கை"}},
+	{"team", []string{"டீம்"}, []string{"குழு"}},
+	{"function", []string{"ஃபங்ஷன்"}, []string{"விழா"}},
+	{"juice", []string{"ஜூஸ்"}, []string{"பழச்சாறு"}},
+	{"thanks", []string{"தேங்க்ஸ்"}, []string{"நன்றி"}},
+	{"sorry", []string{"சாரி"}, []string{"மன்னிக்கவும்"}},
+	{"okay", []string{"ஓகே"}, []string{"சரி"}},
+	{"news", []string{"நியூஸ்"}, []string{"செய்தி"}},
+	{"photo", []string{"போட்டோ", "ஃபோட்டோ"}, []string{"புகைப்படம்", "ஒளிப்படம்"}},
+	{"video", []string{"வீடியோ"}, []string{"காணொளி"}},
+	{"software", []string{"சாஃப்ட்வேர்"}, []string{"மென்பொருள்"}},
+	{"password", []string{"பாஸ்வேர்ட்"}, []string{"கடவுச்சொல்"}},
+	{"download", []string{"டவுன்லோட்"}, []string{"பதிவிறக்கம்"}},
+	{"update", []string{"அப்டேட்"}, []string{"புதுப்பிப்பு"}},
+	{"online", []string{"ஆன்லைன்"}, []string{"இணைய வழி"}},
+	{"bank", []string{"பேங்க்"}, []string{"வங்கி"}},
+	{"salary", []string{"சேலரி"}, []string{"சம்பளம்", "ஊதியம்"}},
+	{"birthday", []string{"பர்த்டே"}, []string{"பிறந்தநாள்"}},
+}
+
+type CodeMixService struct {
+	db  *gorm.DB
+	llm *llm.LLMService
+
+	mu       sync.RWMutex
+	mappings []models.CodeMixMapping
+	loaded   bool
+
+	learnSlots chan struct{}
+}
+
+func NewCodeMixService(db *gorm.DB, llmService *llm.LLMService) *CodeMixService {
+	return &CodeMixService{db: db, llm: llmService, learnSlots: make(chan struct{}, maxBackgroundLearns)}
+}
+
+// SeedDefaults fills the mapping table when it is empty.
+func SeedDefaults(db *gorm.DB) error {
+	var count int64
+	if err := db.Model(&models.CodeMixMapping{}).Count(&count).Error; err != nil || count > 0 {
+		return err
+	}
+
+	rows := make([]models.CodeMixMapping, 0, len(defaults))
+	for _, d := range defaults {
+		rows = append(rows, models.CodeMixMapping{
+			English:     d.english,
+			Spellings:   d.spellings,
+			Equivalents: d.equivalents,
+			Source:      models.CodeMixSeed,
+			IsActive:    true,
+		})
+	}
+	return db.Create(&rows).Error
+}
+
+// Mappings returns the active mappings, cached until Invalidate.
+func (s *CodeMixService) Mappings() ([]models.CodeMixMapping, error) {
+	s.mu.RLock()
+	if s.loaded {
+		defer s.mu.RUnlock()
+		return s.mappings, nil
+	}
+	s.mu.RUnlock()
+
+	var mappings []models.CodeMixMapping
+	if err := s.db.Where("is_active = ?", true).Find(&mappings).Error; err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	s.mappings = mappings
+	s.loaded = true
+	s.mu.Unlock()
+	return mappings, nil
+}
+
+// Invalidate drops the cache after the table changes.
+func (s *CodeMixService) Invalidate() {
+	s.mu.Lock()
+	s.loaded = false
+	s.mappings = nil
+	s.mu.Unlock()
+}
+
+// Learn asks the LLM for Tamil equivalents of detected words that have no
+// mapping and stores the answers with source "llm", inactive until an admin
+// reviews and enables them, so nothing learned from one user's text is
+// suggested to others unchecked. Words with a mapping in any state, pending
+// or disabled, are not looked up again. It returns the number of mappings
+// added.
+func (s *CodeMixService) Learn(ctx context.Context, text string, skip []string) (int, error) {
+	var mappings []models.CodeMixMapping
+	if err := s.db.Find(&mappings).Error; err != nil {
+		return 0, err
+	}
+
+	var unknown []string
+	seen := make(map[string]bool)
+	for _, t := range rules.NewCodeMixChecker(mappings, skip, true).Detect(text) {
+		word := t.Text
+		if t.Latin {
+			word = strings.ToLower(word)
+		}
+		if t.Mapping != nil || seen[word] {
+			continue
+		}
+		seen[word] = true
+		unknown = append(unknown, word)
+		if len(unknown) == maxLookupWords {
+			break
+		}
+	}
+	if len(unknown) == 0 {
+		return 0, nil
+	}
+
+	suggestions, err := s.llm.SuggestCodeMixEquivalents(ctx, unknown)
+	if errors.Is(err, llm.ErrNoProvider) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	var rows []models.CodeMixMapping
+	byEnglish := make(map[string]int)
+	for _, sug := range suggestions {
+		english := strings.ToLower(strings.TrimSpace(sug.English))
+		input := strings.TrimSpace(sug.Input)
+		if english == "" || !(seen[input] || seen[strings.ToLower(input)]) {
+			continue
+		}
+		equivalents := tamilOnly(sug.Tamil)
+		if len(equivalents) == 0 {
+			continue
+		}
+
+		i, ok := byEnglish[english]
+		if !ok {
+			i = len(rows)
+			byEnglish[english] = i
+			rows = append(rows, models.CodeMixMapping{
+				English:     english,
+				Equivalents: equivalents,
+				Source:      models.CodeMixLLM,
+			})
+		}
+		if !isLatin(input) {
+			rows[i].Spellings = append(rows[i].Spellings, input)
+		}
+	}
+	if len(rows) == 0 {
+		return 0, nil
+	}
+
+	// is_active is selected explicitly because the column defaults to true
+	// and GORM would otherwise omit the false value. An existing row for the
+	// same English word (e.g. one an admin disabled) is left untouched. The
+	// cache holds active mappings only, so it stays valid.
+	result := s.db.Clauses(clause.OnConflict{DoNothing: true}).
+		Select("English", "Spellings", "Equivalents", "Source", "IsActive", "CreatedAt", "UpdatedAt").
+		Create(&rows)
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	return int(result.RowsAffected), nil
+}
+
+// LearnInBackground runs Learn without holding up the caller, with its own
+// timeout. At most maxBackgroundLearns lookups run at once; further calls are
+// dropped, since the same words come up again in later submissions.
+func (s *CodeMixService) LearnInBackground(label, text string, skip []string) {
+	select {
+	case s.learnSlots <- struct{}{}:
+	default:
+		return
+	}
+	go func() {
+		defer func() { <-s.learnSlots }()
+		ctx, cancel := context.WithTimeout(context.Background(), learnTimeout)
+		defer cancel()
+		if learned, err := s.Learn(ctx, text, skip); err != nil {
+			log.Printf("Code-mix lookup failed (%s): %v", label, err)
+		} else if learned > 0 {
+			log.Printf("Learned %d code-mix mappings pending review (%s)", learned, label)
+		}
+	}()
+}
+
+func tamilOnly(values []string) []string {
+	var out []string
+	for _, v := range values {
+		v = strings.TrimSpace(v)
+		if v == "" || strings.IndexFunc(v, func(r rune) bool { return !unicode.Is(unicode.Tamil, r) && !unicode.IsSpace(r) }) >= 0 {
+			continue
+		}
+		out = append(out, v)
+		if len(out) == 3 {
+			break
+		}
+	}
+	return out
+}
+
+func isLatin(s string) bool {
+	for _, r := range s {
+		if r >= 0x80 {
+			return false
+		}
+	}
+	return true
+}
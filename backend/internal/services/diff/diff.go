@@ -0,0 +1,342 @@
+// Package diff compares texts as sequences of lines, words or graphemes using
+// Myers' algorithm and renders the result as HTML or unified text.
+package diff
+
+import (
+	"unicode"
+	"unicode/utf8"
+)
+
+type OpType string
+
+const (
+	Equal   OpType = "equal"
+	Insert  OpType = "insert"
+	Delete  OpType = "delete"
+	Replace OpType = "replace"
+)
+
+type Granularity string
+
+const (
+	ByLine     Granularity = "line"
+	ByWord     Granularity = "word"
+	ByGrapheme Granularity = "grapheme"
+)
+
+// Op is one run of the diff. Offsets are byte offsets: OldStart:OldEnd in
+// the old text and NewStart:NewEnd in the new text.
+type Op struct {
+	Type     OpType `json:"type"`
+	OldStart int    `json:"old_start"`
+	OldEnd   int    `json:"old_end"`
+	NewStart int    `json:"new_start"`
+	NewEnd   int    `json:"new_end"`
+	Old      string `json:"old,omitempty"`
+	New      string `json:"new,omitempty"`
+}
+
+// maxEdits bounds the work done by Myers' algorithm, which is quadratic in
+// the number of edits. Beyond it the unmatched middle of the texts is
+// reported as a single replacement.
+const maxEdits = 2000
+
+type token struct {
+	text  string
+	start int
+}
+
+// Compute diffs two texts at the given granularity. Unknown granularities
+// diff by word.
+func Compute(from, to string, g Granularity) []Op {
+	split := words
+	switch g {
+	case ByLine:
+		split = lines
+	case ByGrapheme:
+		split = graphemes
+	}
+	return build(from, to, split(from), split(to))
+}
+
+func Lines(from, to string) []Op     { return Compute(from, to, ByLine) }
+func Words(from, to string) []Op     { return Compute(from, to, ByWord) }
+func Graphemes(from, to string) []Op { return Compute(from, to, ByGrapheme) }
+
+// Changes returns the ops that are not Equal.
+func Changes(ops []Op) []Op {
+	var changes []Op
+	for _, op := range ops {
+		if op.Type != Equal {
+			changes = append(changes, op)
+		}
+	}
+	return changes
+}
+
+type edit int
+
+const (
+	editEqual edit = iota
+	editInsert
+	editDelete
+)
+
+func build(from, to string, a, b []token) []Op {
+	// Common prefix and suffix are matched directly, which keeps the
+	// search small for typical proofreading edits.
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix].text == b[prefix].text {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix].text == b[len(b)-1-suffix].text {
+		suffix++
+	}
+
+	edits := make([]edit, 0, len(a)+len(b))
+	for i := 0; i < prefix; i++ {
+		edits = append(edits, editEqual)
+	}
+	edits = append(edits, myers(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix])...)
+	for i := 0; i < suffix; i++ {
+		edits = append(edits, editEqual)
+	}
+
+	return group(from, to, a, b, edits)
+}
+
+// myers returns the shortest edit script turning a into b.
+func myers(a, b []token) []edit {
+	n, m := len(a), len(b)
+	if n == 0 || m == 0 {
+		return fallback(n, m)
+	}
+
+	max := n + m
+	offset := max + 1
+	v := make([]int, 2*max+3)
+	// trace[d] holds v[-d..d] as it was before round d.
+	var trace [][]int
+
+	for d := 0; d <= max; d++ {
+		if d > maxEdits {
+			return fallback(n, m)
+		}
+		trace = append(trace, append([]int(nil), v[offset-d:offset+d+1]...))
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1]
+			} else {
+				x = v[offset+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x].text == b[y].text {
+				x++
+				y++
+			}
+			v[offset+k] = x
+			if x >= n && y >= m {
+				return backtrack(trace, n, m)
+			}
+		}
+	}
+	return fallback(n, m)
+}
+
+func backtrack(trace [][]int, n, m int) []edit {
+	var reversed []edit
+	x, y := n, m
+	for d := len(trace) - 1; d >= 0; d-- {
+		v := trace[d]
+		at := func(k int) int { return v[k+d] }
+		k := x - y
+
+		var prevK int
+		if k == -d || (k != d && at(k-1) < at(k+1)) {
+			prevK = k + 1
+		} else {
+			prevK = k - 1
+		}
+		prevX := 0
+		if d > 0 {
+			prevX = at(prevK)
+		}
+		prevY := prevX - prevK
+
+		for x > prevX && y > prevY {
+			reversed = append(reversed, editEqual)
+			x--
+			y--
+		}
+		if d > 0 {
+			if x == prevX {
+				reversed = append(reversed, editInsert)
+			} else {
+				reversed = append(reversed, editDelete)
+			}
+		}
+		x, y = prevX, prevY
+	}
+
+	edits := make([]edit, len(reversed))
+	for i, e := range reversed {
+		edits[len(reversed)-1-i] = e
+	}
+	return edits
+}
+
+// fallback deletes all of a and inserts all of b.
+func fallback(n, m int) []edit {
+	edits := make([]edit, 0, n+m)
+	for i := 0; i < n; i++ {
+		edits = append(edits, editDelete)
+	}
+	for i := 0; i < m; i++ {
+		edits = append(edits, editInsert)
+	}
+	return edits
+}
+
+// group merges runs of edits into ops, pairing adjacent deletes and inserts
+// into replacements.
+func group(from, to string, a, b []token, edits []edit) []Op {
+	var ops []Op
+	i, j := 0, 0
+	for p := 0; p < len(edits); {
+		if edits[p] == editEqual {
+			startI, startJ := i, j
+			for p < len(edits) && edits[p] == editEqual {
+				i++
+				j++
+				p++
+			}
+			ops = append(ops, Op{
+				Type:     Equal,
+				OldStart: offset(a, from, startI), OldEnd: offset(a, from, i),
+				NewStart: offset(b, to, startJ), NewEnd: offset(b, to, j),
+			})
+			continue
+		}
+
+		startI, startJ := i, j
+		for p < len(edits) && edits[p] != editEqual {
+			if edits[p] == editDelete {
+				i++
+			} else {
+				j++
+			}
+			p++
+		}
+		op := Op{
+			OldStart: offset(a, from, startI), OldEnd: offset(a, from, i),
+			NewStart: offset(b, to, startJ), NewEnd: offset(b, to, j),
+		}
+		switch {
+		case i == startI:
+			op.Type = Insert
+		case j == startJ:
+			op.Type = Delete
+		default:
+			op.Type = Replace
+		}
+		op.Old = from[op.OldStart:op.OldEnd]
+		op.New = to[op.NewStart:op.NewEnd]
+		ops = append(ops, op)
+	}
+	return ops
+}
+
+// offset returns the byte offset of token i, or the end of text when i is
+// past the last token.
+func offset(tokens []token, text string, i int) int {
+	if i < len(tokens) {
+		return tokens[i].start
+	}
+	return len(text)
+}
+
+// lines splits text into lines, each keeping its newline.
+func lines(text string) []token {
+	var tokens []token
+	start := 0
+	for i := 0; i < len(text); i++ {
+		if text[i] == '\n' {
+			tokens = append(tokens, token{text: text[start : i+1], start: start})
+			start = i + 1
+		}
+	}
+	if start < len(text) {
+		tokens = append(tokens, token{text: text[start:], start: start})
+	}
+	return tokens
+}
+
+// words splits text into words, runs of whitespace, and single
+// punctuation characters.
+func words(text string) []token {
+	var tokens []token
+	for i := 0; i < len(text); {
+		r, size := utf8.DecodeRuneInString(text[i:])
+		start := i
+		i += size
+		switch {
+		case isWordRune(r):
+			for i < len(text) {
+				r, size := utf8.DecodeRuneInString(text[i:])
+				if !isWordRune(r) {
+					break
+				}
+				i += size
+			}
+		case unicode.IsSpace(r):
+			for i < len(text) {
+				r, size := utf8.DecodeRuneInString(text[i:])
+				if !unicode.IsSpace(r) {
+					break
+				}
+				i += size
+			}
+		}
+		tokens = append(tokens, token{text: text[start:i], start: start})
+	}
+	return tokens
+}
+
+// graphemes splits text into user-perceived characters: a base character
+// with its combining marks, so a Tamil consonant stays together with its
+// vowel sign or pulli. Characters joined by ZWJ stay together too.
+func graphemes(text string) []token {
+	var tokens []token
+	for i := 0; i < len(text); {
+		start := i
+		r, size := utf8.DecodeRuneInString(text[i:])
+		i += size
+		if r == '\r' && i < len(text) && text[i] == '\n' {
+			i++
+		}
+		for i < len(text) {
+			r, size := utf8.DecodeRuneInString(text[i:])
+			if r == '\u200d' {
+				i += size
+				if i < len(text) {
+					_, next := utf8.DecodeRuneInString(text[i:])
+					i += next
+				}
+				continue
+			}
+			if !unicode.IsMark(r) && r != '\u200c' {
+				break
+			}
+			i += size
+		}
+		tokens = append(tokens, token{text: text[start:i], start: start})
+	}
+	return tokens
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsMark(r) || unicode.IsDigit(r) ||
+		r == '\u200c' || r == '\u200d'
+}
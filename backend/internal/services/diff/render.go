@@ -0,0 +1,140 @@
+package diff
+
+import (
+	"fmt"
+	"html"
+	"strings"
+)
+
+// HTML renders ops as the new text with removed text in <del> and added
+// text in <ins>. Text is escaped and newlines become <br>.
+func HTML(from string, ops []Op) string {
+	var b strings.Builder
+	for _, op := range ops {
+		switch op.Type {
+		case Equal:
+			b.WriteString(escape(from[op.OldStart:op.OldEnd]))
+		case Delete:
+			b.WriteString("<del>" + escape(op.Old) + "</del>")
+		case Insert:
+			b.WriteString("<ins>" + escape(op.New) + "</ins>")
+		case Replace:
+			b.WriteString("<del>" + escape(op.Old) + "</del><ins>" + escape(op.New) + "</ins>")
+		}
+	}
+	return b.String()
+}
+
+func escape(text string) string {
+	return strings.ReplaceAll(html.EscapeString(text), "\n", "<br>\n")
+}
+
+type unifiedLine struct {
+	kind byte
+	text string
+}
+
+// Unified renders a line diff of two texts in unified format with the given
+// number of context lines. It returns "" when the texts are equal.
+func Unified(from, to, fromName, toName string, context int) string {
+	var all []unifiedLine
+	for _, op := range Lines(from, to) {
+		switch op.Type {
+		case Equal:
+			all = appendLines(all, ' ', from[op.OldStart:op.OldEnd])
+		case Delete:
+			all = appendLines(all, '-', op.Old)
+		case Insert:
+			all = appendLines(all, '+', op.New)
+		case Replace:
+			all = appendLines(all, '-', op.Old)
+			all = appendLines(all, '+', op.New)
+		}
+	}
+
+	var b strings.Builder
+	oldLine, newLine := 1, 1
+	for i := 0; i < len(all); {
+		if all[i].kind == ' ' {
+			i++
+			oldLine++
+			newLine++
+			continue
+		}
+
+		// Extend the hunk until a run of unchanged lines longer than twice
+		// the context separates it from the next change.
+		start := i - context
+		if start < 0 {
+			start = 0
+		}
+		end := i
+		for end < len(all) {
+			if all[end].kind != ' ' {
+				end++
+				continue
+			}
+			run := end
+			for run < len(all) && all[run].kind == ' ' {
+				run++
+			}
+			if run == len(all) || run-end > 2*context {
+				end += min(context, run-end)
+				break
+			}
+			end = run
+		}
+
+		hunkOld, hunkNew := oldLine-(i-start), newLine-(i-start)
+		oldCount, newCount := 0, 0
+		for _, l := range all[start:end] {
+			if l.kind != '+' {
+				oldCount++
+			}
+			if l.kind != '-' {
+				newCount++
+			}
+		}
+
+		if b.Len() == 0 {
+			fmt.Fprintf(&b, "--- %s\n+++ %s\n", fromName, toName)
+		}
+		fmt.Fprintf(&b, "@@ -%s +%s @@\n", hunkRange(hunkOld, oldCount), hunkRange(hunkNew, newCount))
+		for _, l := range all[start:end] {
+			b.WriteByte(l.kind)
+			b.WriteString(l.text)
+			b.WriteByte('\n')
+		}
+
+		for _, l := range all[i:end] {
+			if l.kind != '+' {
+				oldLine++
+			}
+			if l.kind != '-' {
+				newLine++
+			}
+		}
+		i = end
+	}
+	return b.String()
+}
+
+func appendLines(all []unifiedLine, kind byte, text string) []unifiedLine {
+	for _, line := range strings.SplitAfter(text, "\n") {
+		if line == "" {
+			continue
+		}
+		all = append(all, unifiedLine{kind: kind, text: strings.TrimSuffix(line, "\n")})
+	}
+	return all
+}
+
+func hunkRange(start, count int) string {
+	if count == 0 {
+		return fmt.Sprintf("%d,0", start-1)
+	}
+	if count == 1 {
+		return fmt.Sprintf("%d", start)
+	}
+	return fmt.Sprintf("%d,%d", start, count)
+}
@@ -0,0 +1,111 @@
+// Package document stores documents as numbered revisions, each optionally
+// linked to the submission that proofread it.
+package document
+
+import (
+	"errors"
+	"strings"
+	"unicode/utf8"
+
+	"tamil-proofreading-platform/backend/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var ErrRevisionNotFound = errors.New("revision not found")
+
+const maxTitleLength = 80
+
+type DocumentService struct {
+	db *gorm.DB
+}
+
+func NewDocumentService(db *gorm.DB) *DocumentService {
+	return &DocumentService{db: db}
+}
+
+// Get returns one of the user's documents.
+func (s *DocumentService) Get(userID, documentID uint) (*models.Document, error) {
+	var doc models.Document
+	if err := s.db.Where("id = ? AND user_id = ?", documentID, userID).First(&doc).Error; err != nil {
+		return nil, err
+	}
+	return &doc, nil
+}
+
+// Revision returns revision number of a document.
+func (s *DocumentService) Revision(documentID uint, number int) (*models.DocumentRevision, error) {
+	var rev models.DocumentRevision
+	err := s.db.Where("document_id = ? AND number = ?", documentID, number).First(&rev).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrRevisionNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &rev, nil
+}
+
+// AddRevision appends rev to doc, creating doc first when it has no ID.
+// When submission is non-nil it is created in the same transaction, linked
+// to the document, and recorded as the revision's submission. Each of after
+// runs last in the transaction, to save records that belong with it.
+func (s *DocumentService) AddRevision(doc *models.Document, rev *models.DocumentRevision, submission *models.Submission, after ...func(tx *gorm.DB) error) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if err := AddRevisionTx(tx, doc, rev, submission); err != nil {
+			return err
+		}
+		for _, fn := range after {
+			if err := fn(tx); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// AddRevisionTx is AddRevision within the caller's transaction, for saving
+// several documents together.
+func AddRevisionTx(tx *gorm.DB, doc *models.Document, rev *models.DocumentRevision, submission *models.Submission) error {
+	if doc.ID == 0 {
+		if doc.Title == "" {
+			doc.Title = Title(rev.Text)
+		}
+		if err := tx.Create(doc).Error; err != nil {
+			return err
+		}
+	} else if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(doc, doc.ID).Error; err != nil {
+		return err
+	}
+
+	if submission != nil {
+		submission.DocumentID = &doc.ID
+		if err := tx.Create(submission).Error; err != nil {
+			return err
+		}
+		rev.SubmissionID = &submission.ID
+	}
+
+	rev.DocumentID = doc.ID
+	rev.Number = doc.CurrentRevision + 1
+	if err := tx.Create(rev).Error; err != nil {
+		return err
+	}
+
+	doc.CurrentRevision = rev.Number
+	return tx.Model(doc).Update("current_revision", rev.Number).Error
+}
+
+// Title derives a document title from the first line of its text.
+func Title(text string) string {
+	line := strings.TrimSpace(text)
+	if i := strings.IndexByte(line, '\n'); i >= 0 {
+		line = strings.TrimSpace(line[:i])
+	}
+	if utf8.RuneCountInString(line) <= maxTitleLength {
+		return line
+	}
+	runes := []rune(line)
+	return strings.TrimSpace(string(runes[:maxTitleLength])) + "…"
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"html"
 	"log"
 	"net/http"
 	"os"
@@ -138,3 +139,85 @@ func (s *EmailService) SendVerificationEmail(to, otp string) error {
 
 	return s.SendEmail(to, subject, htmlBody)
 }
+
+// SendProofreadReport emails a proofreading report. diffHTML is the rendered
+// diff, with removed text in <del> and added text in <ins>.
+func (s *EmailService) SendProofreadReport(to, title, diffHTML string, changes int) error {
+	subject := "Your ProofTamil proofreading report"
+	htmlBody := fmt.Sprintf(`
+<!DOCTYPE html>
+<html>
+<head>
+    <meta charset="UTF-8">
+    <meta name="viewport" content="width=device-width, initial-scale=1.0">
+    <style>
+        del { background-color: #fee2e2; color: #991b1b; }
+        ins { background-color: #dcfce7; color: #166534; text-decoration: none; }
+    </style>
+</head>
+<body style="font-family: 'Segoe UI', Tahoma, Geneva, Verdana, sans-serif; margin: 0; padding: 0; background-color: #f5f5f5;">
+    <div style="max-width: 600px; margin: 0 auto; background-color: #ffffff; border-radius: 8px; overflow: hidden; box-shadow: 0 2px 10px rgba(0,0,0,0.1);">
+        <div style="background: linear-gradient(135deg, #ea580c 0%%, #f97316 100%%); padding: 30px; text-align: center;">
+            <h1 style="color: white; margin: 0; font-size: 28px;">தமிழ்</h1>
+            <p style="color: rgba(255,255,255,0.9); margin: 10px 0 0 0;">ProofTamil</p>
+        </div>
+
+        <div style="padding: 40px 30px;">
+            <h2 style="color: #1f2937; margin: 0 0 10px 0; font-size: 22px;">Proofreading Report</h2>
+            <p style="color: #6b7280; font-size: 14px; margin: 0 0 25px 0;">%s &middot; %d change(s)</p>
+            <div style="color: #1f2937; font-size: 16px; line-height: 1.8;">%s</div>
+        </div>
+
+        <div style="background-color: #f9fafb; padding: 20px 30px; text-align: center; border-top: 1px solid #e5e7eb;">
+            <p style="color: #9ca3af; font-size: 12px; margin: 0;">
+                © 2024 ProofTamil. Your AI Writing Partner for Tamil.
+            </p>
+        </div>
+    </div>
+</body>
+</html>
+`, html.EscapeString(title), changes, diffHTML)
+
+	return s.SendEmail(to, subject, htmlBody)
+}
+
+// SendOrganizationInvite tells a user they have been invited to join an
+// organization. The invitation is accepted from their account.
+func (s *EmailService) SendOrganizationInvite(to, organization string) error {
+	subject := "You have been invited to a ProofTamil organization"
+	htmlBody := fmt.Sprintf(`
+<!DOCTYPE html>
+<html>
+<head>
+    <meta charset="UTF-8">
+    <meta name="viewport" content="width=device-width, initial-scale=1.0">
+</head>
+<body style="font-family: 'Segoe UI', Tahoma, Geneva, Verdana, sans-serif; margin: 0; padding: 0; background-color: #f5f5f5;">
+    <div style="max-width: 600px; margin: 0 auto; background-color: #ffffff; border-radius: 8px; overflow: hidden; box-shadow: 0 2px 10px rgba(0,0,0,0.1);">
+        <div style="background: linear-gradient(135deg, #ea580c 0%%, #f97316 100%%); padding: 30px; text-align: center;">
+            <h1 style="color: white; margin: 0; font-size: 28px;">தமிழ்</h1>
+            <p style="color: rgba(255,255,255,0.9); margin: 10px 0 0 0;">ProofTamil</p>
+        </div>
+
+        <div style="padding: 40px 30px;">
+            <h2 style="color: #1f2937; margin: 0 0 20px 0; font-size: 24px;">Organization Invitation</h2>
+            <p style="color: #4b5563; font-size: 16px; line-height: 1.6; margin: 0 0 25px 0;">
+                You have been invited to join <strong>%s</strong>. Sign in to ProofTamil to accept or decline the invitation.
+            </p>
+            <p style="color: #6b7280; font-size: 14px; line-height: 1.6; margin: 25px 0 0 0;">
+                The invitation expires in <strong>14 days</strong>. If you don't want to join, you can ignore this email.
+            </p>
+        </div>
+
+        <div style="background-color: #f9fafb; padding: 20px 30px; text-align: center; border-top: 1px solid #e5e7eb;">
+            <p style="color: #9ca3af; font-size: 12px; margin: 0;">
+                © 2024 ProofTamil. Your AI Writing Partner for Tamil.
+            </p>
+        </div>
+    </div>
+</body>
+</html>
+`, html.EscapeString(organization))
+
+	return s.SendEmail(to, subject, htmlBody)
+}
@@ -0,0 +1,449 @@
+package fileformat
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"html"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	docxDocumentPart     = "word/document.xml"
+	docxCommentsPart     = "word/comments.xml"
+	docxRelsPart         = "word/_rels/document.xml.rels"
+	docxContentTypesPart = "[Content_Types].xml"
+
+	docxCommentsType = "application/vnd.openxmlformats-officedocument.wordprocessingml.comments+xml"
+	docxCommentsRel  = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/comments"
+
+	// Revision and comment IDs start high to stay clear of any the
+	// document already uses.
+	docxFirstID = 900000
+)
+
+type docx struct{}
+
+func init() {
+	register(docx{}, "docx")
+}
+
+func (docx) Name() string { return "docx" }
+
+func (docx) ContentType() string {
+	return "application/vnd.openxmlformats-officedocument.wordprocessingml.document"
+}
+
+// docxSegment is the text of one <w:t> element. Start:End is its range in
+// the extracted text and Tag its range in document.xml.
+type docxSegment struct {
+	Start    int
+	End      int
+	TagStart int
+	TagEnd   int
+	Text     string
+	RunProps string
+}
+
+// docxText is the extracted text of document.xml. Tabs, breaks and
+// paragraph ends appear in the text but belong to no segment, so edits
+// across them cannot be written back.
+type docxText struct {
+	Text     string
+	Segments []docxSegment
+}
+
+func (docx) Extract(data []byte) (string, error) {
+	doc, err := readDocx(data)
+	if err != nil {
+		return "", err
+	}
+	return doc.Text, nil
+}
+
+func readDocx(data []byte) (*docxText, error) {
+	r, err := openZip(data)
+	if err != nil {
+		return nil, err
+	}
+	part, err := readPart(r, docxDocumentPart)
+	if err != nil {
+		return nil, err
+	}
+	if part == nil {
+		return nil, fmt.Errorf("%w: missing %s", ErrInvalidFile, docxDocumentPart)
+	}
+	return parseDocx(part)
+}
+
+// parseDocx walks document.xml, recording each <w:t> with the properties of
+// its run. Content under mc:Fallback duplicates mc:Choice and is skipped.
+func parseDocx(part []byte) (*docxText, error) {
+	dec := xml.NewDecoder(bytes.NewReader(part))
+	var text strings.Builder
+	var segments []docxSegment
+
+	type run struct {
+		propsStart int
+		props      string
+	}
+	var runs []run
+	var inText *docxSegment
+	fallback := 0
+	tabStops := 0 // open w:tabs elements, whose w:tab children are tab stops
+
+	for {
+		offset := int(dec.InputOffset())
+		tok, err := dec.RawToken()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidFile, err)
+		}
+		end := int(dec.InputOffset())
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			name := qualified(t.Name)
+			if name == "mc:Fallback" {
+				fallback++
+			}
+			if fallback > 0 {
+				continue
+			}
+			switch name {
+			case "w:r":
+				runs = append(runs, run{propsStart: -1})
+			case "w:rPr":
+				if len(runs) > 0 {
+					runs[len(runs)-1].propsStart = offset
+				}
+			case "w:t":
+				if len(runs) > 0 {
+					inText = &docxSegment{Start: text.Len(), TagStart: offset, RunProps: runs[len(runs)-1].props}
+				}
+			case "w:tabs":
+				tabStops++
+			case "w:tab":
+				// Outside a run, w:tab defines a tab stop of the paragraph
+				if len(runs) > 0 && tabStops == 0 {
+					text.WriteByte('\t')
+				}
+			case "w:br", "w:cr":
+				if len(runs) > 0 {
+					text.WriteByte('\n')
+				}
+			}
+		case xml.EndElement:
+			name := qualified(t.Name)
+			if name == "mc:Fallback" {
+				fallback--
+				continue
+			}
+			if fallback > 0 {
+				continue
+			}
+			switch name {
+			case "w:tabs":
+				tabStops--
+			case "w:r":
+				if len(runs) > 0 {
+					runs = runs[:len(runs)-1]
+				}
+			case "w:rPr":
+				if len(runs) > 0 && runs[len(runs)-1].propsStart >= 0 {
+					r := &runs[len(runs)-1]
+					r.props = string(part[r.propsStart:end])
+				}
+			case "w:t":
+				if inText != nil {
+					inText.End = text.Len()
+					inText.TagEnd = end
+					segments = append(segments, *inText)
+					inText = nil
+				}
+			case "w:p":
+				text.WriteByte('\n')
+			}
+		case xml.CharData:
+			if inText != nil && fallback == 0 {
+				text.Write(t)
+				inText.Text += string(t)
+			}
+		}
+	}
+
+	return &docxText{Text: text.String(), Segments: segments}, nil
+}
+
+func qualified(name xml.Name) string {
+	if name.Space == "" {
+		return name.Local
+	}
+	return name.Space + ":" + name.Local
+}
+
+// docxCut is one edit, or the part of one that falls in a segment.
+type docxCut struct {
+	Start   int // offsets within the segment text
+	End     int
+	Insert  string
+	First   bool // first piece of its edit
+	Last    bool // last piece; carries the insertion and comment
+	Comment int
+}
+
+func (docx) Apply(data []byte, edits []Edit, opts ApplyOptions) ([]byte, error) {
+	r, err := openZip(data)
+	if err != nil {
+		return nil, err
+	}
+	part, err := readPart(r, docxDocumentPart)
+	if err != nil {
+		return nil, err
+	}
+	if part == nil {
+		return nil, fmt.Errorf("%w: missing %s", ErrInvalidFile, docxDocumentPart)
+	}
+	doc, err := parseDocx(part)
+	if err != nil {
+		return nil, err
+	}
+
+	author := opts.Author
+	if author == "" {
+		author = "ProofTamil"
+	}
+	if opts.Date.IsZero() {
+		opts.Date = time.Now()
+	}
+	date := opts.Date.UTC().Format("2006-01-02T15:04:05Z")
+
+	cuts := make(map[int][]docxCut)
+	var comments []string
+	nextID := docxFirstID
+	for _, e := range usable(doc.Text, edits) {
+		pieces := docxPieces(doc, e)
+		if pieces == nil {
+			continue
+		}
+		comment := 0
+		if opts.TrackChanges && e.Reason != "" {
+			comment = nextID
+			nextID++
+			comments = append(comments, docxComment(comment, author, date, e.Reason))
+		}
+		for i, p := range pieces {
+			p.cut.Comment = comment
+			p.cut.First = i == 0
+			p.cut.Last = i == len(pieces)-1
+			if p.cut.Last {
+				p.cut.Insert = e.Replacement
+			}
+			cuts[p.segment] = append(cuts[p.segment], p.cut)
+		}
+	}
+	if len(cuts) == 0 {
+		return data, nil
+	}
+
+	var out bytes.Buffer
+	pos := 0
+	for i, seg := range doc.Segments {
+		segCuts, ok := cuts[i]
+		if !ok {
+			continue
+		}
+		out.Write(part[pos:seg.TagStart])
+		writeDocxSegment(&out, seg, segCuts, opts.TrackChanges, author, date, &nextID)
+		pos = seg.TagEnd
+	}
+	out.Write(part[pos:])
+
+	parts := map[string][]byte{docxDocumentPart: out.Bytes()}
+	if len(comments) > 0 {
+		if err := addDocxComments(r, parts, comments); err != nil {
+			return nil, err
+		}
+	}
+	return rewriteZip(r, parts)
+}
+
+type docxPiece struct {
+	segment int
+	cut     docxCut
+}
+
+// docxPieces splits an edit across the segments it covers. It returns nil
+// when the edit touches text outside any segment, such as a tab or a
+// paragraph break.
+func docxPieces(doc *docxText, e Edit) []docxPiece {
+	if e.Start == e.End {
+		for i, seg := range doc.Segments {
+			if seg.Start <= e.Start && e.Start <= seg.End {
+				at := e.Start - seg.Start
+				return []docxPiece{{segment: i, cut: docxCut{Start: at, End: at}}}
+			}
+		}
+		return nil
+	}
+
+	var pieces []docxPiece
+	covered := e.Start
+	for i, seg := range doc.Segments {
+		if seg.End <= e.Start || seg.Start == seg.End {
+			continue
+		}
+		if seg.Start >= e.End {
+			break
+		}
+		if seg.Start > covered {
+			return nil
+		}
+		from := max(e.Start, seg.Start)
+		to := min(e.End, seg.End)
+		pieces = append(pieces, docxPiece{segment: i, cut: docxCut{Start: from - seg.Start, End: to - seg.Start}})
+		covered = to
+	}
+	if covered < e.End {
+		return nil
+	}
+	return pieces
+}
+
+// writeDocxSegment writes a <w:t> element with its cuts applied. With
+// tracked changes each cut closes the current run, writes w:del and w:ins
+// runs with the same properties, and reopens the run.
+func writeDocxSegment(out *bytes.Buffer, seg docxSegment, cuts []docxCut, track bool, author, date string, nextID *int) {
+	sort.SliceStable(cuts, func(i, j int) bool { return cuts[i].Start < cuts[j].Start })
+
+	text := func(s string) {
+		out.WriteString(`<w:t xml:space="preserve">` + xmlEscape(s) + `</w:t>`)
+	}
+	revision := func(tag string) string {
+		id := *nextID
+		*nextID++
+		return fmt.Sprintf(`<w:%s w:id="%d" w:author="%s" w:date="%s">`, tag, id, xmlEscape(author), date)
+	}
+
+	if !track {
+		var b strings.Builder
+		pos := 0
+		for _, c := range cuts {
+			b.WriteString(seg.Text[pos:c.Start])
+			b.WriteString(c.Insert)
+			pos = c.End
+		}
+		b.WriteString(seg.Text[pos:])
+		text(b.String())
+		return
+	}
+
+	pos := 0
+	for _, c := range cuts {
+		text(seg.Text[pos:c.Start])
+		out.WriteString("</w:r>")
+		if c.First && c.Comment != 0 {
+			fmt.Fprintf(out, `<w:commentRangeStart w:id="%d"/>`, c.Comment)
+		}
+		if c.End > c.Start {
+			out.WriteString(revision("del"))
+			out.WriteString("<w:r>" + seg.RunProps + `<w:delText xml:space="preserve">` + xmlEscape(seg.Text[c.Start:c.End]) + "</w:delText></w:r></w:del>")
+		}
+		if c.Insert != "" {
+			out.WriteString(revision("ins"))
+			out.WriteString("<w:r>" + seg.RunProps + `<w:t xml:space="preserve">` + xmlEscape(c.Insert) + "</w:t></w:r></w:ins>")
+		}
+		if c.Last && c.Comment != 0 {
+			fmt.Fprintf(out, `<w:commentRangeEnd w:id="%d"/><w:r><w:commentReference w:id="%d"/></w:r>`, c.Comment, c.Comment)
+		}
+		out.WriteString("<w:r>" + seg.RunProps)
+		pos = c.End
+	}
+	text(seg.Text[pos:])
+}
+
+func docxComment(id int, author, date, reason string) string {
+	return fmt.Sprintf(`<w:comment w:id="%d" w:author="%s" w:date="%s" w:initials="PT"><w:p><w:r><w:t xml:space="preserve">%s</w:t></w:r></w:p></w:comment>`,
+		id, xmlEscape(author), date, xmlEscape(reason))
+}
+
+var relIDPattern = regexp.MustCompile(`Id="rId(\d+)"`)
+
+// addDocxComments appends comments to word/comments.xml, creating the part
+// and registering it when the document has none.
+func addDocxComments(zr *zip.Reader, parts map[string][]byte, comments []string) error {
+	existing, err := readPart(zr, docxCommentsPart)
+	if err != nil {
+		return err
+	}
+	body := strings.Join(comments, "")
+	if existing != nil {
+		i := bytes.LastIndex(existing, []byte("</w:comments>"))
+		if i < 0 {
+			return fmt.Errorf("%w: malformed %s", ErrInvalidFile, docxCommentsPart)
+		}
+		parts[docxCommentsPart] = append(append(append([]byte{}, existing[:i]...), body...), existing[i:]...)
+		return nil
+	}
+
+	parts[docxCommentsPart] = []byte(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` + "\n" +
+		`<w:comments xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main">` + body + `</w:comments>`)
+
+	rels, err := readPart(zr, docxRelsPart)
+	if err != nil {
+		return err
+	}
+	if rels == nil {
+		rels = []byte(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` + "\n" +
+			`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"></Relationships>`)
+	}
+	next := 1
+	for _, m := range relIDPattern.FindAllSubmatch(rels, -1) {
+		if n, _ := strconv.Atoi(string(m[1])); n >= next {
+			next = n + 1
+		}
+	}
+	rel := fmt.Sprintf(`<Relationship Id="rId%d" Type="%s" Target="comments.xml"/>`, next, docxCommentsRel)
+	updated, err := insertBefore(rels, "</Relationships>", rel)
+	if err != nil {
+		return err
+	}
+	parts[docxRelsPart] = updated
+
+	types, err := readPart(zr, docxContentTypesPart)
+	if err != nil {
+		return err
+	}
+	if types == nil {
+		return fmt.Errorf("%w: missing %s", ErrInvalidFile, docxContentTypesPart)
+	}
+	override := `<Override PartName="/word/comments.xml" ContentType="` + docxCommentsType + `"/>`
+	updated, err = insertBefore(types, "</Types>", override)
+	if err != nil {
+		return err
+	}
+	parts[docxContentTypesPart] = updated
+	return nil
+}
+
+func insertBefore(data []byte, closing, insert string) ([]byte, error) {
+	i := bytes.LastIndex(data, []byte(closing))
+	if i < 0 {
+		return nil, fmt.Errorf("%w: missing %s", ErrInvalidFile, closing)
+	}
+	out := append([]byte{}, data[:i]...)
+	out = append(out, insert...)
+	return append(out, data[i:]...), nil
+}
+
+func xmlEscape(s string) string {
+	return html.EscapeString(s)
+}
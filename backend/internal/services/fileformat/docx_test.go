@@ -0,0 +1,65 @@
+package fileformat
+
+import (
+	"archive/zip"
+	"bytes"
+	"strings"
+	"testing"
+)
+
+// tabStopsDocument has a paragraph with custom tab stops and one real tab.
+const tabStopsDocument = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main">
+<w:body>
+<w:p><w:pPr><w:tabs><w:tab w:val="left" w:pos="1440"/><w:tab w:val="right" w:pos="8640"/></w:tabs><w:rPr><w:b/></w:rPr></w:pPr><w:r><w:t>அவன்</w:t></w:r><w:r><w:tab/><w:t>வந்தான்</w:t></w:r></w:p>
+<w:p><w:pPr><w:tabs><w:tab w:val="clear" w:pos="720"/></w:tabs></w:pPr><w:r><w:t>முடிவு</w:t></w:r></w:p>
+</w:body>
+</w:document>`
+
+func docxFixture(t *testing.T, document string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	for name, content := range map[string]string{
+		docxContentTypesPart: `<?xml version="1.0" encoding="UTF-8"?><Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types"></Types>`,
+		docxDocumentPart:     document,
+	} {
+		f, err := w.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := f.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestDocxTabStops(t *testing.T) {
+	data := docxFixture(t, tabStopsDocument)
+
+	text, err := docx{}.Extract(data)
+	if err != nil {
+		t.Fatalf("Extract: %v", err)
+	}
+	if want := "அவன்\tவந்தான்\nமுடிவு\n"; text != want {
+		t.Fatalf("Extract = %q, want %q", text, want)
+	}
+
+	start := strings.Index(text, "வந்தான்")
+	edits := []Edit{{Start: start, End: start + len("வந்தான்"), Original: "வந்தான்", Replacement: "வந்தார்"}}
+	out, err := docx{}.Apply(data, edits, ApplyOptions{})
+	if err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	text, err = docx{}.Extract(out)
+	if err != nil {
+		t.Fatalf("Extract after Apply: %v", err)
+	}
+	if want := "அவன்\tவந்தார்\nமுடிவு\n"; text != want {
+		t.Errorf("Extract after Apply = %q, want %q", text, want)
+	}
+}
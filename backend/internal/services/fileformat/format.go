@@ -0,0 +1,103 @@
+// Package fileformat extracts proofreadable text from uploaded files and
+// writes corrections back into them, keeping the original formatting.
+package fileformat
+
+import (
+	"errors"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+var (
+	ErrUnsupported = errors.New("unsupported file format")
+	ErrInvalidFile = errors.New("file could not be read")
+)
+
+// Format reads and writes one file type. Extract returns the plain text that
+// is proofread; Apply writes edits, given as byte offsets into that text,
+// back into the original file.
+type Format interface {
+	Name() string
+	ContentType() string
+	Extract(data []byte) (string, error)
+	Apply(data []byte, edits []Edit, opts ApplyOptions) ([]byte, error)
+}
+
+// Edit replaces Start:End of the extracted text with Replacement.
+type Edit struct {
+	Start       int
+	End         int
+	Original    string
+	Replacement string
+	Reason      string
+}
+
+// ApplyOptions controls how edits are written. Formats without revision
+// tracking always apply edits directly.
+type ApplyOptions struct {
+	// TrackChanges writes edits as tracked changes with the reason as a
+	// comment, for review in the editor.
+	TrackChanges bool
+	Author       string
+	Date         time.Time
+}
+
+var formats = map[string]Format{}
+
+func register(f Format, extensions ...string) {
+	for _, ext := range extensions {
+		formats[ext] = f
+	}
+}
+
+// Lookup returns the format for a name or file extension such as "docx" or
+// ".docx".
+func Lookup(name string) (Format, error) {
+	f, ok := formats[strings.ToLower(strings.TrimPrefix(name, "."))]
+	if !ok {
+		return nil, ErrUnsupported
+	}
+	return f, nil
+}
+
+// ForFilename returns the format for a file's extension.
+func ForFilename(filename string) (Format, error) {
+	return Lookup(filepath.Ext(filename))
+}
+
+// Names lists the supported format names.
+func Names() []string {
+	seen := map[string]bool{}
+	var names []string
+	for _, f := range formats {
+		if !seen[f.Name()] {
+			seen[f.Name()] = true
+			names = append(names, f.Name())
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// usable sorts edits by position and drops those that do not match the text
+// or that overlap an earlier edit.
+func usable(text string, edits []Edit) []Edit {
+	sorted := append([]Edit(nil), edits...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Start < sorted[j].Start })
+
+	var out []Edit
+	end := -1
+	for _, e := range sorted {
+		if e.Start < 0 || e.End < e.Start || e.End > len(text) || text[e.Start:e.End] != e.Original {
+			continue
+		}
+		if e.Start < end {
+			continue
+		}
+		out = append(out, e)
+		end = e.End
+	}
+	return out
+}
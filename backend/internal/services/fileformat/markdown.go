@@ -0,0 +1,296 @@
+package fileformat
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+type markdown struct{}
+
+func init() {
+	register(markdown{}, "md", "markdown")
+}
+
+func (markdown) Name() string        { return "md" }
+func (markdown) ContentType() string { return "text/markdown; charset=utf-8" }
+
+func (markdown) Extract(data []byte) (string, error) {
+	m, err := mapMarkdown(data)
+	if err != nil {
+		return "", err
+	}
+	return m.Text, nil
+}
+
+func (markdown) Apply(data []byte, edits []Edit, _ ApplyOptions) ([]byte, error) {
+	m, err := mapMarkdown(data)
+	if err != nil {
+		return nil, err
+	}
+	return m.apply(data, edits), nil
+}
+
+var (
+	mdListMarker   = regexp.MustCompile(`^(?:[-*+]|\d{1,9}[.)])[ \t]+(?:\[[ xX]\][ \t]+)?`)
+	mdHeading      = regexp.MustCompile(`^#{1,6}(?:[ \t]+|$)`)
+	mdThematic     = regexp.MustCompile(`^(?:(?:-[ \t]*){3,}|(?:\*[ \t]*){3,}|(?:_[ \t]*){3,}|=+[ \t]*)$`)
+	mdLinkRefDef   = regexp.MustCompile(`^\[[^\]]+\]:[ \t]`)
+	mdHTMLBlock    = regexp.MustCompile(`^</?[A-Za-z][A-Za-z0-9-]*(?:[ \t>/]|$)|^<!--`)
+	mdInlineTag    = regexp.MustCompile(`^(?:</?[A-Za-z][^<>]*>|<!--.*?-->)`)
+	mdTrailingHash = regexp.MustCompile(`[ \t]+#+[ \t]*$`)
+)
+
+// mapMarkdown maps the prose of a Markdown file. Syntax is left out of the
+// text, as are code blocks, inline code, URLs, HTML and front matter, so
+// none of it can be changed by an edit. Each source line ends with a line
+// break in the text.
+func mapMarkdown(data []byte) (*textMap, error) {
+	if !utf8.Valid(data) {
+		return nil, fmt.Errorf("%w: text must be UTF-8", ErrInvalidFile)
+	}
+	src := string(data)
+
+	var b textMapBuilder
+	fence := ""
+	frontMatter := false
+	indentedCode := false
+	prevBlank := true
+
+	lineStart := 0
+	if strings.HasPrefix(src, utf8BOM) {
+		lineStart = len(utf8BOM)
+	}
+	for first := true; lineStart < len(src); first = false {
+		lineEnd := strings.IndexByte(src[lineStart:], '\n')
+		next := len(src)
+		if lineEnd < 0 {
+			lineEnd = len(src)
+		} else {
+			lineEnd += lineStart
+			next = lineEnd + 1
+		}
+		end := lineEnd
+		if end > lineStart && src[end-1] == '\r' {
+			end--
+		}
+		line := src[lineStart:end]
+		start := lineStart
+		lineStart = next
+
+		trimmed := strings.TrimLeft(line, " ")
+		indent := len(line) - len(trimmed)
+		blank := strings.TrimSpace(line) == ""
+
+		switch {
+		case first && strings.TrimSpace(line) == "---" && closesFrontMatter(src[lineStart:]):
+			frontMatter = true
+			continue
+		case frontMatter:
+			if t := strings.TrimSpace(line); t == "---" || t == "..." {
+				frontMatter = false
+			}
+			continue
+		case fence != "":
+			if strings.HasPrefix(trimmed, fence) && strings.Trim(trimmed, fence[:1]+" \t") == "" {
+				fence = ""
+			}
+			b.gap("\n")
+			continue
+		case indent < 4 && (strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~")):
+			fence = trimmed[:len(trimmed)-len(strings.TrimLeft(trimmed, trimmed[:1]))]
+			b.gap("\n")
+			continue
+		case !blank && (indent >= 4 || strings.HasPrefix(line, "\t")) && (prevBlank || indentedCode):
+			indentedCode = true
+			b.gap("\n")
+			continue
+		}
+		indentedCode = false
+		prevBlank = blank
+
+		if blank || mdThematic.MatchString(trimmed) || mdLinkRefDef.MatchString(trimmed) || mdHTMLBlock.MatchString(trimmed) {
+			b.gap("\n")
+			continue
+		}
+
+		pos := start + indent
+		body := trimmed
+		for {
+			if strings.HasPrefix(body, ">") {
+				n := 1
+				if strings.HasPrefix(body[1:], " ") {
+					n = 2
+				}
+				pos += n
+				body = body[n:]
+				continue
+			}
+			if loc := mdListMarker.FindStringIndex(body); loc != nil {
+				pos += loc[1]
+				body = body[loc[1]:]
+				continue
+			}
+			if loc := mdHeading.FindStringIndex(body); loc != nil {
+				pos += loc[1]
+				body = body[loc[1]:]
+				if loc := mdTrailingHash.FindStringIndex(body); loc != nil {
+					body = body[:loc[0]]
+				}
+			}
+			break
+		}
+
+		mapInlineMarkdown(&b, src, pos, pos+len(body))
+		b.gap("\n")
+	}
+
+	return b.build(nil), nil
+}
+
+// mapInlineMarkdown adds the prose of src[from:to], skipping inline syntax.
+func mapInlineMarkdown(b *textMapBuilder, src string, from, to int) {
+	segStart := from
+	flush := func(at int) {
+		b.add(src[segStart:at], segStart, at)
+	}
+
+	for i := from; i < to; {
+		c := src[i]
+		switch {
+		case c == '\\' && i+1 < to && isASCIIPunct(src[i+1]):
+			// The escaped character is text; the backslash is not.
+			flush(i)
+			segStart = i + 1
+			i += 2
+			continue
+
+		case c == '`':
+			n := runLength(src, i, to, '`')
+			if close := strings.Index(src[i+n:to], strings.Repeat("`", n)); close >= 0 {
+				flush(i)
+				i += n + close + n
+				segStart = i
+				continue
+			}
+			i += n
+			continue
+
+		case c == '!' && i+1 < to && src[i+1] == '[':
+			if _, end, ok := mdLink(src, i+1, to); ok {
+				flush(i)
+				i = end
+				segStart = i
+				continue
+			}
+
+		case c == '[':
+			if textEnd, end, ok := mdLink(src, i, to); ok {
+				flush(i)
+				mapInlineMarkdown(b, src, i+1, textEnd)
+				i = end
+				segStart = i
+				continue
+			}
+
+		case c == '<':
+			if loc := mdInlineTag.FindStringIndex(src[i:to]); loc != nil {
+				flush(i)
+				i += loc[1]
+				segStart = i
+				continue
+			}
+
+		case c == '*' || c == '~' || c == '|':
+			n := runLength(src, i, to, c)
+			flush(i)
+			i += n
+			segStart = i
+			continue
+
+		case c == '_':
+			n := runLength(src, i, to, c)
+			before, _ := utf8.DecodeLastRuneInString(src[from:i])
+			after, _ := utf8.DecodeRuneInString(src[i+n : to])
+			if isAlnum(before) && isAlnum(after) {
+				// Intra-word underscores, as in snake_case, are text.
+				i += n
+				continue
+			}
+			flush(i)
+			i += n
+			segStart = i
+			continue
+
+		case c == 'h' && (strings.HasPrefix(src[i:to], "http://") || strings.HasPrefix(src[i:to], "https://")):
+			end := i
+			for end < to && src[end] != ' ' && src[end] != '\t' && src[end] != ')' {
+				end++
+			}
+			flush(i)
+			i = end
+			segStart = i
+			continue
+		}
+		_, size := utf8.DecodeRuneInString(src[i:to])
+		i += size
+	}
+	flush(to)
+}
+
+// mdLink matches [text](url) or [text][ref] at i, returning the end of the
+// link text and of the whole link.
+func mdLink(src string, i, to int) (int, int, bool) {
+	close := strings.IndexByte(src[i:to], ']')
+	if close < 0 {
+		return 0, 0, false
+	}
+	textEnd := i + close
+	if textEnd+1 >= to {
+		return 0, 0, false
+	}
+	var closer byte
+	switch src[textEnd+1] {
+	case '(':
+		closer = ')'
+	case '[':
+		closer = ']'
+	default:
+		return 0, 0, false
+	}
+	end := strings.IndexByte(src[textEnd+2:to], closer)
+	if end < 0 {
+		return 0, 0, false
+	}
+	return textEnd, textEnd + 2 + end + 1, true
+}
+
+func runLength(src string, i, to int, c byte) int {
+	n := 0
+	for i+n < to && src[i+n] == c {
+		n++
+	}
+	return n
+}
+
+func isASCIIPunct(c byte) bool {
+	return c < utf8.RuneSelf && unicode.IsPunct(rune(c)) || strings.IndexByte("$+<=>^`|~", c) >= 0
+}
+
+func isAlnum(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.IsMark(r)
+}
+
+// closesFrontMatter reports whether rest, the text after an opening "---",
+// has a closing "---" or "..." line. Without one the "---" is a thematic
+// break and the document is ordinary text.
+func closesFrontMatter(rest string) bool {
+	for _, line := range strings.Split(rest, "\n") {
+		if t := strings.TrimSpace(line); t == "---" || t == "..." {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,37 @@
+package fileformat
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMapMarkdownFrontMatter(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want []string
+		skip []string
+	}{
+		{"closed", "---\ntitle: தலைப்பு\n---\nவணக்கம் உலகம்\n", []string{"வணக்கம் உலகம்"}, []string{"title"}},
+		{"closed with dots", "---\ntitle: x\n...\nவணக்கம்\n", []string{"வணக்கம்"}, []string{"title"}},
+		{"rule, never closed", "---\nவணக்கம் உலகம்\n\nஇரண்டாம் பத்தி\n", []string{"வணக்கம் உலகம்", "இரண்டாம் பத்தி"}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := mapMarkdown([]byte(tt.src))
+			if err != nil {
+				t.Fatalf("mapMarkdown: %v", err)
+			}
+			for _, w := range tt.want {
+				if !strings.Contains(m.Text, w) {
+					t.Errorf("text %q is missing %q", m.Text, w)
+				}
+			}
+			for _, w := range tt.skip {
+				if strings.Contains(m.Text, w) {
+					t.Errorf("text %q includes front matter %q", m.Text, w)
+				}
+			}
+		})
+	}
+}
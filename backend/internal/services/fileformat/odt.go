@@ -0,0 +1,152 @@
+package fileformat
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+const odtContentPart = "content.xml"
+
+type odt struct{}
+
+func init() {
+	register(odt{}, "odt")
+}
+
+func (odt) Name() string        { return "odt" }
+func (odt) ContentType() string { return "application/vnd.oasis.opendocument.text" }
+
+func (odt) Extract(data []byte) (string, error) {
+	r, err := openZip(data)
+	if err != nil {
+		return "", err
+	}
+	part, err := readODTContent(r)
+	if err != nil {
+		return "", err
+	}
+	m, err := mapODT(part)
+	if err != nil {
+		return "", err
+	}
+	return m.Text, nil
+}
+
+// Apply writes edits directly into content.xml; ODT tracked changes are not
+// produced.
+func (odt) Apply(data []byte, edits []Edit, _ ApplyOptions) ([]byte, error) {
+	r, err := openZip(data)
+	if err != nil {
+		return nil, err
+	}
+	part, err := readODTContent(r)
+	if err != nil {
+		return nil, err
+	}
+	m, err := mapODT(part)
+	if err != nil {
+		return nil, err
+	}
+	updated := m.apply(part, edits)
+	if bytes.Equal(updated, part) {
+		return data, nil
+	}
+	return rewriteZip(r, map[string][]byte{odtContentPart: updated})
+}
+
+func readODTContent(r *zip.Reader) ([]byte, error) {
+	part, err := readPart(r, odtContentPart)
+	if err != nil {
+		return nil, err
+	}
+	if part == nil {
+		return nil, fmt.Errorf("%w: missing %s", ErrInvalidFile, odtContentPart)
+	}
+	return part, nil
+}
+
+// maxSpaceRun bounds the spaces one text:s element stands for. Together with
+// a limit on all of them it keeps a small crafted file from expanding into a
+// huge text.
+const maxSpaceRun = 1000
+
+// mapODT maps the character data of paragraphs and headings in
+// content.xml. Spaces, tabs and line breaks written as elements become
+// uneditable gaps, and annotations are skipped.
+func mapODT(part []byte) (*textMap, error) {
+	dec := xml.NewDecoder(bytes.NewReader(part))
+	var b textMapBuilder
+	depth := 0 // open text:p and text:h elements
+	skip := 0  // open elements whose text is not part of the document
+	spaces := 0
+
+	for {
+		offset := int(dec.InputOffset())
+		tok, err := dec.RawToken()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidFile, err)
+		}
+		end := int(dec.InputOffset())
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			switch qualified(t.Name) {
+			case "text:p", "text:h":
+				depth++
+			case "office:annotation", "text:tracked-changes", "text:note-citation":
+				skip++
+			case "text:s":
+				if depth > 0 && skip == 0 {
+					n := 1
+					for _, a := range t.Attr {
+						if qualified(a.Name) == "text:c" {
+							c, err := strconv.Atoi(a.Value)
+							if err != nil || c > maxSpaceRun {
+								return nil, fmt.Errorf("%w: bad text:c %q", ErrInvalidFile, a.Value)
+							}
+							if c > 0 {
+								n = c
+							}
+						}
+					}
+					if spaces += n; spaces > maxPartSize {
+						return nil, fmt.Errorf("%w: too many spaces", ErrInvalidFile)
+					}
+					b.gap(strings.Repeat(" ", n))
+				}
+			case "text:tab":
+				if depth > 0 && skip == 0 {
+					b.gap("\t")
+				}
+			case "text:line-break":
+				if depth > 0 && skip == 0 {
+					b.gap("\n")
+				}
+			}
+		case xml.EndElement:
+			switch qualified(t.Name) {
+			case "text:p", "text:h":
+				depth--
+				if depth == 0 && skip == 0 {
+					b.gap("\n")
+				}
+			case "office:annotation", "text:tracked-changes", "text:note-citation":
+				skip--
+			}
+		case xml.CharData:
+			if depth > 0 && skip == 0 {
+				b.add(string(t), offset, end)
+			}
+		}
+	}
+
+	return b.build(xmlEscape), nil
+}
@@ -0,0 +1,24 @@
+package fileformat
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestMapODTSpaces(t *testing.T) {
+	part := []byte(`<office:text><text:p>அ<text:s text:c="3"/>ஆ<text:s/>இ</text:p></office:text>`)
+	m, err := mapODT(part)
+	if err != nil {
+		t.Fatalf("mapODT: %v", err)
+	}
+	if want := "அ   ஆ இ\n"; m.Text != want {
+		t.Errorf("text = %q, want %q", m.Text, want)
+	}
+
+	for _, c := range []string{"1000000000000", "1001", "-", "x"} {
+		part := []byte(`<office:text><text:p>அ<text:s text:c="` + c + `"/></text:p></office:text>`)
+		if _, err := mapODT(part); !errors.Is(err, ErrInvalidFile) {
+			t.Errorf("text:c=%q: err = %v, want ErrInvalidFile", c, err)
+		}
+	}
+}
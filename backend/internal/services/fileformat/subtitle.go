@@ -0,0 +1,91 @@
+package fileformat
+
+import (
+	"fmt"
+	"unicode/utf8"
+
+	"tamil-proofreading-platform/backend/internal/services/subtitle"
+)
+
+// subtitles reads SRT and WebVTT files. Only cue text is extracted; cues
+// are separated by blank lines so each is proofread as a unit, and
+// timings, settings and markup are never changed.
+type subtitles struct {
+	format subtitle.Format
+}
+
+func init() {
+	register(subtitles{format: subtitle.SRT}, "srt")
+	register(subtitles{format: subtitle.WebVTT}, "vtt", "webvtt")
+}
+
+func (s subtitles) Name() string { return string(s.format) }
+
+func (s subtitles) ContentType() string {
+	if s.format == subtitle.WebVTT {
+		return "text/vtt; charset=utf-8"
+	}
+	return "application/x-subrip; charset=utf-8"
+}
+
+func (s subtitles) Extract(data []byte) (string, error) {
+	m, err := s.mapCues(data)
+	if err != nil {
+		return "", err
+	}
+	return m.Text, nil
+}
+
+func (s subtitles) Apply(data []byte, edits []Edit, _ ApplyOptions) ([]byte, error) {
+	m, err := s.mapCues(data)
+	if err != nil {
+		return nil, err
+	}
+	return m.apply(data, edits), nil
+}
+
+func (s subtitles) mapCues(data []byte) (*textMap, error) {
+	if !utf8.Valid(data) {
+		return nil, fmt.Errorf("%w: text must be UTF-8", ErrInvalidFile)
+	}
+	cues, err := subtitle.Parse(string(data), s.format)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidFile, err)
+	}
+
+	var b textMapBuilder
+	for i, cue := range cues {
+		if i > 0 {
+			b.gap("\n\n")
+		}
+		for j, line := range cue.Lines {
+			if j > 0 {
+				b.gap("\n")
+			}
+			pos := 0
+			for _, tag := range subtitle.Markup(line.Text) {
+				b.add(line.Text[pos:tag[0]], line.Start+pos, line.Start+tag[0])
+				pos = tag[1]
+			}
+			b.add(line.Text[pos:], line.Start+pos, line.End)
+		}
+	}
+	return b.build(nil), nil
+}
+
+// Cues parses a subtitle file in the named format.
+func Cues(format string, data []byte) ([]subtitle.Cue, error) {
+	switch format {
+	case string(subtitle.SRT), string(subtitle.WebVTT):
+	default:
+		return nil, ErrUnsupported
+	}
+	if !utf8.Valid(data) {
+		return nil, fmt.Errorf("%w: text must be UTF-8", ErrInvalidFile)
+	}
+	cues, err := subtitle.Parse(string(data), subtitle.Format(format))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidFile, err)
+	}
+	return cues, nil
+}
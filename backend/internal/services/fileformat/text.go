@@ -0,0 +1,48 @@
+package fileformat
+
+import (
+	"fmt"
+	"unicode/utf8"
+)
+
+const utf8BOM = "\ufeff"
+
+type plainText struct{}
+
+func init() {
+	register(plainText{}, "txt", "text")
+}
+
+func (plainText) Name() string        { return "txt" }
+func (plainText) ContentType() string { return "text/plain; charset=utf-8" }
+
+func (plainText) Extract(data []byte) (string, error) {
+	m, err := mapPlainText(data)
+	if err != nil {
+		return "", err
+	}
+	return m.Text, nil
+}
+
+func (plainText) Apply(data []byte, edits []Edit, _ ApplyOptions) ([]byte, error) {
+	m, err := mapPlainText(data)
+	if err != nil {
+		return nil, err
+	}
+	return m.apply(data, edits), nil
+}
+
+// mapPlainText maps the whole file, after any byte order mark, as one
+// segment.
+func mapPlainText(data []byte) (*textMap, error) {
+	if !utf8.Valid(data) {
+		return nil, fmt.Errorf("%w: text must be UTF-8", ErrInvalidFile)
+	}
+	start := 0
+	if len(data) >= len(utf8BOM) && string(data[:len(utf8BOM)]) == utf8BOM {
+		start = len(utf8BOM)
+	}
+	var b textMapBuilder
+	b.add(string(data[start:]), start, len(data))
+	return b.build(nil), nil
+}
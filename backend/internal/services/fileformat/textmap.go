@@ -0,0 +1,144 @@
+package fileformat
+
+import (
+	"bytes"
+	"sort"
+	"strings"
+)
+
+// textSegment maps Start:End of the extracted text to SrcStart:SrcEnd of the
+// source file. Text between segments, such as line breaks standing in for
+// markup, has no source and cannot be edited.
+type textSegment struct {
+	Start    int
+	End      int
+	SrcStart int
+	SrcEnd   int
+}
+
+// textMap is extracted text with its position map back to the source.
+// Escape encodes segment text for the source, e.g. as XML.
+type textMap struct {
+	Text     string
+	Segments []textSegment
+	Escape   func(string) string
+}
+
+// textMapBuilder accumulates a textMap while a format walks its source.
+type textMapBuilder struct {
+	text     strings.Builder
+	segments []textSegment
+}
+
+// add appends text taken from src[srcStart:srcEnd].
+func (b *textMapBuilder) add(text string, srcStart, srcEnd int) {
+	if text == "" {
+		return
+	}
+	start := b.text.Len()
+	b.text.WriteString(text)
+	b.segments = append(b.segments, textSegment{Start: start, End: b.text.Len(), SrcStart: srcStart, SrcEnd: srcEnd})
+}
+
+// gap appends text that has no editable source.
+func (b *textMapBuilder) gap(text string) {
+	b.text.WriteString(text)
+}
+
+func (b *textMapBuilder) build(escape func(string) string) *textMap {
+	return &textMap{Text: b.text.String(), Segments: b.segments, Escape: escape}
+}
+
+// apply writes edits into src. An edit spanning several segments puts its
+// replacement in the first and removes its text from the rest; edits that
+// touch text without a source are skipped.
+func (m *textMap) apply(src []byte, edits []Edit) []byte {
+	type cut struct {
+		start, end int
+		insert     string
+	}
+	cuts := make(map[int][]cut)
+
+	for _, e := range usable(m.Text, edits) {
+		pieces := m.pieces(e)
+		for i, p := range pieces {
+			c := cut{start: p.start, end: p.end}
+			if i == 0 {
+				c.insert = e.Replacement
+			}
+			cuts[p.segment] = append(cuts[p.segment], c)
+		}
+	}
+	if len(cuts) == 0 {
+		return src
+	}
+
+	var out bytes.Buffer
+	pos := 0
+	for i, seg := range m.Segments {
+		segCuts, ok := cuts[i]
+		if !ok {
+			continue
+		}
+		sort.SliceStable(segCuts, func(a, b int) bool { return segCuts[a].start < segCuts[b].start })
+
+		text := m.Text[seg.Start:seg.End]
+		var b strings.Builder
+		at := 0
+		for _, c := range segCuts {
+			b.WriteString(text[at:c.start])
+			b.WriteString(c.insert)
+			at = c.end
+		}
+		b.WriteString(text[at:])
+
+		out.Write(src[pos:seg.SrcStart])
+		escape := m.Escape
+		if escape == nil {
+			escape = func(s string) string { return s }
+		}
+		out.WriteString(escape(b.String()))
+		pos = seg.SrcEnd
+	}
+	out.Write(src[pos:])
+	return out.Bytes()
+}
+
+type textPiece struct {
+	segment    int
+	start, end int // offsets within the segment text
+}
+
+func (m *textMap) pieces(e Edit) []textPiece {
+	if e.Start == e.End {
+		for i, seg := range m.Segments {
+			if seg.Start <= e.Start && e.Start <= seg.End {
+				at := e.Start - seg.Start
+				return []textPiece{{segment: i, start: at, end: at}}
+			}
+		}
+		return nil
+	}
+
+	var pieces []textPiece
+	covered := e.Start
+	for i, seg := range m.Segments {
+		if seg.End <= e.Start {
+			continue
+		}
+		if seg.Start >= e.End {
+			break
+		}
+		if seg.Start > covered {
+			return nil
+		}
+		from := max(e.Start, seg.Start)
+		to := min(e.End, seg.End)
+		pieces = append(pieces, textPiece{segment: i, start: from - seg.Start, end: to - seg.Start})
+		covered = to
+	}
+	if covered < e.End {
+		return nil
+	}
+	return pieces
+}
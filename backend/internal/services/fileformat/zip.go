@@ -0,0 +1,104 @@
+package fileformat
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"io"
+	"sort"
+)
+
+// maxPartSize bounds how much of one archive entry is read, so a small
+// upload cannot expand without limit.
+const maxPartSize = 50 << 20
+
+func openZip(data []byte) (*zip.Reader, error) {
+	r, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidFile, err)
+	}
+	return r, nil
+}
+
+// readPart returns the contents of the named entry, or nil if it is
+// missing.
+func readPart(r *zip.Reader, name string) ([]byte, error) {
+	for _, f := range r.File {
+		if f.Name != name {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidFile, err)
+		}
+		defer rc.Close()
+		data, err := io.ReadAll(io.LimitReader(rc, maxPartSize+1))
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidFile, err)
+		}
+		if len(data) > maxPartSize {
+			return nil, fmt.Errorf("%w: %s is too large", ErrInvalidFile, name)
+		}
+		return data, nil
+	}
+	return nil, nil
+}
+
+// rewriteZip copies an archive, replacing entries named in parts and adding
+// the ones it does not contain. Entries keep their order and headers.
+func rewriteZip(r *zip.Reader, parts map[string][]byte) ([]byte, error) {
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	written := map[string]bool{}
+
+	for _, f := range r.File {
+		header := f.FileHeader
+		if data, ok := parts[f.Name]; ok {
+			header.Method = zip.Deflate
+			fw, err := w.CreateHeader(&header)
+			if err != nil {
+				return nil, err
+			}
+			if _, err := fw.Write(data); err != nil {
+				return nil, err
+			}
+			written[f.Name] = true
+			continue
+		}
+
+		fw, err := w.CreateRaw(&header)
+		if err != nil {
+			return nil, err
+		}
+		rc, err := f.OpenRaw()
+		if err != nil {
+			return nil, err
+		}
+		if _, err := io.Copy(fw, rc); err != nil {
+			return nil, err
+		}
+	}
+
+	var added []string
+	for name := range parts {
+		if !written[name] {
+			added = append(added, name)
+		}
+	}
+	sort.Strings(added)
+	for _, name := range added {
+		data := parts[name]
+		fw, err := w.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Deflate})
+		if err != nil {
+			return nil, err
+		}
+		if _, err := fw.Write(data); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
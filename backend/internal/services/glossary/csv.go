@@ -0,0 +1,157 @@
+package glossary
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+
+	"tamil-proofreading-platform/backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// ImportResult counts the effect of a CSV import.
+type ImportResult struct {
+	Added   int `json:"added"`
+	Updated int `json:"updated"`
+	Removed int `json:"removed"`
+}
+
+// ParseCSV reads glossary terms. The header must include a term column;
+// kind, variants (pipe-separated), replacement, match_mode and note are
+// optional.
+func ParseCSV(r io.Reader) ([]models.GlossaryTerm, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("read header: %w", err)
+	}
+	index := make(map[string]int, len(header))
+	for i, name := range header {
+		index[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))] = i
+	}
+	if _, ok := index["term"]; !ok {
+		return nil, fmt.Errorf("header must include a term column")
+	}
+
+	field := func(record []string, name string) string {
+		if i, ok := index[name]; ok && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	var terms []models.GlossaryTerm
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+
+		term := models.GlossaryTerm{
+			Term:        field(record, "term"),
+			Kind:        models.GlossaryTermKind(strings.ToLower(field(record, "kind"))),
+			Replacement: field(record, "replacement"),
+			MatchMode:   models.GlossaryMatchMode(strings.ToLower(field(record, "match_mode"))),
+			Note:        field(record, "note"),
+		}
+		if variants := field(record, "variants"); variants != "" {
+			term.Variants = strings.Split(variants, "|")
+		}
+		term.Normalize()
+		if err := Validate(&term); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		terms = append(terms, term)
+	}
+	return terms, nil
+}
+
+// Validate checks a normalized term.
+func Validate(t *models.GlossaryTerm) error {
+	if t.Term == "" {
+		return fmt.Errorf("term is required")
+	}
+	switch t.Kind {
+	case models.TermPreferred:
+		if len(t.Variants) == 0 {
+			return fmt.Errorf("preferred term %q needs at least one variant", t.Term)
+		}
+	case models.TermForbidden:
+	default:
+		return fmt.Errorf("kind must be preferred or forbidden")
+	}
+	switch t.MatchMode {
+	case models.MatchWord, models.MatchStem:
+	default:
+		return fmt.Errorf("match_mode must be word or stem")
+	}
+	return nil
+}
+
+// Import upserts terms by term text and kind. With replace set, terms missing
+// from the import are removed. The change is committed as one version.
+func (s *GlossaryService) Import(glossary *models.Glossary, terms []models.GlossaryTerm, replace bool, userID uint) (ImportResult, error) {
+	var result ImportResult
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var existing []models.GlossaryTerm
+		if err := tx.Where("glossary_id = ?", glossary.ID).Find(&existing).Error; err != nil {
+			return err
+		}
+		byKey := make(map[string]models.GlossaryTerm, len(existing))
+		for _, t := range existing {
+			byKey[termKey(t)] = t
+		}
+
+		seen := make(map[string]bool, len(terms))
+		for _, t := range terms {
+			key := termKey(t)
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+
+			t.GlossaryID = glossary.ID
+			if current, ok := byKey[key]; ok {
+				t.ID = current.ID
+				t.CreatedAt = current.CreatedAt
+				if err := tx.Save(&t).Error; err != nil {
+					return err
+				}
+				result.Updated++
+				continue
+			}
+			if err := tx.Create(&t).Error; err != nil {
+				return err
+			}
+			result.Added++
+		}
+
+		if replace {
+			for key, t := range byKey {
+				if seen[key] {
+					continue
+				}
+				if err := tx.Delete(&models.GlossaryTerm{}, t.ID).Error; err != nil {
+					return err
+				}
+				result.Removed++
+			}
+		}
+
+		summary := fmt.Sprintf("CSV import: %d added, %d updated, %d removed", result.Added, result.Updated, result.Removed)
+		return Commit(tx, glossary, userID, summary)
+	})
+	return result, err
+}
+
+func termKey(t models.GlossaryTerm) string {
+	return string(t.Kind) + "\x00" + t.Term
+}
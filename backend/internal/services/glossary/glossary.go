@@ -0,0 +1,121 @@
+// Package glossary manages organization glossaries: loading the terms that
+// apply to a user, versioned edits and CSV import.
+package glossary
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"tamil-proofreading-platform/backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+var ErrVersionNotFound = errors.New("glossary version not found")
+
+type GlossaryService struct {
+	db *gorm.DB
+}
+
+func NewGlossaryService(db *gorm.DB) *GlossaryService {
+	return &GlossaryService{db: db}
+}
+
+// Membership returns the user's organization membership, or nil when the
+// user does not belong to an organization.
+func (s *GlossaryService) Membership(userID uint) (*models.OrganizationMember, error) {
+	var member models.OrganizationMember
+	err := s.db.Where("user_id = ?", userID).First(&member).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &member, nil
+}
+
+// TermsForUser returns the terms of every active glossary in the user's
+// organization.
+func (s *GlossaryService) TermsForUser(userID uint) ([]models.GlossaryTerm, error) {
+	member, err := s.Membership(userID)
+	if err != nil || member == nil {
+		return nil, err
+	}
+
+	var terms []models.GlossaryTerm
+	err = s.db.Joins("JOIN glossaries ON glossaries.id = glossary_terms.glossary_id").
+		Where("glossaries.organization_id = ? AND glossaries.is_active = ? AND glossaries.deleted_at IS NULL", member.OrganizationID, true).
+		Order("glossary_terms.id ASC").
+		Find(&terms).Error
+	return terms, err
+}
+
+// Commit bumps the glossary version and snapshots its current terms. Call it
+// inside the transaction that changed the terms. The version is bumped in
+// the database first, which locks the glossary row, so concurrent edits take
+// successive versions and each snapshot sees the edits committed before it.
+func Commit(tx *gorm.DB, glossary *models.Glossary, userID uint, summary string) error {
+	var bumped struct {
+		Version   int
+		UpdatedAt time.Time
+	}
+	if err := tx.Raw("UPDATE glossaries SET version = version + 1, updated_at = ? WHERE id = ? RETURNING version, updated_at",
+		time.Now(), glossary.ID).Scan(&bumped).Error; err != nil {
+		return err
+	}
+	glossary.Version, glossary.UpdatedAt = bumped.Version, bumped.UpdatedAt
+
+	var terms []models.GlossaryTerm
+	if err := tx.Where("glossary_id = ?", glossary.ID).Order("id ASC").Find(&terms).Error; err != nil {
+		return err
+	}
+	snapshot, err := models.SnapshotTerms(terms)
+	if err != nil {
+		return err
+	}
+	return tx.Create(&models.GlossaryVersion{
+		GlossaryID: glossary.ID,
+		Version:    glossary.Version,
+		Terms:      snapshot,
+		TermCount:  len(terms),
+		Summary:    summary,
+		CreatedBy:  userID,
+	}).Error
+}
+
+// Restore replaces the glossary's terms with a snapshot and records the
+// restore as a new version.
+func (s *GlossaryService) Restore(glossary *models.Glossary, version int, userID uint) error {
+	var snapshot models.GlossaryVersion
+	err := s.db.Where("glossary_id = ? AND version = ?", glossary.ID, version).First(&snapshot).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrVersionNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	var terms []models.GlossaryTerm
+	if err := json.Unmarshal([]byte(snapshot.Terms), &terms); err != nil {
+		return fmt.Errorf("decode version %d: %w", version, err)
+	}
+
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("glossary_id = ?", glossary.ID).Delete(&models.GlossaryTerm{}).Error; err != nil {
+			return err
+		}
+		for i := range terms {
+			terms[i].ID = 0
+			terms[i].GlossaryID = glossary.ID
+		}
+		if len(terms) > 0 {
+			if err := tx.Create(&terms).Error; err != nil {
+				return err
+			}
+		}
+		return Commit(tx, glossary, userID, fmt.Sprintf("Restored version %d", version))
+	})
+}
@@ -0,0 +1,109 @@
+// Package grantha keeps the admin-editable grantha / Sanskrit-origin to pure
+// Tamil mapping table.
+package grantha
+
+import (
+	"sync"
+
+	"tamil-proofreading-platform/backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// defaults seed an empty table. Admins edit the table from then on.
+var defaults = []struct {
+	word         string
+	alternatives []string
+}{
+	{"ஜனநாயகம்", []string{"மக்களாட்சி"}},
+	{"ஜனாதிபதி", []string{"குடியரசுத் தலைவர்"}},
+	{"ஜனங்கள்", []string{"மக்கள்"}},
+	{"ஜலம்", []string{"நீர்"}},
+	{"ஜன்னல்", []string{"சாளரம்"}},
+	{"ராஜா", []string{"அரசன்", "மன்னன்"}},
+	{"ஜாதி", []string{"சாதி"}},
+	{"ஜோதி", []string{"ஒளி", "சோதி"}},
+	{"பூஜை", []string{"பூசை", "வழிபாடு"}},
+	{"விஷயம்", []string{"செய்தி", "பொருள்"}},
+	{"ஸ்நேகிதன்", []string{"நண்பன்"}},
+	{"ஸ்நேகம்", []string{"நட்பு"}},
+	{"சந்தோஷம்", []string{"மகிழ்ச்சி"}},
+	{"ஆஸ்பத்திரி", []string{"மருத்துவமனை"}},
+	{"ஹோட்டல்", []string{"உணவகம்"}},
+	{"புஸ்தகம்", []string{"நூல்", "புத்தகம்"}},
+	{"ஸ்வாமி", []string{"கடவுள்", "சாமி"}},
+	{"கஷ்டம்", []string{"துன்பம்", "கடினம்"}},
+	{"இஷ்டம்", []string{"விருப்பம்"}},
+	{"நஷ்டம்", []string{"இழப்பு"}},
+	{"வருஷம்", []string{"ஆண்டு"}},
+	{"பாஷை", []string{"மொழி"}},
+	{"ரிஷி", []string{"முனிவர்"}},
+	{"அக்ஷரம்", []string{"எழுத்து"}},
+	{"பக்ஷி", []string{"பறவை"}},
+	{"ஹாஸ்யம்", []string{"நகைச்சுவை"}},
+	{"ஸ்ரீ", []string{"திரு"}},
+	{"ஸ்தாபனம்", []string{"நிறுவனம்"}},
+	{"ஆரம்பம்", []string{"தொடக்கம்"}},
+	{"உபயோகம்", []string{"பயன்பாடு"}},
+	{"அபிப்ராயம்", []string{"கருத்து"}},
+	{"அவசியம்", []string{"தேவை"}},
+	{"ஆசீர்வாதம்", []string{"வாழ்த்து"}},
+	{"பிரயாணம்", []string{"பயணம்"}},
+	{"சுதந்திரம்", []string{"விடுதலை"}},
+	{"சமுத்திரம்", []string{"கடல்"}},
+}
+
+type GranthaService struct {
+	db *gorm.DB
+
+	mu       sync.RWMutex
+	mappings []models.GranthaMapping
+	loaded   bool
+}
+
+func NewGranthaService(db *gorm.DB) *GranthaService {
+	return &GranthaService{db: db}
+}
+
+// SeedDefaults fills the mapping table when it is empty.
+func SeedDefaults(db *gorm.DB) error {
+	var count int64
+	if err := db.Model(&models.GranthaMapping{}).Count(&count).Error; err != nil || count > 0 {
+		return err
+	}
+
+	rows := make([]models.GranthaMapping, 0, len(defaults))
+	for _, d := range defaults {
+		rows = append(rows, models.GranthaMapping{Word: d.word, Alternatives: d.alternatives, IsActive: true})
+	}
+	return db.Create(&rows).Error
+}
+
+// Mappings returns the active mappings, cached until Invalidate.
+func (s *GranthaService) Mappings() ([]models.GranthaMapping, error) {
+	s.mu.RLock()
+	if s.loaded {
+		defer s.mu.RUnlock()
+		return s.mappings, nil
+	}
+	s.mu.RUnlock()
+
+	var mappings []models.GranthaMapping
+	if err := s.db.Where("is_active = ?", true).Find(&mappings).Error; err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	s.mappings = mappings
+	s.loaded = true
+	s.mu.Unlock()
+	return mappings, nil
+}
+
+// Invalidate drops the cache after an admin edit.
+func (s *GranthaService) Invalidate() {
+	s.mu.Lock()
+	s.loaded = false
+	s.mappings = nil
+	s.mu.Unlock()
+}
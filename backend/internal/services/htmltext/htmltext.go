@@ -0,0 +1,314 @@
+// Package htmltext extracts the text of rich-text HTML for proofreading and
+// writes corrections back into the text nodes it came from, so the markup
+// around them is left as it was.
+package htmltext
+
+import (
+	"bytes"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// Document is an HTML fragment with the text a reader sees in it. Block
+// elements become line breaks and runs of whitespace collapse to one space,
+// as a browser would render them.
+type Document struct {
+	Text  string
+	nodes []*html.Node
+	runs  []run
+}
+
+// run is a stretch of Text taken from one text node. offsets[i] is the byte
+// offset in node.Data of Text[start+i], and offsets[end-start] is where the
+// run ends in node.Data; a collapsed space covers all the whitespace it
+// stands for.
+type run struct {
+	start, end int
+	node       *html.Node
+	path       []int
+	offsets    []int
+}
+
+// Position locates part of the text inside the fragment: Path is the child
+// index of each element from the top level down to the text node, and
+// Start:End are byte offsets in that node's decoded text.
+type Position struct {
+	Path  []int `json:"path"`
+	Start int   `json:"start"`
+	End   int   `json:"end"`
+}
+
+// Edit replaces Text[Start:End], which must equal Original.
+type Edit struct {
+	Start       int
+	End         int
+	Original    string
+	Replacement string
+}
+
+// skipped elements have no visible text.
+var skipped = map[atom.Atom]bool{
+	atom.Script: true, atom.Style: true, atom.Template: true, atom.Noscript: true,
+	atom.Head: true, atom.Title: true, atom.Iframe: true, atom.Object: true,
+	atom.Svg: true, atom.Math: true, atom.Select: true,
+}
+
+// breaks are elements that start a new line; other block elements start a
+// new paragraph.
+var breaks = map[atom.Atom]string{
+	atom.Br: "\n", atom.Li: "\n", atom.Dt: "\n", atom.Dd: "\n",
+	atom.Tr: "\n", atom.Td: "\n", atom.Th: "\n", atom.Caption: "\n",
+	atom.P: "\n\n", atom.Div: "\n\n", atom.Blockquote: "\n\n", atom.Pre: "\n\n",
+	atom.H1: "\n\n", atom.H2: "\n\n", atom.H3: "\n\n", atom.H4: "\n\n", atom.H5: "\n\n", atom.H6: "\n\n",
+	atom.Ul: "\n\n", atom.Ol: "\n\n", atom.Dl: "\n\n", atom.Table: "\n\n", atom.Hr: "\n\n",
+	atom.Section: "\n\n", atom.Article: "\n\n", atom.Aside: "\n\n", atom.Header: "\n\n",
+	atom.Footer: "\n\n", atom.Nav: "\n\n", atom.Main: "\n\n", atom.Figure: "\n\n",
+	atom.Figcaption: "\n\n", atom.Address: "\n\n", atom.Details: "\n\n", atom.Summary: "\n",
+}
+
+// preformatted elements keep their whitespace.
+var preformatted = map[atom.Atom]bool{atom.Pre: true, atom.Textarea: true, atom.Listing: true}
+
+// Parse parses an HTML fragment as the content of a <body>.
+func Parse(src string) (*Document, error) {
+	body := &html.Node{Type: html.ElementNode, Data: "body", DataAtom: atom.Body}
+	nodes, err := html.ParseFragment(strings.NewReader(src), body)
+	if err != nil {
+		return nil, err
+	}
+
+	e := &extractor{}
+	for i, n := range nodes {
+		e.walk(n, []int{i}, false)
+	}
+	return &Document{Text: e.text.String(), nodes: nodes, runs: e.runs}, nil
+}
+
+type extractor struct {
+	text strings.Builder
+	runs []run
+
+	// A space or line break is only written once text follows it, so none
+	// is left at the start or end, or doubled up between blocks.
+	pendingBreak string
+	space        *html.Node
+	spacePath    []int
+	spaceStart   int
+	spaceEnd     int
+}
+
+func (e *extractor) walk(n *html.Node, path []int, pre bool) {
+	switch n.Type {
+	case html.TextNode:
+		e.addText(n, path, pre)
+		return
+	case html.ElementNode:
+		if skipped[n.DataAtom] {
+			return
+		}
+		pre = pre || preformatted[n.DataAtom]
+	case html.DocumentNode:
+	default:
+		return
+	}
+
+	sep := breaks[n.DataAtom]
+	e.lineBreak(sep)
+	i := 0
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		e.walk(c, append(path[:len(path):len(path)], i), pre)
+		i++
+	}
+	e.lineBreak(sep)
+}
+
+func (e *extractor) lineBreak(sep string) {
+	if sep == "" {
+		return
+	}
+	e.space = nil
+	if len(sep) > len(e.pendingBreak) {
+		e.pendingBreak = sep
+	}
+}
+
+func (e *extractor) addText(n *html.Node, path []int, pre bool) {
+	data := n.Data
+	for i := 0; i < len(data); {
+		r, size := utf8.DecodeRuneInString(data[i:])
+		if !pre && isSpace(r) {
+			j := i
+			for j < len(data) && isSpace(rune(data[j])) {
+				j++
+			}
+			if e.space == nil && e.text.Len() > 0 && e.pendingBreak == "" {
+				e.space, e.spacePath, e.spaceStart, e.spaceEnd = n, path, i, j
+			}
+			i = j
+			continue
+		}
+		e.flush()
+		e.emit(n, path, data[i:i+size], i, i+size)
+		i += size
+	}
+}
+
+// flush writes any pending line break or space before more text.
+func (e *extractor) flush() {
+	if e.pendingBreak != "" {
+		if e.text.Len() > 0 {
+			e.text.WriteString(e.pendingBreak)
+		}
+		e.pendingBreak = ""
+	}
+	if e.space != nil {
+		e.emit(e.space, e.spacePath, " ", e.spaceStart, e.spaceEnd)
+		e.space = nil
+	}
+}
+
+// emit appends s, which stands for n.Data[start:end].
+func (e *extractor) emit(n *html.Node, path []int, s string, start, end int) {
+	pos := e.text.Len()
+	e.text.WriteString(s)
+
+	if len(e.runs) == 0 || e.runs[len(e.runs)-1].node != n || e.runs[len(e.runs)-1].end != pos {
+		e.runs = append(e.runs, run{start: pos, end: pos, node: n, path: path, offsets: []int{start}})
+	}
+	r := &e.runs[len(e.runs)-1]
+	r.offsets = r.offsets[:len(r.offsets)-1]
+	for i := 0; i < len(s); i++ {
+		if len(s) == end-start {
+			r.offsets = append(r.offsets, start+i)
+		} else {
+			r.offsets = append(r.offsets, start)
+		}
+	}
+	r.offsets = append(r.offsets, end)
+	r.end = e.text.Len()
+}
+
+func isSpace(r rune) bool {
+	return r == ' ' || r == '\t' || r == '\n' || r == '\r' || r == '\f'
+}
+
+// piece is the part of an edit that falls in one run.
+type piece struct {
+	run        int
+	start, end int // offsets in the run's node.Data
+}
+
+// pieces splits Text[start:end] by run. It returns false if part of the
+// range, such as a line break between blocks, has no text node behind it.
+func (d *Document) pieces(start, end int) ([]piece, bool) {
+	i := sort.Search(len(d.runs), func(i int) bool { return d.runs[i].end > start })
+	if start == end {
+		// An insertion goes into the run it falls in, or the one it ends
+		if i < len(d.runs) && d.runs[i].start <= start {
+			r := d.runs[i]
+			return []piece{{run: i, start: r.offsets[start-r.start], end: r.offsets[start-r.start]}}, true
+		}
+		if i > 0 && d.runs[i-1].end == start {
+			r := d.runs[i-1]
+			return []piece{{run: i - 1, start: r.offsets[len(r.offsets)-1], end: r.offsets[len(r.offsets)-1]}}, true
+		}
+		return nil, false
+	}
+
+	var out []piece
+	pos := start
+	for ; i < len(d.runs) && pos < end; i++ {
+		r := d.runs[i]
+		if r.start > pos {
+			return nil, false
+		}
+		to := min(end, r.end)
+		out = append(out, piece{run: i, start: r.offsets[pos-r.start], end: r.offsets[to-r.start]})
+		pos = to
+	}
+	return out, pos == end
+}
+
+// Positions maps Text[start:end] to the text nodes it was taken from. It
+// returns nil if the range is out of bounds or crosses a line break between
+// blocks.
+func (d *Document) Positions(start, end int) []Position {
+	if start < 0 || end < start || end > len(d.Text) {
+		return nil
+	}
+	pieces, ok := d.pieces(start, end)
+	if !ok {
+		return nil
+	}
+	out := make([]Position, len(pieces))
+	for i, p := range pieces {
+		out[i] = Position{Path: d.runs[p.run].path, Start: p.start, End: p.end}
+	}
+	return out
+}
+
+// Apply writes edits into the text nodes and returns how many were made.
+// An edit spanning several nodes puts its replacement in the first and
+// removes its text from the rest, so it takes on the first node's
+// formatting. Edits that do not match the text, overlap an earlier edit or
+// cross a line break between blocks are skipped. Apply changes the
+// document's nodes but not Text, so it should be called once.
+func (d *Document) Apply(edits []Edit) int {
+	sorted := append([]Edit(nil), edits...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Start < sorted[j].Start })
+
+	type cut struct {
+		start, end int
+		insert     string
+	}
+	cuts := make(map[*html.Node][]cut)
+	applied, last := 0, -1
+	for _, e := range sorted {
+		if e.Start < 0 || e.End < e.Start || e.End > len(d.Text) || d.Text[e.Start:e.End] != e.Original || e.Start < last {
+			continue
+		}
+		pieces, ok := d.pieces(e.Start, e.End)
+		if !ok {
+			continue
+		}
+		for i, p := range pieces {
+			c := cut{start: p.start, end: p.end}
+			if i == 0 {
+				c.insert = e.Replacement
+			}
+			n := d.runs[p.run].node
+			cuts[n] = append(cuts[n], c)
+		}
+		applied++
+		last = e.End
+	}
+
+	for n, list := range cuts {
+		var b strings.Builder
+		pos := 0
+		for _, c := range list {
+			b.WriteString(n.Data[pos:c.start])
+			b.WriteString(c.insert)
+			pos = c.end
+		}
+		b.WriteString(n.Data[pos:])
+		n.Data = b.String()
+	}
+	return applied
+}
+
+// HTML renders the fragment. Text is escaped as needed, so corrections
+// cannot introduce markup.
+func (d *Document) HTML() string {
+	var b bytes.Buffer
+	for _, n := range d.nodes {
+		if err := html.Render(&b, n); err != nil {
+			return ""
+		}
+	}
+	return b.String()
+}
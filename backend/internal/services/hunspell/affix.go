@@ -0,0 +1,124 @@
+// Package hunspell builds Hunspell .dic/.aff dictionaries from the lexicon so
+// writers can use the same word list in LibreOffice and other spellcheckers.
+package hunspell
+
+import (
+	"strings"
+
+	"tamil-proofreading-platform/backend/internal/models"
+	"tamil-proofreading-platform/backend/internal/services/lexicon"
+)
+
+// Affix flags assigned by word ending. Each class attaches the common case
+// suffixes (accusative, dative, instrumental, sociative, genitive, locative)
+// and the plural using the joining rule for that ending.
+const (
+	flagConsonant = "C" // ends in pulli, other than ம் (அவன் → அவனை)
+	flagMFinal    = "M" // ends in ம் (மரம் → மரத்தை)
+	flagFrontV    = "I" // ends in ி ீ ை ே, takes the ய் glide (பள்ளி → பள்ளியை)
+	flagBackV     = "A" // ends in ா ோ ொ ூ ௌ, takes the வ் glide (அம்மா → அம்மாவை)
+	flagShortU    = "U" // ends in ு (கதவு → கதவை)
+)
+
+// AffixRules is the .aff file served with generated dictionaries. Gemination
+// after டு/று (வீடு → வீட்டை) is not modelled; such inflections are only
+// accepted when the lexicon lists them as words.
+const AffixRules = `SET UTF-8
+LANG ta_IN
+TRY அஆஇஈஉஊஎஏஐஒஓஔகஙசஞடணதநபமயரலவழளறனஜஷஸஹ்ாிீுூெேைொோௌ
+
+# Common confusions between similar-sounding letters
+REP 8
+REP ல ள
+REP ள ழ
+REP ழ ள
+REP ன ண
+REP ண ன
+REP ந ன
+REP ர ற
+REP ற ர
+
+SFX C Y 8
+SFX C ் ை [^ம]்
+SFX C ் ுக்கு [^ம]்
+SFX C ் ால் [^ம]்
+SFX C ் ுடன் [^ம]்
+SFX C ் ின் [^ம]்
+SFX C ் ில் [^ம]்
+SFX C ் ிடம் [^ம]்
+SFX C 0 கள் [^ம]்
+
+SFX M Y 7
+SFX M ம் த்தை ம்
+SFX M ம் த்துக்கு ம்
+SFX M ம் த்தால் ம்
+SFX M ம் த்துடன் ம்
+SFX M ம் த்தின் ம்
+SFX M ம் த்தில் ம்
+SFX M ம் ங்கள் ம்
+
+SFX I Y 7
+SFX I 0 யை [ிீைே]
+SFX I 0 க்கு [ிீைே]
+SFX I 0 யால் [ிீைே]
+SFX I 0 யுடன் [ிீைே]
+SFX I 0 யின் [ிீைே]
+SFX I 0 யில் [ிீைே]
+SFX I 0 கள் [ிீைே]
+
+SFX A Y 7
+SFX A 0 வை [ாோொூௌ]
+SFX A 0 வுக்கு [ாோொூௌ]
+SFX A 0 வால் [ாோொூௌ]
+SFX A 0 வுடன் [ாோொூௌ]
+SFX A 0 வின் [ாோொூௌ]
+SFX A 0 வில் [ாோொூௌ]
+SFX A 0 க்கள் [ாோொூௌ]
+
+SFX U Y 7
+SFX U ு ை ு
+SFX U ு ுக்கு ு
+SFX U ு ால் ு
+SFX U ு ுடன் ு
+SFX U ு ின் ு
+SFX U ு ில் ு
+SFX U 0 கள் ு
+`
+
+// inflectedCategories are the categories that take case suffixes. Personal
+// dictionary words have no category and are treated as nouns.
+var inflectedCategories = map[string]bool{
+	"":                                true,
+	string(models.CategoryCommon):     true,
+	string(models.CategoryNoun):       true,
+	string(models.CategoryProperNoun): true,
+	string(models.CategoryPronoun):    true,
+}
+
+// FlagsFor returns the affix flag for an entry based on its final letter.
+func FlagsFor(e lexicon.Entry) string {
+	if !inflectedCategories[e.Category] || strings.ContainsRune(e.Tamil, ' ') {
+		return ""
+	}
+
+	runes := []rune(e.Tamil)
+	if len(runes) < 2 {
+		return ""
+	}
+	last := runes[len(runes)-1]
+	prev := runes[len(runes)-2]
+
+	switch {
+	case last == '்' && prev == 'ம':
+		return flagMFinal
+	case last == '்':
+		return flagConsonant
+	case strings.ContainsRune("ிீைே", last):
+		return flagFrontV
+	case strings.ContainsRune("ாோொூௌ", last):
+		return flagBackV
+	case last == 'ு':
+		return flagShortU
+	}
+	return ""
+}
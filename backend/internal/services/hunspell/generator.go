@@ -0,0 +1,194 @@
+package hunspell
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"tamil-proofreading-platform/backend/internal/models"
+	"tamil-proofreading-platform/backend/internal/services/lexicon"
+
+	"gorm.io/gorm"
+)
+
+// recheckInterval bounds how often the lexicon fingerprint is queried.
+const recheckInterval = time.Minute
+
+// maxRendered bounds how many rendered dictionaries are kept: the shared
+// one and those with users' personal words added.
+const maxRendered = 256
+
+// Dictionary is a generated .dic/.aff pair. Each file has its own ETag: the
+// .dic changes with the lexicon and the words added to it, while the .aff
+// only changes with the affix rules.
+type Dictionary struct {
+	Dic         []byte
+	Aff         []byte
+	DicETag     string
+	AffETag     string
+	GeneratedAt time.Time
+	WordCount   int
+}
+
+// Generator builds dictionaries from verified, approved tamil_words and keeps
+// the rendered files until the lexicon changes.
+type Generator struct {
+	db *gorm.DB
+
+	mu          sync.Mutex
+	base        []lexicon.Entry
+	fingerprint string
+	generatedAt time.Time
+	checkedAt   time.Time
+	// rendered holds dictionaries built from the lexicon with fingerprint
+	// renderedFor, keyed by their .dic ETag.
+	rendered    map[string]*Dictionary
+	renderedFor string
+}
+
+func NewGenerator(db *gorm.DB) *Generator {
+	return &Generator{db: db}
+}
+
+// Invalidate forces the next Build to reload the lexicon, e.g. after a
+// reviewer approves a word.
+func (g *Generator) Invalidate() {
+	g.mu.Lock()
+	g.checkedAt = time.Time{}
+	g.fingerprint = ""
+	g.rendered = nil
+	g.mu.Unlock()
+}
+
+// Build returns the dictionary, adding extra words (e.g. a user's personal
+// dictionary) on top of the shared lexicon. Rendered files are reused while
+// the lexicon and the extra words stay the same.
+func (g *Generator) Build(extra []string) (*Dictionary, error) {
+	base, fingerprint, generatedAt, err := g.current()
+	if err != nil {
+		return nil, err
+	}
+	key := etag(fingerprint, extra)
+	if dict := g.cached(fingerprint, key); dict != nil {
+		return dict, nil
+	}
+
+	entries := base
+	if len(extra) > 0 {
+		entries = make([]lexicon.Entry, 0, len(base)+len(extra))
+		entries = append(entries, base...)
+		for _, word := range extra {
+			if word = strings.TrimSpace(word); word != "" {
+				entries = append(entries, lexicon.Entry{Tamil: word})
+			}
+		}
+	}
+
+	var dic bytes.Buffer
+	var aff bytes.Buffer
+	if err := lexicon.WriteHunspell(&dic, &aff, entries, AffixRules, FlagsFor); err != nil {
+		return nil, err
+	}
+
+	dict := &Dictionary{
+		Dic:         dic.Bytes(),
+		Aff:         aff.Bytes(),
+		DicETag:     key,
+		AffETag:     affETag,
+		GeneratedAt: generatedAt,
+		WordCount:   len(entries),
+	}
+	g.store(fingerprint, key, dict)
+	return dict, nil
+}
+
+func (g *Generator) cached(fingerprint, key string) *Dictionary {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.renderedFor != fingerprint {
+		return nil
+	}
+	return g.rendered[key]
+}
+
+func (g *Generator) store(fingerprint, key string, dict *Dictionary) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	// The lexicon may have changed while this one was rendered
+	if fingerprint != g.fingerprint {
+		return
+	}
+	if g.rendered == nil || g.renderedFor != fingerprint || len(g.rendered) >= maxRendered {
+		g.rendered = make(map[string]*Dictionary)
+		g.renderedFor = fingerprint
+	}
+	g.rendered[key] = dict
+}
+
+func (g *Generator) current() ([]lexicon.Entry, string, time.Time, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.base != nil && time.Since(g.checkedAt) < recheckInterval {
+		return g.base, g.fingerprint, g.generatedAt, nil
+	}
+
+	fingerprint, err := g.lexiconFingerprint()
+	if err != nil {
+		return nil, "", time.Time{}, err
+	}
+	g.checkedAt = time.Now()
+	if g.base != nil && fingerprint == g.fingerprint {
+		return g.base, g.fingerprint, g.generatedAt, nil
+	}
+
+	entries, err := lexicon.Export(g.db, lexicon.Filter{VerifiedOnly: true})
+	if err != nil {
+		return nil, "", time.Time{}, err
+	}
+
+	g.base = entries
+	g.fingerprint = fingerprint
+	g.generatedAt = time.Now()
+	return g.base, g.fingerprint, g.generatedAt, nil
+}
+
+// lexiconFingerprint changes whenever a dictionary word is added, edited or
+// removed, so regeneration only happens when the output would differ.
+func (g *Generator) lexiconFingerprint() (string, error) {
+	var stats struct {
+		Total     int64
+		Deleted   int64
+		UpdatedAt *time.Time
+	}
+	err := g.db.Unscoped().Model(&models.TamilWord{}).
+		Select("COUNT(*) AS total, COUNT(deleted_at) AS deleted, MAX(updated_at) AS updated_at").
+		Where("is_verified = ? AND review_status = ?", true, models.WordApproved).
+		Scan(&stats).Error
+	if err != nil {
+		return "", err
+	}
+
+	updated := int64(0)
+	if stats.UpdatedAt != nil {
+		updated = stats.UpdatedAt.UnixNano()
+	}
+	return fmt.Sprintf("%d-%d-%d", stats.Total, stats.Deleted, updated), nil
+}
+
+func etag(fingerprint string, extra []string) string {
+	sum := sha256.New()
+	sum.Write([]byte(fingerprint))
+	for _, w := range extra {
+		sum.Write([]byte{0})
+		sum.Write([]byte(w))
+	}
+	return `"` + hex.EncodeToString(sum.Sum(nil))[:32] + `"`
+}
+
+// affETag identifies the .aff file, which is the affix rules verbatim.
+var affETag = etag("aff", []string{AffixRules})
@@ -0,0 +1,88 @@
+// Package lexicon converts tamil_words rows to and from interchange formats
+// and applies imported entries to the database.
+package lexicon
+
+import (
+	"fmt"
+	"strings"
+
+	"tamil-proofreading-platform/backend/internal/models"
+)
+
+// Entry is the format-neutral representation of a lexicon word.
+type Entry struct {
+	Tamil           string   `json:"tamil"`
+	Transliteration string   `json:"transliteration"`
+	Alternates      []string `json:"alternates,omitempty"`
+	Frequency       int      `json:"frequency"`
+	Category        string   `json:"category,omitempty"`
+	Meaning         string   `json:"meaning,omitempty"`
+	Example         string   `json:"example,omitempty"`
+	Verified        bool     `json:"is_verified"`
+	Source          string   `json:"source,omitempty"`
+}
+
+// Normalize trims fields and lowercases transliterations so entries compare
+// the same way the autocomplete index stores them.
+func (e *Entry) Normalize() {
+	e.Tamil = strings.TrimSpace(e.Tamil)
+	e.Transliteration = strings.ToLower(strings.TrimSpace(e.Transliteration))
+	e.Category = strings.TrimSpace(e.Category)
+	e.Meaning = strings.TrimSpace(e.Meaning)
+	e.Example = strings.TrimSpace(e.Example)
+	e.Source = strings.TrimSpace(e.Source)
+
+	word := models.TamilWord{Transliteration: e.Transliteration}
+	word.SetAlternates(e.Alternates)
+	e.Alternates = word.Alternates()
+}
+
+// Validate reports why an entry cannot be imported.
+func (e *Entry) Validate() error {
+	if e.Tamil == "" {
+		return fmt.Errorf("missing tamil text")
+	}
+	if e.Transliteration == "" {
+		return fmt.Errorf("missing transliteration for %q", e.Tamil)
+	}
+	if len(e.Tamil) > 255 || len(e.Transliteration) > 255 {
+		return fmt.Errorf("entry %q exceeds 255 bytes", e.Transliteration)
+	}
+	return nil
+}
+
+// FromModel converts a stored word into an Entry.
+func FromModel(w models.TamilWord) Entry {
+	return Entry{
+		Tamil:           w.TamilText,
+		Transliteration: w.Transliteration,
+		Alternates:      w.Alternates(),
+		Frequency:       w.Frequency,
+		Category:        string(w.Category),
+		Meaning:         w.Meaning,
+		Example:         w.Example,
+		Verified:        w.IsVerified,
+		Source:          w.Source,
+	}
+}
+
+// ToModel converts an Entry into a new, unsaved word.
+func (e Entry) ToModel() models.TamilWord {
+	category := models.WordCategory(e.Category)
+	if category == "" {
+		category = models.CategoryCommon
+	}
+
+	word := models.TamilWord{
+		TamilText:       e.Tamil,
+		Transliteration: e.Transliteration,
+		Frequency:       e.Frequency,
+		Category:        category,
+		Meaning:         e.Meaning,
+		Example:         e.Example,
+		IsVerified:      e.Verified,
+		Source:          e.Source,
+	}
+	word.SetAlternates(e.Alternates)
+	return word
+}
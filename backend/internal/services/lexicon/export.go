@@ -0,0 +1,42 @@
+package lexicon
+
+import (
+	"tamil-proofreading-platform/backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// Filter narrows an export.
+type Filter struct {
+	VerifiedOnly bool
+	Source       string
+	Category     string
+}
+
+// Export loads approved tamil_words matching the filter, most frequent first.
+// Pending and rejected contributions are never exported.
+func Export(db *gorm.DB, filter Filter) ([]Entry, error) {
+	query := db.Model(&models.TamilWord{}).
+		Where("review_status = ?", models.WordApproved).
+		Order("frequency DESC, transliteration ASC")
+	if filter.VerifiedOnly {
+		query = query.Where("is_verified = ?", true)
+	}
+	if filter.Source != "" {
+		query = query.Where("source = ?", filter.Source)
+	}
+	if filter.Category != "" {
+		query = query.Where("category = ?", filter.Category)
+	}
+
+	var words []models.TamilWord
+	if err := query.Find(&words).Error; err != nil {
+		return nil, err
+	}
+
+	entries := make([]Entry, 0, len(words))
+	for _, w := range words {
+		entries = append(entries, FromModel(w))
+	}
+	return entries, nil
+}
@@ -0,0 +1,325 @@
+package lexicon
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+type Format string
+
+const (
+	FormatJSON     Format = "json"
+	FormatCSV      Format = "csv"
+	FormatTSV      Format = "tsv"
+	FormatHunspell Format = "hunspell"
+)
+
+// tabularColumns is the column order used for CSV/TSV export. Import matches
+// columns by header name so files may omit or reorder them.
+var tabularColumns = []string{
+	"tamil", "transliteration", "alternates", "frequency", "category",
+	"meaning", "example", "is_verified", "source",
+}
+
+// ParseFormat accepts a format name or infers it from a file extension.
+func ParseFormat(name, path string) (Format, error) {
+	if name == "" {
+		name = strings.TrimPrefix(strings.ToLower(filepath.Ext(path)), ".")
+	}
+	switch strings.ToLower(name) {
+	case "json":
+		return FormatJSON, nil
+	case "csv":
+		return FormatCSV, nil
+	case "tsv", "tab":
+		return FormatTSV, nil
+	case "hunspell", "dic":
+		return FormatHunspell, nil
+	}
+	return "", fmt.Errorf("unsupported format %q (expected json, csv, tsv or hunspell)", name)
+}
+
+// Read decodes entries in the given format. Hunspell input is the .dic file;
+// the .aff file carries no word data and is not needed for import.
+func Read(format Format, r io.Reader) ([]Entry, error) {
+	switch format {
+	case FormatJSON:
+		return readJSON(r)
+	case FormatCSV:
+		return readTabular(r, ',')
+	case FormatTSV:
+		return readTabular(r, '\t')
+	case FormatHunspell:
+		return readDic(r)
+	}
+	return nil, fmt.Errorf("unsupported format %q", format)
+}
+
+// Write encodes entries in a single-file format. Use WriteHunspell for .dic/.aff.
+func Write(format Format, w io.Writer, entries []Entry) error {
+	switch format {
+	case FormatJSON:
+		enc := json.NewEncoder(w)
+		enc.SetEscapeHTML(false)
+		enc.SetIndent("", "  ")
+		return enc.Encode(entries)
+	case FormatCSV:
+		return writeTabular(w, ',', entries)
+	case FormatTSV:
+		return writeTabular(w, '\t', entries)
+	}
+	return fmt.Errorf("format %q cannot be written to a single stream", format)
+}
+
+// jsonEntry accepts both the lexicon field names and the compact tam/eng/freq
+// keys used by data/tamil_lexicon.json.
+type jsonEntry struct {
+	Entry
+	Tam  string `json:"tam"`
+	Eng  string `json:"eng"`
+	Freq int    `json:"freq"`
+}
+
+func readJSON(r io.Reader) ([]Entry, error) {
+	var raw []jsonEntry
+	if err := json.NewDecoder(r).Decode(&raw); err != nil {
+		return nil, fmt.Errorf("decode json: %w", err)
+	}
+
+	entries := make([]Entry, 0, len(raw))
+	for _, item := range raw {
+		entry := item.Entry
+		if entry.Tamil == "" {
+			entry.Tamil = item.Tam
+		}
+		if entry.Transliteration == "" {
+			entry.Transliteration = item.Eng
+		}
+		if entry.Frequency == 0 {
+			entry.Frequency = item.Freq
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+func readTabular(r io.Reader, sep rune) ([]Entry, error) {
+	reader := csv.NewReader(r)
+	reader.Comma = sep
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	if sep == '\t' {
+		reader.LazyQuotes = true
+	}
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("read header: %w", err)
+	}
+	index := make(map[string]int, len(header))
+	for i, name := range header {
+		index[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))] = i
+	}
+	if _, ok := index["tamil"]; !ok {
+		return nil, fmt.Errorf("header must include a tamil column")
+	}
+	if _, ok := index["transliteration"]; !ok {
+		return nil, fmt.Errorf("header must include a transliteration column")
+	}
+
+	field := func(record []string, name string) string {
+		if i, ok := index[name]; ok && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	var entries []Entry
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+
+		entry := Entry{
+			Tamil:           field(record, "tamil"),
+			Transliteration: field(record, "transliteration"),
+			Category:        field(record, "category"),
+			Meaning:         field(record, "meaning"),
+			Example:         field(record, "example"),
+			Source:          field(record, "source"),
+		}
+		if alternates := field(record, "alternates"); alternates != "" {
+			entry.Alternates = strings.Split(alternates, "|")
+		}
+		if freq := field(record, "frequency"); freq != "" {
+			if entry.Frequency, err = strconv.Atoi(freq); err != nil {
+				return nil, fmt.Errorf("line %d: invalid frequency %q", line, freq)
+			}
+		}
+		if verified := field(record, "is_verified"); verified != "" {
+			entry.Verified, _ = strconv.ParseBool(verified)
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+func writeTabular(w io.Writer, sep rune, entries []Entry) error {
+	writer := csv.NewWriter(w)
+	writer.Comma = sep
+	if err := writer.Write(tabularColumns); err != nil {
+		return err
+	}
+	for _, e := range entries {
+		record := []string{
+			e.Tamil,
+			e.Transliteration,
+			strings.Join(e.Alternates, "|"),
+			strconv.Itoa(e.Frequency),
+			e.Category,
+			e.Meaning,
+			e.Example,
+			strconv.FormatBool(e.Verified),
+			e.Source,
+		}
+		if sep == '\t' {
+			for i := range record {
+				record[i] = strings.NewReplacer("\t", " ", "\n", " ").Replace(record[i])
+			}
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// readDic parses a Hunspell .dic file. Each line is `word[/flags]` optionally
+// followed by morphological fields; ph: fields carry the transliteration
+// (first) and alternates, po: the category and fr: the frequency.
+func readDic(r io.Reader) ([]Entry, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	var entries []Entry
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		// First line is the approximate word count.
+		if line == 1 {
+			if _, err := strconv.Atoi(text); err == nil {
+				continue
+			}
+		}
+
+		fields := strings.Fields(text)
+		word := fields[0]
+		if slash := strings.Index(word, "/"); slash >= 0 {
+			word = word[:slash]
+		}
+
+		entry := Entry{Tamil: word, Verified: true}
+		for _, f := range fields[1:] {
+			key, value, ok := strings.Cut(f, ":")
+			if !ok {
+				continue
+			}
+			switch key {
+			case "ph":
+				if entry.Transliteration == "" {
+					entry.Transliteration = value
+				} else {
+					entry.Alternates = append(entry.Alternates, value)
+				}
+			case "po":
+				entry.Category = value
+			case "fr":
+				entry.Frequency, _ = strconv.Atoi(value)
+			}
+		}
+		entries = append(entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// WriteHunspell writes a .dic/.aff pair. flagsFor may return affix flags for
+// an entry (without the leading slash); aff is written verbatim from affRules.
+func WriteHunspell(dic, aff io.Writer, entries []Entry, affRules string, flagsFor func(Entry) string) error {
+	if _, err := io.WriteString(aff, affRules); err != nil {
+		return err
+	}
+
+	// Hunspell keys on the surface form, so rows sharing Tamil text collapse
+	// into one line with every transliteration as a ph: field.
+	order := make([]string, 0, len(entries))
+	grouped := make(map[string]*Entry, len(entries))
+	for _, e := range entries {
+		if e.Tamil == "" || strings.ContainsAny(e.Tamil, " \t/") {
+			continue
+		}
+		if first, ok := grouped[e.Tamil]; ok {
+			first.Alternates = append(first.Alternates, e.Transliteration)
+			first.Alternates = append(first.Alternates, e.Alternates...)
+			continue
+		}
+		copied := e
+		copied.Alternates = append([]string(nil), e.Alternates...)
+		grouped[e.Tamil] = &copied
+		order = append(order, e.Tamil)
+	}
+
+	lines := make([]string, 0, len(order))
+	for _, tamil := range order {
+		e := *grouped[tamil]
+		e.Normalize()
+
+		var b strings.Builder
+		b.WriteString(e.Tamil)
+		if flagsFor != nil {
+			if flags := flagsFor(e); flags != "" {
+				b.WriteString("/" + flags)
+			}
+		}
+		if e.Transliteration != "" {
+			b.WriteString("\tph:" + e.Transliteration)
+			for _, alt := range e.Alternates {
+				b.WriteString(" ph:" + alt)
+			}
+		}
+		if e.Category != "" {
+			b.WriteString(" po:" + e.Category)
+		}
+		if e.Frequency > 0 {
+			b.WriteString(" fr:" + strconv.Itoa(e.Frequency))
+		}
+		lines = append(lines, b.String())
+	}
+
+	if _, err := fmt.Fprintf(dic, "%d\n", len(lines)); err != nil {
+		return err
+	}
+	for _, l := range lines {
+		if _, err := io.WriteString(dic, l+"\n"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,309 @@
+package lexicon
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"tamil-proofreading-platform/backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type Action string
+
+const (
+	ActionCreate    Action = "create"
+	ActionUpdate    Action = "update"
+	ActionMerge     Action = "merge_alternate"
+	ActionUnchanged Action = "unchanged"
+	ActionSkip      Action = "skip"
+)
+
+// FieldChange describes one column an import would modify.
+type FieldChange struct {
+	Field string `json:"field"`
+	From  string `json:"from"`
+	To    string `json:"to"`
+}
+
+// Change is the planned effect of importing one entry.
+type Change struct {
+	Action  Action        `json:"action"`
+	Entry   Entry         `json:"entry"`
+	WordID  uint          `json:"word_id,omitempty"`
+	Fields  []FieldChange `json:"fields,omitempty"`
+	Reason  string        `json:"reason,omitempty"`
+	updates map[string]interface{}
+}
+
+func (c Change) String() string {
+	switch c.Action {
+	case ActionCreate:
+		return fmt.Sprintf("+ %s → %s", c.Entry.Transliteration, c.Entry.Tamil)
+	case ActionSkip:
+		return fmt.Sprintf("! %s: %s", c.Entry.Transliteration, c.Reason)
+	case ActionUnchanged:
+		return fmt.Sprintf("= %s", c.Entry.Transliteration)
+	}
+
+	parts := make([]string, 0, len(c.Fields))
+	for _, f := range c.Fields {
+		parts = append(parts, fmt.Sprintf("%s: %q → %q", f.Field, f.From, f.To))
+	}
+	prefix := "~"
+	if c.Action == ActionMerge {
+		prefix = "&"
+	}
+	return fmt.Sprintf("%s %s (#%d) %s", prefix, c.Entry.Transliteration, c.WordID, strings.Join(parts, ", "))
+}
+
+// Options controls how imported entries are tagged and merged.
+type Options struct {
+	// Source tags every imported row; entries keep their own source when empty.
+	Source string
+	// MarkVerified forces is_verified on created and updated rows.
+	MarkVerified bool
+}
+
+// Summary counts planned changes by action.
+type Summary map[Action]int
+
+func Summarize(changes []Change) Summary {
+	summary := Summary{}
+	for _, c := range changes {
+		summary[c.Action]++
+	}
+	return summary
+}
+
+type Importer struct {
+	db *gorm.DB
+}
+
+func NewImporter(db *gorm.DB) *Importer {
+	return &Importer{db: db}
+}
+
+const lookupBatchSize = 500
+
+// Plan compares entries with tamil_words and returns the changes an import
+// would make, upserting by transliteration; a soft-deleted row with the same
+// transliteration is updated and restored. Entries whose transliteration is
+// already an alternate spelling of the same Tamil word are merged instead of
+// duplicated, and alternates that are another word's primary key are dropped.
+func (im *Importer) Plan(entries []Entry, opts Options) ([]Change, error) {
+	for i := range entries {
+		if opts.Source != "" {
+			entries[i].Source = opts.Source
+		}
+		if opts.MarkVerified {
+			entries[i].Verified = true
+		}
+		entries[i].Normalize()
+	}
+
+	byTranslit, byTamil, err := im.loadExisting(entries)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := make([]Change, 0, len(entries))
+	planned := make(map[string]int, len(entries))
+	for _, entry := range entries {
+		if err := entry.Validate(); err != nil {
+			changes = append(changes, Change{Action: ActionSkip, Entry: entry, Reason: err.Error()})
+			continue
+		}
+
+		entry.Alternates = dropForeignKeys(entry, byTranslit)
+
+		if idx, ok := planned[entry.Transliteration]; ok {
+			changes = append(changes, Change{
+				Action: ActionSkip,
+				Entry:  entry,
+				Reason: fmt.Sprintf("duplicate of entry %d in this import", idx+1),
+			})
+			continue
+		}
+		planned[entry.Transliteration] = len(changes)
+
+		if existing, ok := byTranslit[entry.Transliteration]; ok {
+			changes = append(changes, planUpdate(existing, entry))
+			continue
+		}
+
+		if owner := findAlternateOwner(byTamil[entry.Tamil], entry.Transliteration); owner != nil {
+			change := planMerge(*owner, entry)
+			if merged, ok := change.updates["alternate_spellings"].(string); ok {
+				// Later entries for the same word must see this merge.
+				owner.AlternateSpellings = merged
+			}
+			changes = append(changes, change)
+			continue
+		}
+
+		changes = append(changes, Change{Action: ActionCreate, Entry: entry})
+	}
+
+	return changes, nil
+}
+
+// Apply writes planned changes in a single transaction.
+func (im *Importer) Apply(changes []Change) error {
+	return im.db.Transaction(func(tx *gorm.DB) error {
+		for _, c := range changes {
+			switch c.Action {
+			case ActionCreate:
+				word := c.Entry.ToModel()
+				if err := tx.Create(&word).Error; err != nil {
+					return fmt.Errorf("create %s: %w", c.Entry.Transliteration, err)
+				}
+			case ActionUpdate, ActionMerge:
+				// Unscoped so updates that restore a soft-deleted row match it
+				if err := tx.Unscoped().Model(&models.TamilWord{}).Where("id = ?", c.WordID).Updates(c.updates).Error; err != nil {
+					return fmt.Errorf("update %s: %w", c.Entry.Transliteration, err)
+				}
+			}
+		}
+		return nil
+	})
+}
+
+func (im *Importer) loadExisting(entries []Entry) (map[string]models.TamilWord, map[string][]models.TamilWord, error) {
+	translits := make([]string, 0, len(entries)*2)
+	tamils := make([]string, 0, len(entries))
+	for _, e := range entries {
+		translits = append(translits, e.Transliteration)
+		translits = append(translits, e.Alternates...)
+		tamils = append(tamils, e.Tamil)
+	}
+
+	// Soft-deleted rows still hold their transliteration in the unique
+	// index, so they are updated and restored rather than created again
+	byTranslit := make(map[string]models.TamilWord)
+	for _, batch := range chunk(translits) {
+		var words []models.TamilWord
+		if err := im.db.Unscoped().Where("transliteration IN ?", batch).Find(&words).Error; err != nil {
+			return nil, nil, err
+		}
+		for _, w := range words {
+			byTranslit[w.Transliteration] = w
+		}
+	}
+
+	byTamil := make(map[string][]models.TamilWord)
+	for _, batch := range chunk(tamils) {
+		var words []models.TamilWord
+		if err := im.db.Where("tamil_text IN ?", batch).Find(&words).Error; err != nil {
+			return nil, nil, err
+		}
+		for _, w := range words {
+			byTamil[w.TamilText] = append(byTamil[w.TamilText], w)
+		}
+	}
+
+	return byTranslit, byTamil, nil
+}
+
+func chunk(values []string) [][]string {
+	var batches [][]string
+	for start := 0; start < len(values); start += lookupBatchSize {
+		end := start + lookupBatchSize
+		if end > len(values) {
+			end = len(values)
+		}
+		batches = append(batches, values[start:end])
+	}
+	return batches
+}
+
+// dropForeignKeys removes alternates that are the primary transliteration of
+// a different Tamil word; keeping them would make autocomplete ambiguous.
+func dropForeignKeys(entry Entry, byTranslit map[string]models.TamilWord) []string {
+	kept := entry.Alternates[:0]
+	for _, alt := range entry.Alternates {
+		if w, ok := byTranslit[alt]; ok && !w.DeletedAt.Valid && w.TamilText != entry.Tamil {
+			continue
+		}
+		kept = append(kept, alt)
+	}
+	return kept
+}
+
+// findAlternateOwner picks the existing row an unknown transliteration should
+// be merged into: the one already listing it as an alternate, otherwise the
+// first row with the same Tamil text.
+func findAlternateOwner(candidates []models.TamilWord, translit string) *models.TamilWord {
+	for i := range candidates {
+		for _, alt := range candidates[i].Alternates() {
+			if alt == translit {
+				return &candidates[i]
+			}
+		}
+	}
+	if len(candidates) > 0 {
+		return &candidates[0]
+	}
+	return nil
+}
+
+func planUpdate(existing models.TamilWord, entry Entry) Change {
+	change := Change{Action: ActionUpdate, Entry: entry, WordID: existing.ID, updates: map[string]interface{}{}}
+
+	set := func(column, from, to string, value interface{}) {
+		if to == "" || from == to {
+			return
+		}
+		change.Fields = append(change.Fields, FieldChange{Field: column, From: from, To: to})
+		change.updates[column] = value
+	}
+
+	set("tamil_text", existing.TamilText, entry.Tamil, entry.Tamil)
+	set("category", string(existing.Category), entry.Category, entry.Category)
+	set("meaning", existing.Meaning, entry.Meaning, entry.Meaning)
+	set("example", existing.Example, entry.Example, entry.Example)
+	set("source", existing.Source, entry.Source, entry.Source)
+	if entry.Frequency > 0 {
+		set("frequency", strconv.Itoa(existing.Frequency), strconv.Itoa(entry.Frequency), entry.Frequency)
+	}
+	if entry.Verified && !existing.IsVerified {
+		set("is_verified", "false", "true", true)
+	}
+	mergeAlternates(&change, existing, entry.Alternates)
+	if existing.DeletedAt.Valid {
+		change.Fields = append(change.Fields, FieldChange{Field: "deleted_at", From: existing.DeletedAt.Time.Format(time.RFC3339), To: ""})
+		change.updates["deleted_at"] = nil
+	}
+
+	if len(change.Fields) == 0 {
+		change.Action = ActionUnchanged
+	}
+	return change
+}
+
+func planMerge(owner models.TamilWord, entry Entry) Change {
+	change := Change{Action: ActionMerge, Entry: entry, WordID: owner.ID, updates: map[string]interface{}{}}
+	mergeAlternates(&change, owner, append([]string{entry.Transliteration}, entry.Alternates...))
+	if len(change.Fields) == 0 {
+		change.Action = ActionUnchanged
+		change.Reason = fmt.Sprintf("already an alternate of %s", owner.Transliteration)
+	}
+	return change
+}
+
+func mergeAlternates(change *Change, existing models.TamilWord, incoming []string) {
+	current := existing.Alternates()
+	merged := models.TamilWord{Transliteration: existing.Transliteration}
+	merged.SetAlternates(append(append([]string{}, current...), incoming...))
+	if len(merged.Alternates()) == len(current) {
+		return
+	}
+	change.Fields = append(change.Fields, FieldChange{
+		Field: "alternate_spellings",
+		From:  strings.Join(current, "|"),
+		To:    strings.Join(merged.Alternates(), "|"),
+	})
+	change.updates["alternate_spellings"] = merged.AlternateSpellings
+}
@@ -0,0 +1,575 @@
+// Package library organizes a user's submissions with folders, tags, titles
+// and custom metadata, and filters submission lists by them.
+package library
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"tamil-proofreading-platform/backend/internal/models"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+)
+
+const (
+	MaxFolders           = 200
+	MaxTags              = 500
+	MaxTagsPerSubmission = 20
+	MaxMetadataKeys      = 20
+	MaxBulk              = 500
+
+	maxFolderName    = 120
+	maxTagName       = 40
+	maxTitle         = 255
+	maxMetadataValue = 500
+)
+
+var (
+	ErrFolderNotFound   = errors.New("folder not found")
+	ErrTagNotFound      = errors.New("tag not found")
+	ErrNameTaken        = errors.New("name is already in use")
+	ErrInvalidName      = errors.New("invalid name")
+	ErrInvalidColor     = errors.New("color must be #rrggbb")
+	ErrInvalidTitle     = fmt.Errorf("title must be at most %d characters", maxTitle)
+	ErrInvalidMetadata  = fmt.Errorf("metadata may have %d keys of 1-40 letters, digits, '_', '-' or '.', with values of at most %d characters", MaxMetadataKeys, maxMetadataValue)
+	ErrTooManyFolders   = fmt.Errorf("at most %d folders", MaxFolders)
+	ErrTooManyTags      = fmt.Errorf("at most %d tags", MaxTags)
+	ErrTooManyOnOne     = fmt.Errorf("a submission may have at most %d tags", MaxTagsPerSubmission)
+	ErrTooManySubmitted = fmt.Errorf("at most %d submissions at once", MaxBulk)
+)
+
+var (
+	metadataKeyPattern = regexp.MustCompile(`^[A-Za-z0-9_.-]{1,40}$`)
+	colorPattern       = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+)
+
+// Migrate creates the indexes that keep folder and tag names unique per
+// user, ignoring case, replacing the case-sensitive ones of earlier
+// versions. It is safe to run on every start.
+func Migrate(db *gorm.DB) error {
+	for _, stmt := range []string{
+		"DROP INDEX IF EXISTS idx_folders_user_name",
+		"DROP INDEX IF EXISTS idx_tags_user_name",
+		"CREATE UNIQUE INDEX IF NOT EXISTS idx_folders_user_lower_name ON folders (user_id, LOWER(name))",
+		"CREATE UNIQUE INDEX IF NOT EXISTS idx_tags_user_lower_name ON tags (user_id, LOWER(name))",
+	} {
+		if err := db.Exec(stmt).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type LibraryService struct {
+	db *gorm.DB
+}
+
+func NewLibraryService(db *gorm.DB) *LibraryService {
+	return &LibraryService{db: db}
+}
+
+// FolderSummary is a folder with how many unarchived submissions it holds.
+type FolderSummary struct {
+	models.Folder
+	Submissions int64 `json:"submissions"`
+}
+
+// TagSummary is a tag with how many unarchived submissions carry it.
+type TagSummary struct {
+	models.Tag
+	Submissions int64 `json:"submissions"`
+}
+
+// Folders lists the user's folders by name.
+func (s *LibraryService) Folders(userID uint) ([]FolderSummary, error) {
+	var folders []FolderSummary
+	err := s.db.Model(&models.Folder{}).
+		Select("folders.*, (SELECT COUNT(*) FROM submissions WHERE submissions.folder_id = folders.id AND submissions.archived = false AND submissions.deleted_at IS NULL) AS submissions").
+		Where("user_id = ?", userID).
+		Order("LOWER(name) ASC").
+		Find(&folders).Error
+	return folders, err
+}
+
+// Folder returns one of the user's folders.
+func (s *LibraryService) Folder(userID, folderID uint) (*models.Folder, error) {
+	var folder models.Folder
+	err := s.db.Where("id = ? AND user_id = ?", folderID, userID).First(&folder).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrFolderNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &folder, nil
+}
+
+// CreateFolder creates a folder.
+func (s *LibraryService) CreateFolder(userID uint, name string) (*models.Folder, error) {
+	name, err := cleanName(name, maxFolderName)
+	if err != nil {
+		return nil, err
+	}
+	var count int64
+	if err := s.db.Model(&models.Folder{}).Where("user_id = ?", userID).Count(&count).Error; err != nil {
+		return nil, err
+	}
+	if count >= MaxFolders {
+		return nil, ErrTooManyFolders
+	}
+
+	folder := &models.Folder{UserID: userID, Name: name}
+	if err := s.db.Create(folder).Error; err != nil {
+		return nil, nameError(err)
+	}
+	return folder, nil
+}
+
+// RenameFolder renames one of the user's folders.
+func (s *LibraryService) RenameFolder(userID, folderID uint, name string) (*models.Folder, error) {
+	folder, err := s.Folder(userID, folderID)
+	if err != nil {
+		return nil, err
+	}
+	if name, err = cleanName(name, maxFolderName); err != nil {
+		return nil, err
+	}
+	if err := s.db.Model(folder).Update("name", name).Error; err != nil {
+		return nil, nameError(err)
+	}
+	return folder, nil
+}
+
+// DeleteFolder deletes one of the user's folders, leaving its submissions
+// unfiled.
+func (s *LibraryService) DeleteFolder(userID, folderID uint) error {
+	folder, err := s.Folder(userID, folderID)
+	if err != nil {
+		return err
+	}
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.Submission{}).Where("folder_id = ?", folder.ID).Update("folder_id", nil).Error; err != nil {
+			return err
+		}
+		return tx.Delete(folder).Error
+	})
+}
+
+// Tags lists the user's tags by name.
+func (s *LibraryService) Tags(userID uint) ([]TagSummary, error) {
+	var tags []TagSummary
+	err := s.db.Model(&models.Tag{}).
+		Select("tags.*, (SELECT COUNT(*) FROM submission_tags st JOIN submissions ON submissions.id = st.submission_id WHERE st.tag_id = tags.id AND submissions.archived = false AND submissions.deleted_at IS NULL) AS submissions").
+		Where("user_id = ?", userID).
+		Order("LOWER(name) ASC").
+		Find(&tags).Error
+	return tags, err
+}
+
+// Tag returns one of the user's tags.
+func (s *LibraryService) Tag(userID, tagID uint) (*models.Tag, error) {
+	var tag models.Tag
+	err := s.db.Where("id = ? AND user_id = ?", tagID, userID).First(&tag).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrTagNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &tag, nil
+}
+
+// CreateTag creates a tag.
+func (s *LibraryService) CreateTag(userID uint, name, color string) (*models.Tag, error) {
+	name, err := cleanName(name, maxTagName)
+	if err != nil {
+		return nil, err
+	}
+	if color != "" && !colorPattern.MatchString(color) {
+		return nil, ErrInvalidColor
+	}
+	var count int64
+	if err := s.db.Model(&models.Tag{}).Where("user_id = ?", userID).Count(&count).Error; err != nil {
+		return nil, err
+	}
+	if count >= MaxTags {
+		return nil, ErrTooManyTags
+	}
+
+	tag := &models.Tag{UserID: userID, Name: name, Color: strings.ToLower(color)}
+	if err := s.db.Create(tag).Error; err != nil {
+		return nil, nameError(err)
+	}
+	return tag, nil
+}
+
+// UpdateTag renames or recolors one of the user's tags. Nil fields are left
+// as they are; an empty color clears it.
+func (s *LibraryService) UpdateTag(userID, tagID uint, name, color *string) (*models.Tag, error) {
+	tag, err := s.Tag(userID, tagID)
+	if err != nil {
+		return nil, err
+	}
+	updates := map[string]interface{}{}
+	if name != nil {
+		cleaned, err := cleanName(*name, maxTagName)
+		if err != nil {
+			return nil, err
+		}
+		updates["name"] = cleaned
+	}
+	if color != nil {
+		if *color != "" && !colorPattern.MatchString(*color) {
+			return nil, ErrInvalidColor
+		}
+		updates["color"] = strings.ToLower(*color)
+	}
+	if len(updates) > 0 {
+		if err := s.db.Model(tag).Updates(updates).Error; err != nil {
+			return nil, nameError(err)
+		}
+	}
+	return tag, nil
+}
+
+// DeleteTag deletes one of the user's tags and removes it from every
+// submission.
+func (s *LibraryService) DeleteTag(userID, tagID uint) error {
+	tag, err := s.Tag(userID, tagID)
+	if err != nil {
+		return err
+	}
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("DELETE FROM submission_tags WHERE tag_id = ?", tag.ID).Error; err != nil {
+			return err
+		}
+		return tx.Delete(tag).Error
+	})
+}
+
+// Changes are edits to one submission. Nil fields are left as they are.
+type Changes struct {
+	Title *string
+	// FolderID 0 takes the submission out of its folder.
+	FolderID *uint
+	// Metadata replaces all of the submission's metadata.
+	Metadata *models.Metadata
+	// Tags replaces the submission's tags, by name; missing tags are
+	// created.
+	Tags *[]string
+}
+
+// Update applies changes to one of the user's submissions.
+func (s *LibraryService) Update(userID uint, submission *models.Submission, changes Changes) error {
+	updates := map[string]interface{}{}
+	if changes.Title != nil {
+		title := strings.TrimSpace(*changes.Title)
+		if utf8.RuneCountInString(title) > maxTitle {
+			return ErrInvalidTitle
+		}
+		updates["title"] = title
+	}
+	if changes.FolderID != nil {
+		if *changes.FolderID == 0 {
+			updates["folder_id"] = nil
+		} else {
+			if _, err := s.Folder(userID, *changes.FolderID); err != nil {
+				return err
+			}
+			updates["folder_id"] = *changes.FolderID
+		}
+	}
+	if changes.Metadata != nil {
+		if err := validateMetadata(*changes.Metadata); err != nil {
+			return err
+		}
+		raw, err := json.Marshal(*changes.Metadata)
+		if err != nil {
+			return err
+		}
+		updates["metadata"] = string(raw)
+	}
+	if changes.Tags != nil && len(*changes.Tags) > MaxTagsPerSubmission {
+		return ErrTooManyOnOne
+	}
+
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if len(updates) > 0 {
+			if err := tx.Model(&models.Submission{}).Where("id = ?", submission.ID).Updates(updates).Error; err != nil {
+				return err
+			}
+		}
+		if changes.Tags == nil {
+			return nil
+		}
+		tags, err := s.resolveTags(tx, userID, *changes.Tags)
+		if err != nil {
+			return err
+		}
+		if err := tx.Exec("DELETE FROM submission_tags WHERE submission_id = ?", submission.ID).Error; err != nil {
+			return err
+		}
+		return addTags(tx, []uint{submission.ID}, tags)
+	})
+}
+
+// BulkResult reports a bulk change. NotFound lists the requested IDs that
+// are not the user's submissions; they are skipped.
+type BulkResult struct {
+	Updated  []uint `json:"updated"`
+	NotFound []uint `json:"not_found"`
+}
+
+// Move files the user's submissions in a folder, or takes them out of any
+// folder when folderID is 0.
+func (s *LibraryService) Move(userID uint, submissionIDs []uint, folderID uint) (*BulkResult, error) {
+	result, err := s.owned(userID, submissionIDs)
+	if err != nil || len(result.Updated) == 0 {
+		return result, err
+	}
+	var folder interface{}
+	if folderID != 0 {
+		if _, err := s.Folder(userID, folderID); err != nil {
+			return nil, err
+		}
+		folder = folderID
+	}
+	if err := s.db.Model(&models.Submission{}).Where("id IN ?", result.Updated).Update("folder_id", folder).Error; err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Retag adds and removes tags, by name, on the user's submissions. Tags
+// added that do not exist are created.
+func (s *LibraryService) Retag(userID uint, submissionIDs []uint, add, remove []string) (*BulkResult, error) {
+	result, err := s.owned(userID, submissionIDs)
+	if err != nil || len(result.Updated) == 0 {
+		return result, err
+	}
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if len(remove) > 0 {
+			if err := tx.Exec(`DELETE FROM submission_tags WHERE submission_id IN ?
+				AND tag_id IN (SELECT id FROM tags WHERE user_id = ? AND LOWER(name) IN ?)`,
+				result.Updated, userID, lowerAll(remove)).Error; err != nil {
+				return err
+			}
+		}
+		if len(add) == 0 {
+			return nil
+		}
+		tags, err := s.resolveTags(tx, userID, add)
+		if err != nil {
+			return err
+		}
+		if err := addTags(tx, result.Updated, tags); err != nil {
+			return err
+		}
+		var over int64
+		if err := tx.Raw(`SELECT COUNT(*) FROM (SELECT submission_id FROM submission_tags WHERE submission_id IN ?
+			GROUP BY submission_id HAVING COUNT(*) > ?) AS over`, result.Updated, MaxTagsPerSubmission).Scan(&over).Error; err != nil {
+			return err
+		}
+		if over > 0 {
+			return ErrTooManyOnOne
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// owned splits submissionIDs into the user's submissions and the rest.
+func (s *LibraryService) owned(userID uint, submissionIDs []uint) (*BulkResult, error) {
+	if len(submissionIDs) > MaxBulk {
+		return nil, ErrTooManySubmitted
+	}
+	result := &BulkResult{Updated: []uint{}, NotFound: []uint{}}
+	if len(submissionIDs) == 0 {
+		return result, nil
+	}
+	if err := s.db.Model(&models.Submission{}).
+		Where("user_id = ? AND id IN ?", userID, submissionIDs).
+		Order("id ASC").
+		Pluck("id", &result.Updated).Error; err != nil {
+		return nil, err
+	}
+	found := make(map[uint]bool, len(result.Updated))
+	for _, id := range result.Updated {
+		found[id] = true
+	}
+	for _, id := range submissionIDs {
+		if !found[id] {
+			result.NotFound = append(result.NotFound, id)
+			found[id] = true
+		}
+	}
+	return result, nil
+}
+
+// resolveTags returns the user's tags with the given names, creating those
+// that do not exist yet.
+func (s *LibraryService) resolveTags(tx *gorm.DB, userID uint, names []string) ([]models.Tag, error) {
+	var tags []models.Tag
+	seen := make(map[string]bool)
+	for _, name := range names {
+		name, err := cleanName(name, maxTagName)
+		if err != nil {
+			return nil, err
+		}
+		if seen[strings.ToLower(name)] {
+			continue
+		}
+		seen[strings.ToLower(name)] = true
+
+		var tag models.Tag
+		err = tx.Where("user_id = ? AND LOWER(name) = ?", userID, strings.ToLower(name)).First(&tag).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			var count int64
+			if err := tx.Model(&models.Tag{}).Where("user_id = ?", userID).Count(&count).Error; err != nil {
+				return nil, err
+			}
+			if count >= MaxTags {
+				return nil, ErrTooManyTags
+			}
+			// A concurrent request may create the same tag; take
+			// whichever was stored first
+			now := time.Now()
+			err = tx.Exec(`INSERT INTO tags (user_id, name, created_at, updated_at) VALUES (?, ?, ?, ?)
+				ON CONFLICT (user_id, (LOWER(name))) DO NOTHING`, userID, name, now, now).Error
+			if err == nil {
+				err = tx.Where("user_id = ? AND LOWER(name) = ?", userID, strings.ToLower(name)).First(&tag).Error
+			}
+		}
+		if err != nil {
+			return nil, err
+		}
+		tags = append(tags, tag)
+	}
+	return tags, nil
+}
+
+func addTags(tx *gorm.DB, submissionIDs []uint, tags []models.Tag) error {
+	if len(submissionIDs) == 0 || len(tags) == 0 {
+		return nil
+	}
+	tagIDs := make([]uint, len(tags))
+	for i, tag := range tags {
+		tagIDs[i] = tag.ID
+	}
+	return tx.Exec(`INSERT INTO submission_tags (submission_id, tag_id)
+		SELECT s.id, t.id FROM submissions s CROSS JOIN tags t WHERE s.id IN ? AND t.id IN ?
+		ON CONFLICT DO NOTHING`, submissionIDs, tagIDs).Error
+}
+
+// nameError maps a violation of the unique name indexes to ErrNameTaken.
+func nameError(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+		return ErrNameTaken
+	}
+	return err
+}
+
+func cleanName(name string, maxLength int) (string, error) {
+	name = strings.Join(strings.Fields(name), " ")
+	if name == "" || utf8.RuneCountInString(name) > maxLength {
+		return "", fmt.Errorf("%w: names must be 1-%d characters", ErrInvalidName, maxLength)
+	}
+	return name, nil
+}
+
+func validateMetadata(metadata models.Metadata) error {
+	if len(metadata) > MaxMetadataKeys {
+		return ErrInvalidMetadata
+	}
+	for key, value := range metadata {
+		if !metadataKeyPattern.MatchString(key) || utf8.RuneCountInString(value) > maxMetadataValue {
+			return ErrInvalidMetadata
+		}
+	}
+	return nil
+}
+
+func lowerAll(names []string) []string {
+	lowered := make([]string, 0, len(names))
+	for _, name := range names {
+		lowered = append(lowered, strings.ToLower(strings.Join(strings.Fields(name), " ")))
+	}
+	return lowered
+}
+
+// Filter narrows a list of submissions. Zero values mean no filter.
+type Filter struct {
+	// FolderID 0 matches unfiled submissions.
+	FolderID *uint
+	// Tags are names the submissions must all carry.
+	Tags []string
+	// Metadata are pairs the submissions' metadata must all contain.
+	Metadata map[string]string
+}
+
+// Apply adds the filter's conditions to a query on the user's submissions.
+func (f Filter) Apply(query *gorm.DB, userID uint) *gorm.DB {
+	if f.FolderID != nil {
+		if *f.FolderID == 0 {
+			query = query.Where("submissions.folder_id IS NULL")
+		} else {
+			query = query.Where("submissions.folder_id = ?", *f.FolderID)
+		}
+	}
+	if len(f.Tags) > 0 {
+		names := lowerAll(f.Tags)
+		distinct := make(map[string]bool, len(names))
+		for _, name := range names {
+			distinct[name] = true
+		}
+		query = query.Where(`submissions.id IN (SELECT st.submission_id FROM submission_tags st
+			JOIN tags t ON t.id = st.tag_id WHERE t.user_id = ? AND LOWER(t.name) IN ?
+			GROUP BY st.submission_id HAVING COUNT(DISTINCT t.id) = ?)`, userID, names, len(distinct))
+	}
+	if len(f.Metadata) > 0 {
+		raw, _ := json.Marshal(f.Metadata)
+		query = query.Where("submissions.metadata @> ?::jsonb", string(raw))
+	}
+	return query
+}
+
+// AttachTags loads the tags of submissions read without them.
+func AttachTags(db *gorm.DB, submissions []*models.Submission) error {
+	if len(submissions) == 0 {
+		return nil
+	}
+	ids := make([]uint, len(submissions))
+	for i, submission := range submissions {
+		ids[i] = submission.ID
+	}
+	var rows []struct {
+		SubmissionID uint
+		models.Tag
+	}
+	if err := db.Table("tags").
+		Select("submission_tags.submission_id, tags.*").
+		Joins("JOIN submission_tags ON submission_tags.tag_id = tags.id").
+		Where("submission_tags.submission_id IN ?", ids).
+		Order("LOWER(tags.name) ASC").
+		Scan(&rows).Error; err != nil {
+		return err
+	}
+	byID := make(map[uint][]models.Tag)
+	for _, row := range rows {
+		byID[row.SubmissionID] = append(byID[row.SubmissionID], row.Tag)
+	}
+	for _, submission := range submissions {
+		submission.Tags = byID[submission.ID]
+	}
+	return nil
+}
@@ -0,0 +1,546 @@
+// Package live proofreads a document while it is being edited. The editor
+// keeps a session open and sends the paragraphs that changed, each under a
+// stable ID; only those are proofread. A paragraph edited again before its
+// turn is proofread once, at its latest text, so a fast typist never builds
+// a backlog. Results are cached by text across sessions, and each
+// paragraph's suggestions are reported as additions and removals against
+// what the editor was sent before.
+package live
+
+import (
+	"context"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"tamil-proofreading-platform/backend/internal/services/llm"
+)
+
+// Event types sent to the editor.
+const (
+	EventReady   = "ready"
+	EventAck     = "ack"
+	EventAdd     = "suggestions.add"
+	EventRemove  = "suggestions.remove"
+	EventChecked = "paragraph.checked"
+	EventError   = "error"
+	EventPong    = "pong"
+)
+
+// Error codes of error events.
+const (
+	CodeInvalidMessage    = "invalid_message"
+	CodeInvalidParagraph  = "invalid_paragraph"
+	CodeParagraphTooLong  = "paragraph_too_long"
+	CodeTooManyParagraphs = "too_many_paragraphs"
+	CodeQuotaExceeded     = "quota_exceeded"
+	CodeProofreadFailed   = "proofread_failed"
+	CodeSlowClient        = "slow_client"
+)
+
+// maxParagraphIDLength bounds the IDs editors give paragraphs.
+const maxParagraphIDLength = 64
+
+var (
+	// ErrTooManySessions is returned when a user already has as many
+	// sessions open as allowed.
+	ErrTooManySessions = errors.New("too many live sessions open")
+	// ErrSlowClient ends a session whose events are not being read.
+	ErrSlowClient = errors.New("client is not reading events")
+	// ErrQuotaExceeded is reported for paragraphs that would take a
+	// session past its word quota.
+	ErrQuotaExceeded = errors.New("session word quota exceeded")
+
+	errStale = errors.New("paragraph changed")
+)
+
+// Options tunes a LiveService.
+type Options struct {
+	// Workers is how many paragraphs one session proofreads at once.
+	Workers int
+	// MaxSessionsPerUser caps the sessions a user has open at once.
+	MaxSessionsPerUser int
+	// MaxParagraphs caps the paragraphs a session tracks.
+	MaxParagraphs int
+	// MaxParagraphRunes caps the length of one paragraph.
+	MaxParagraphRunes int
+	// CallsPerMinute caps a session's proofreading requests. Cached
+	// paragraphs do not count.
+	CallsPerMinute int
+	// MaxWords caps the words a session sends to be proofread. Cached
+	// paragraphs do not count.
+	MaxWords int
+	// SendBuffer is how many events may wait for the client before its
+	// session is ended.
+	SendBuffer int
+	// CacheSize and CacheTTL bound the results cache shared by sessions.
+	CacheSize int
+	CacheTTL  time.Duration
+}
+
+var DefaultOptions = Options{
+	Workers:            2,
+	MaxSessionsPerUser: 5,
+	MaxParagraphs:      1000,
+	MaxParagraphRunes:  4000,
+	CallsPerMinute:     30,
+	MaxWords:           50000,
+	SendBuffer:         256,
+	CacheSize:          10000,
+	CacheTTL:           30 * time.Minute,
+}
+
+// ProofreadFunc returns the suggestions for one paragraph.
+type ProofreadFunc func(ctx context.Context, text string) ([]llm.Suggestion, error)
+
+// Paragraph is one paragraph of the edited document.
+type Paragraph struct {
+	ID   string `json:"id"`
+	Text string `json:"text"`
+}
+
+// Suggestion is a suggestion with an ID that is stable for as long as the
+// suggestion applies unchanged to its paragraph.
+type Suggestion struct {
+	ID string `json:"id"`
+	llm.Suggestion
+}
+
+// Limits are a session's quotas, sent to the editor when it connects.
+type Limits struct {
+	MaxParagraphs     int `json:"max_paragraphs"`
+	MaxParagraphRunes int `json:"max_paragraph_runes"`
+	CallsPerMinute    int `json:"calls_per_minute"`
+	MaxWords          int `json:"max_words"`
+}
+
+// Usage is what a session has proofread so far.
+type Usage struct {
+	Calls     int `json:"calls"`
+	Words     int `json:"words"`
+	CacheHits int `json:"cache_hits"`
+}
+
+// Event is a message to the editor. Seq is that of the update whose text a
+// paragraph event describes.
+type Event struct {
+	Type        string       `json:"type"`
+	Seq         int64        `json:"seq,omitempty"`
+	ParagraphID string       `json:"paragraph_id,omitempty"`
+	Suggestions []Suggestion `json:"suggestions,omitempty"`
+	IDs         []string     `json:"ids,omitempty"`
+	Pending     *int         `json:"pending,omitempty"`
+	Cached      bool         `json:"cached,omitempty"`
+	Code        string       `json:"code,omitempty"`
+	Message     string       `json:"message,omitempty"`
+	Limits      *Limits      `json:"limits,omitempty"`
+	Usage       *Usage       `json:"usage,omitempty"`
+}
+
+type LiveService struct {
+	opts  Options
+	cache *cache
+
+	mu       sync.Mutex
+	sessions map[uint]int
+}
+
+func NewLiveService(opts Options) *LiveService {
+	return &LiveService{
+		opts:     opts,
+		cache:    newCache(opts.CacheSize, opts.CacheTTL),
+		sessions: make(map[uint]int),
+	}
+}
+
+// NewSession opens a session for the user. Results are cached under scope,
+// which must differ whenever proofread would answer differently, such as
+// for another user or style. The session runs until Close is called or the
+// client stops reading its events.
+func (s *LiveService) NewSession(userID uint, scope string, proofread ProofreadFunc) (*Session, error) {
+	s.mu.Lock()
+	if s.sessions[userID] >= s.opts.MaxSessionsPerUser {
+		s.mu.Unlock()
+		return nil, ErrTooManySessions
+	}
+	s.sessions[userID]++
+	s.mu.Unlock()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	session := &Session{
+		svc:        s,
+		userID:     userID,
+		scope:      scope,
+		proofread:  proofread,
+		ctx:        ctx,
+		cancel:     cancel,
+		events:     make(chan Event, s.opts.SendBuffer),
+		wake:       make(chan struct{}, 1),
+		paragraphs: make(map[string]*paragraph),
+	}
+	session.Notify(Event{Type: EventReady, Limits: &Limits{
+		MaxParagraphs:     s.opts.MaxParagraphs,
+		MaxParagraphRunes: s.opts.MaxParagraphRunes,
+		CallsPerMinute:    s.opts.CallsPerMinute,
+		MaxWords:          s.opts.MaxWords,
+	}})
+	for i := 0; i < max(s.opts.Workers, 1); i++ {
+		go session.work()
+	}
+	return session, nil
+}
+
+func (s *LiveService) release(userID uint) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.sessions[userID] <= 1 {
+		delete(s.sessions, userID)
+		return
+	}
+	s.sessions[userID]--
+}
+
+// Session is one editor's connection.
+type Session struct {
+	svc       *LiveService
+	userID    uint
+	scope     string
+	proofread ProofreadFunc
+	ctx       context.Context
+	cancel    context.CancelFunc
+	events    chan Event
+	wake      chan struct{}
+
+	mu         sync.Mutex
+	closed     bool
+	err        error
+	paragraphs map[string]*paragraph
+	queue      []string
+	callTimes  []time.Time
+	usage      Usage
+}
+
+// paragraph is what the session knows of one paragraph: its latest text
+// and the suggestions the editor holds for it.
+type paragraph struct {
+	text string
+	seq  int64
+	// queued is set while the latest text waits to be proofread, busy while
+	// a worker proofreads some text of it.
+	queued bool
+	busy   bool
+	sent   map[string]Suggestion
+}
+
+// Events returns the session's events. The channel is closed when the
+// session ends.
+func (s *Session) Events() <-chan Event {
+	return s.events
+}
+
+// Err returns why the session ended, if it ended on its own.
+func (s *Session) Err() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.err
+}
+
+// Usage returns what the session has proofread so far.
+func (s *Session) Usage() Usage {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.usage
+}
+
+// Close ends the session.
+func (s *Session) Close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closeLocked()
+}
+
+func (s *Session) closeLocked() {
+	if s.closed {
+		return
+	}
+	s.closed = true
+	s.cancel()
+	close(s.events)
+	s.svc.release(s.userID)
+}
+
+// Notify sends an event to the editor.
+func (s *Session) Notify(event Event) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.emit(event)
+}
+
+// emit queues an event for the client, ending the session if the client
+// has fallen too far behind. s.mu is held.
+func (s *Session) emit(event Event) {
+	if s.closed {
+		return
+	}
+	select {
+	case s.events <- event:
+	default:
+		s.err = ErrSlowClient
+		s.closeLocked()
+	}
+}
+
+// Update records the editor's changes: paragraphs added or edited, and the
+// IDs of paragraphs deleted. Changed paragraphs are queued to be proofread;
+// problems with individual paragraphs are reported as error events.
+func (s *Session) Update(seq int64, paragraphs []Paragraph, removed []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, id := range removed {
+		delete(s.paragraphs, id)
+	}
+	for _, in := range paragraphs {
+		if in.ID == "" || len(in.ID) > maxParagraphIDLength {
+			s.emit(Event{Type: EventError, Seq: seq, ParagraphID: in.ID, Code: CodeInvalidParagraph,
+				Message: "paragraph IDs must be 1 to " + strconv.Itoa(maxParagraphIDLength) + " bytes"})
+			continue
+		}
+		if utf8.RuneCountInString(in.Text) > s.svc.opts.MaxParagraphRunes {
+			s.emit(Event{Type: EventError, Seq: seq, ParagraphID: in.ID, Code: CodeParagraphTooLong,
+				Message: "paragraphs may be at most " + strconv.Itoa(s.svc.opts.MaxParagraphRunes) + " characters"})
+			continue
+		}
+
+		p := s.paragraphs[in.ID]
+		if p == nil {
+			if len(s.paragraphs) >= s.svc.opts.MaxParagraphs {
+				s.emit(Event{Type: EventError, Seq: seq, ParagraphID: in.ID, Code: CodeTooManyParagraphs,
+					Message: "documents may have at most " + strconv.Itoa(s.svc.opts.MaxParagraphs) + " paragraphs"})
+				continue
+			}
+			p = &paragraph{sent: make(map[string]Suggestion)}
+			s.paragraphs[in.ID] = p
+		} else if p.text == in.Text {
+			continue
+		}
+		p.text, p.seq = in.Text, seq
+		if !p.queued {
+			p.queued = true
+			// A busy paragraph is queued again when its worker finishes
+			if !p.busy {
+				s.queue = append(s.queue, in.ID)
+			}
+		}
+	}
+
+	pending := 0
+	for _, p := range s.paragraphs {
+		if p.queued {
+			pending++
+		}
+	}
+	s.emit(Event{Type: EventAck, Seq: seq, Pending: &pending})
+	s.signal()
+}
+
+// signal wakes a worker if there is work queued. s.mu is held.
+func (s *Session) signal() {
+	if len(s.queue) == 0 {
+		return
+	}
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (s *Session) work() {
+	for {
+		id, text, ok := s.next()
+		if !ok {
+			select {
+			case <-s.ctx.Done():
+				return
+			case <-s.wake:
+			}
+			continue
+		}
+		found, cached, err := s.check(id, text)
+		s.finish(id, text, found, cached, err)
+	}
+}
+
+// next takes the next queued paragraph.
+func (s *Session) next() (string, string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for len(s.queue) > 0 {
+		id := s.queue[0]
+		s.queue = s.queue[1:]
+		p := s.paragraphs[id]
+		if p == nil || !p.queued {
+			continue
+		}
+		p.queued, p.busy = false, true
+		s.signal()
+		return id, p.text, true
+	}
+	return "", "", false
+}
+
+// check proofreads a paragraph's text, from the cache if it can.
+func (s *Session) check(id, text string) ([]llm.Suggestion, bool, error) {
+	if strings.TrimSpace(text) == "" {
+		return nil, false, nil
+	}
+	key := s.cacheKey(text)
+	if found, ok := s.svc.cache.get(key); ok {
+		s.mu.Lock()
+		s.usage.CacheHits++
+		s.mu.Unlock()
+		return found, true, nil
+	}
+
+	if err := s.acquire(id, text, len(strings.Fields(text))); err != nil {
+		return nil, false, err
+	}
+	found, err := s.proofread(s.ctx, text)
+	if err != nil {
+		return nil, false, err
+	}
+	s.svc.cache.put(key, found)
+	return found, false, nil
+}
+
+// acquire waits for the session's rate limit to allow another call and
+// counts it, unless the paragraph has changed meanwhile or the words would
+// exceed the session's quota.
+func (s *Session) acquire(id, text string, words int) error {
+	for {
+		s.mu.Lock()
+		if p := s.paragraphs[id]; p == nil || p.text != text {
+			s.mu.Unlock()
+			return errStale
+		}
+		if s.usage.Words+words > s.svc.opts.MaxWords {
+			s.mu.Unlock()
+			return ErrQuotaExceeded
+		}
+		now := time.Now()
+		cutoff := now.Add(-time.Minute)
+		for len(s.callTimes) > 0 && !s.callTimes[0].After(cutoff) {
+			s.callTimes = s.callTimes[1:]
+		}
+		if len(s.callTimes) < s.svc.opts.CallsPerMinute {
+			s.callTimes = append(s.callTimes, now)
+			s.usage.Calls++
+			s.usage.Words += words
+			s.mu.Unlock()
+			return nil
+		}
+		wait := s.callTimes[0].Sub(cutoff)
+		s.mu.Unlock()
+
+		select {
+		case <-s.ctx.Done():
+			return s.ctx.Err()
+		case <-time.After(wait):
+		}
+	}
+}
+
+// finish reports a paragraph's new suggestions, unless it has been edited
+// or deleted since the text was proofread.
+func (s *Session) finish(id, text string, found []llm.Suggestion, cached bool, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p := s.paragraphs[id]
+	if p == nil || s.closed {
+		return
+	}
+	p.busy = false
+	if p.queued {
+		s.queue = append(s.queue, id)
+		s.signal()
+		return
+	}
+	if errors.Is(err, errStale) || p.text != text {
+		return
+	}
+
+	if err != nil {
+		// The suggestions held for the old text no longer line up with it
+		s.replace(id, p, nil)
+		code := CodeProofreadFailed
+		if errors.Is(err, ErrQuotaExceeded) {
+			code = CodeQuotaExceeded
+		}
+		s.emit(Event{Type: EventError, Seq: p.seq, ParagraphID: id, Code: code, Message: err.Error()})
+		return
+	}
+
+	s.replace(id, p, found)
+	usage := s.usage
+	s.emit(Event{Type: EventChecked, Seq: p.seq, ParagraphID: id, Cached: cached, Usage: &usage})
+}
+
+// replace sends the changes from the paragraph's held suggestions to
+// found. s.mu is held.
+func (s *Session) replace(id string, p *paragraph, found []llm.Suggestion) {
+	next := make(map[string]Suggestion, len(found))
+	for _, sg := range found {
+		next[suggestionID(sg)] = Suggestion{ID: suggestionID(sg), Suggestion: sg}
+	}
+
+	var removed []string
+	for sid := range p.sent {
+		if _, ok := next[sid]; !ok {
+			removed = append(removed, sid)
+		}
+	}
+	var added []Suggestion
+	for sid, sg := range next {
+		if _, ok := p.sent[sid]; !ok {
+			added = append(added, sg)
+		}
+	}
+	p.sent = next
+
+	if len(removed) > 0 {
+		sort.Strings(removed)
+		s.emit(Event{Type: EventRemove, Seq: p.seq, ParagraphID: id, IDs: removed})
+	}
+	if len(added) > 0 {
+		sort.Slice(added, func(i, j int) bool {
+			if added[i].StartIndex != added[j].StartIndex {
+				return added[i].StartIndex < added[j].StartIndex
+			}
+			return added[i].ID < added[j].ID
+		})
+		s.emit(Event{Type: EventAdd, Seq: p.seq, ParagraphID: id, Suggestions: added})
+	}
+}
+
+func (s *Session) cacheKey(text string) string {
+	sum := sha256.Sum256([]byte(s.scope + "\x00" + text))
+	return hex.EncodeToString(sum[:])
+}
+
+// suggestionID identifies a suggestion by what it changes and where, so
+// proofreading the same text again yields the same IDs.
+func suggestionID(sg llm.Suggestion) string {
+	sum := sha1.Sum([]byte(strings.Join([]string{
+		sg.Original, sg.Corrected, sg.Type,
+		strconv.Itoa(sg.StartIndex), strconv.Itoa(sg.EndIndex),
+	}, "\x00")))
+	return "sg_" + hex.EncodeToString(sum[:8])
+}
@@ -3,6 +3,7 @@ package llm
 
 import (
         "bytes"
+        "context"
         "encoding/json"
         "fmt"
         "io"
@@ -64,8 +65,53 @@ var geminiClient = &http.Client{
         },
 }
 
+// maxProtectedTerms caps how many dictionary words are sent with each prompt
+const maxProtectedTerms = 200
+
+// buildProofreadPrompt fills the prompt template, adding the style guide and
+// the user's protected terms ahead of the text.
+func buildProofreadPrompt(userText string, opts ProofreadOptions) string {
+        prompt := proofreadingPrompt
+
+        var blocks []string
+        if guide := strings.TrimSpace(opts.StyleGuide); guide != "" {
+                blocks = append(blocks, "STYLE GUIDE (follow these conventions; report style fixes with type \"style\"):\n"+guide)
+        }
+
+        if len(opts.ProtectedTerms) > 0 {
+                terms := make([]string, 0, len(opts.ProtectedTerms))
+                for _, term := range opts.ProtectedTerms {
+                        term = strings.Join(strings.Fields(term), " ")
+                        if term == "" || len(term) > 100 {
+                                continue
+                        }
+                        terms = append(terms, "- "+term)
+                        if len(terms) == maxProtectedTerms {
+                                break
+                        }
+                }
+                if len(terms) > 0 {
+                        blocks = append(blocks, "USER DICTIONARY (names, brands and terms that are spelled correctly - NEVER correct these, even if they look unusual):\n"+
+                                strings.Join(terms, "\n"))
+                }
+        }
+
+        if len(blocks) > 0 {
+                prompt = strings.Replace(prompt, "TEXT TO PROOFREAD:", strings.Join(blocks, "\n\n")+"\n\nTEXT TO PROOFREAD:", 1)
+        }
+
+        // CRITICAL: Replace the actual placeholder in the prompt template
+        return strings.Replace(prompt, "[USER'S TAMIL TEXT HERE]", userText, 1)
+}
+
 // CallGeminiProofread calls Gemini 2.5 Flash with the proofreading prompt
 func CallGeminiProofread(userText string, model string, apiKey string) (string, error) {
+        return CallGeminiProofreadWithOptions(context.Background(), userText, model, apiKey, ProofreadOptions{})
+}
+
+// CallGeminiProofreadWithOptions calls Gemini with a prompt tailored by opts.
+// Cancelling ctx abandons the request.
+func CallGeminiProofreadWithOptions(ctx context.Context, userText string, model string, apiKey string, opts ProofreadOptions) (string, error) {
         if apiKey == "" {
                 return "", fmt.Errorf("API key not provided")
         }
@@ -73,8 +119,8 @@ func CallGeminiProofread(userText string, model string, apiKey string) (string,
         startTime := time.Now()
         log.Printf("[GEMINI] Starting with model: %s, text length: %d", model, len(userText))
 
-        // Build final prompt - CRITICAL: Replace the actual placeholder in the prompt template
-        finalPrompt := strings.Replace(proofreadingPrompt, "[USER'S TAMIL TEXT HERE]", userText, 1)
+        // Build final prompt
+        finalPrompt := buildProofreadPrompt(userText, opts)
         promptBuildTime := time.Since(startTime)
 
         // Gemini API Endpoint
@@ -107,7 +153,7 @@ func CallGeminiProofread(userText string, model string, apiKey string) (string,
         prepTime := time.Since(startTime)
         log.Printf("[GEMINI] Prep time: %v (prompt build: %v)", prepTime, promptBuildTime)
 
-        req, err := http.NewRequest("POST", url, bytes.NewReader(jsonBody))
+        req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(jsonBody))
         if err != nil {
                 log.Printf("[GEMINI] Request build error: %v", err)
                 return "", err
@@ -331,3 +377,99 @@ func CallGeminiTransliterate(englishText string, apiKey string) ([]string, error
 
         return suggestions, nil
 }
+
+var codeMixPrompt = `You are a Tamil terminology assistant.
+Each input word is an English word written in Latin or Tamil script inside Tamil text.
+For each word give the English word it represents and up to 3 commonly understood Tamil equivalents, best first.
+
+Output ONLY valid JSON:
+{"words": [{"input": "மீட்டிங்", "english": "meeting", "tamil": ["கூட்டம்", "சந்திப்பு"]}]}
+
+Rules:
+- Skip inputs that are not English words (names, Tamil words, typos); do not include them.
+- "english" is lowercase.
+- "tamil" contains Tamil Unicode words only, never transliterations of the English.
+- Never output anything outside JSON.
+
+WORDS:
+{{words}}`
+
+// CodeMixSuggestion is a Tamil equivalent lookup for one code-mixed word
+type CodeMixSuggestion struct {
+        Input   string   `json:"input"`
+        English string   `json:"english"`
+        Tamil   []string `json:"tamil"`
+}
+
+// CallGeminiCodeMix asks Gemini for Tamil equivalents of code-mixed words
+func CallGeminiCodeMix(words []string, apiKey string) ([]CodeMixSuggestion, error) {
+        if apiKey == "" {
+                return nil, fmt.Errorf("API key not provided")
+        }
+        if len(words) == 0 {
+                return nil, nil
+        }
+
+        startTime := time.Now()
+        finalPrompt := strings.Replace(codeMixPrompt, "{{words}}", strings.Join(words, "\n"), 1)
+        url := fmt.Sprintf("https://generativelanguage.googleapis.com/v1beta/models/gemini-2.0-flash-lite:generateContent?key=%s", apiKey)
+
+        payload := map[string]interface{}{
+                "contents": []map[string]interface{}{{
+                        "parts": []map[string]string{{
+                                "text": finalPrompt,
+                        }},
+                }},
+                "generationConfig": map[string]interface{}{
+                        "temperature":      0.1,
+                        "maxOutputTokens":  1024,
+                        "responseMimeType": "application/json",
+                },
+        }
+
+        jsonBody, err := json.Marshal(payload)
+        if err != nil {
+                return nil, fmt.Errorf("failed to build request: %v", err)
+        }
+
+        req, err := http.NewRequest("POST", url, bytes.NewReader(jsonBody))
+        if err != nil {
+                return nil, fmt.Errorf("failed to create request: %v", err)
+        }
+        req.Header.Set("Content-Type", "application/json")
+
+        resp, err := geminiClient.Do(req)
+        if err != nil {
+                log.Printf("[CODEMIX] ERROR: HTTP request failed after %v: %v", time.Since(startTime), err)
+                return nil, fmt.Errorf("API request failed: %v", err)
+        }
+        defer resp.Body.Close()
+
+        bodyBytes, err := io.ReadAll(resp.Body)
+        if err != nil {
+                return nil, fmt.Errorf("failed to read response: %v", err)
+        }
+        if resp.StatusCode != http.StatusOK {
+                log.Printf("[CODEMIX] ERROR: status %d: %s", resp.StatusCode, string(bodyBytes))
+                return nil, fmt.Errorf("API returned status %d", resp.StatusCode)
+        }
+
+        var geminiResp GeminiResponse
+        if err := json.Unmarshal(bodyBytes, &geminiResp); err != nil {
+                return nil, err
+        }
+        if len(geminiResp.Candidates) == 0 || len(geminiResp.Candidates[0].Content.Parts) == 0 {
+                return nil, fmt.Errorf("no content returned from Gemini")
+        }
+
+        var parsed struct {
+                Words []CodeMixSuggestion `json:"words"`
+        }
+        content := stripCodeFence(geminiResp.Candidates[0].Content.Parts[0].Text)
+        if err := json.Unmarshal([]byte(content), &parsed); err != nil {
+                return nil, fmt.Errorf("invalid JSON from Gemini: %v", err)
+        }
+
+        log.Printf("[CODEMIX] Looked up %d words, got %d in %v", len(words), len(parsed.Words), time.Since(startTime))
+        return parsed.Words, nil
+}
@@ -10,6 +10,7 @@ import (
         "time"
 
         "tamil-proofreading-platform/backend/internal/models"
+        "tamil-proofreading-platform/backend/internal/services/diff"
         "tamil-proofreading-platform/backend/internal/services/nlp"
 
         openai "github.com/sashabaranov/go-openai"
@@ -39,6 +40,40 @@ type Suggestion struct {
         EndIndex   int    `json:"end_index"`
 }
 
+// ProofreadOptions tailors a proofreading request to the submitting user.
+type ProofreadOptions struct {
+        // ProtectedTerms are dictionary words the model must not correct.
+        ProtectedTerms []string
+        // StyleGuide describes the selected style profile's conventions.
+        StyleGuide string
+        // Model is the Gemini model to use; empty chooses one by the length
+        // of the text.
+        Model string
+}
+
+// Models are the Gemini models a submission may ask for.
+var Models = []string{models.ModelGeminiFlashLite, models.ModelGeminiFlash, models.ModelGeminiPro}
+
+// ValidModel reports whether name is one of Models.
+func ValidModel(name string) bool {
+        for _, m := range Models {
+                if m == name {
+                        return true
+                }
+        }
+        return false
+}
+
+// PlanAllowsModel reports whether a subscription plan may ask for a model.
+// The models chosen automatically by length are open to every plan; Pro is
+// reserved for the Pro and Enterprise plans.
+func PlanAllowsModel(plan models.SubscriptionPlan, name string) bool {
+        if name != models.ModelGeminiPro {
+                return true
+        }
+        return plan == models.PlanPro || plan == models.PlanEnterprise
+}
+
 type Change struct {
         Original  string `json:"original"`
         Corrected string `json:"corrected"`
@@ -85,57 +120,34 @@ func (s *LLMService) selectOptimalModel(text string, wordCount int) models.Model
         return models.ModelType(models.ModelGeminiFlash)
 }
 
-// detectChangesFromText auto-generates suggestions by finding differences between original and corrected text
+// detectChangesFromText auto-generates suggestions by diffing original and corrected text word by word
 // This is a fallback when Gemini doesn't return explicit corrections array
 func detectChangesFromText(original, corrected string) []Suggestion {
-        if original == corrected {
-                return []Suggestion{}
-        }
-
-        var suggestions []Suggestion
-
-        // Split into words for comparison
-        origWords := strings.Fields(original)
-        corrWords := strings.Fields(corrected)
-
-        // Simple word-by-word comparison
-        minLen := len(origWords)
-        if len(corrWords) < minLen {
-                minLen = len(corrWords)
-        }
-
-        for i := 0; i < minLen; i++ {
-                if origWords[i] != corrWords[i] {
-                        // Find the position in the original text
-                        pos := strings.Index(original, origWords[i])
-                        if pos >= 0 {
-                                suggestions = append(suggestions, Suggestion{
-                                        Original:   origWords[i],
-                                        Corrected:  corrWords[i],
-                                        Reason:     "சரி செய்யப்பட்ட சொல்", // "Corrected word" in Tamil
-                                        Type:       "correction",
-                                        StartIndex: pos,
-                                        EndIndex:   pos + len(origWords[i]),
-                                })
-                        }
+        suggestions := []Suggestion{}
+        for _, op := range diff.Changes(diff.Words(original, corrected)) {
+                // Whitespace-only changes are not worth a suggestion
+                if strings.TrimSpace(op.Old) == "" && strings.TrimSpace(op.New) == "" {
+                        continue
                 }
-        }
 
-        // If different lengths, capture the extra/missing content
-        if len(corrWords) > len(origWords) {
-                remaining := strings.Join(corrWords[len(origWords):], " ")
-                if remaining != "" {
-                        suggestions = append(suggestions, Suggestion{
-                                Original:   "",
-                                Corrected:  remaining,
-                                Reason:     "சேர்க்கப்பட்ட வார்த்தைகள்", // "Added words" in Tamil
-                                Type:       "addition",
-                                StartIndex: len(original),
-                                EndIndex:   len(corrected),
-                        })
+                s := Suggestion{
+                        Original:   op.Old,
+                        Corrected:  op.New,
+                        Reason:     "சரி செய்யப்பட்ட சொல்", // "Corrected word" in Tamil
+                        Type:       "correction",
+                        StartIndex: op.OldStart,
+                        EndIndex:   op.OldEnd,
+                }
+                switch op.Type {
+                case diff.Insert:
+                        s.Reason = "சேர்க்கப்பட்ட வார்த்தைகள்" // "Added words" in Tamil
+                        s.Type = "addition"
+                case diff.Delete:
+                        s.Reason = "நீக்கப்பட்ட வார்த்தைகள்" // "Removed words" in Tamil
+                        s.Type = "deletion"
                 }
+                suggestions = append(suggestions, s)
         }
-
         return suggestions
 }
 
@@ -200,6 +212,10 @@ func (s *LLMService) ProofreadWithGoogle(ctx context.Context, text string, reque
 }
 
 func (s *LLMService) Proofread(ctx context.Context, text string, requestID string) (*ProofreadResult, error) {
+        return s.ProofreadWithOptions(ctx, text, requestID, ProofreadOptions{})
+}
+
+func (s *LLMService) ProofreadWithOptions(ctx context.Context, text string, requestID string, opts ProofreadOptions) (*ProofreadResult, error) {
         start := time.Now()
 
         if text == "" {
@@ -218,13 +234,16 @@ func (s *LLMService) Proofread(ctx context.Context, text string, requestID strin
         cleaned := s.nlpService.Preprocess(text)
         cleaned = sanitizeUserInput(cleaned)
         
-        // Smart model selection based on text length
+        // Smart model selection based on text length, unless one was requested
         wordCount := s.nlpService.CountWords(cleaned)
         selectedModel := s.selectOptimalModel(cleaned, wordCount)
+        if ValidModel(opts.Model) {
+                selectedModel = models.ModelType(opts.Model)
+        }
 
         // Try Google Gemini first
         if s.googleAPIKey != "" {
-                content, err := CallGeminiProofread(cleaned, string(selectedModel), s.googleAPIKey)
+                content, err := CallGeminiProofreadWithOptions(ctx, cleaned, string(selectedModel), s.googleAPIKey, opts)
                 if err == nil && strings.TrimSpace(content) != "" {
                         log.Printf("[GEMINI-SUCCESS] Got response (request_id=%s, len=%d)", requestID, len(content))
                         corrected, suggestions, changes, alternatives, ok := parseProofreadJSON(content)
@@ -257,6 +276,11 @@ func (s *LLMService) Proofread(ctx context.Context, text string, requestID strin
                 log.Printf("[GEMINI-NO-KEY] Google API key not configured (request_id=%s)", requestID)
         }
 
+        // A cancelled request has no result, not an empty one
+        if err := ctx.Err(); err != nil {
+                return nil, err
+        }
+
         // Safe fallback: return text as-is with no suggestions instead of error
         // This allows the demo editor to work even if Gemini API fails
         log.Printf("[FALLBACK] Returning text without corrections (request_id=%s)", requestID)
@@ -498,3 +522,19 @@ func sanitizeUserInput(text string) string {
 func (s *LLMService) ProofreadText(ctx context.Context, text string, wordCount int, includeAlternatives bool, requestID string) (*ProofreadResult, error) {
         return s.Proofread(ctx, text, requestID)
 }
+
+// ProofreadTextWithOptions is ProofreadText with per-user prompt options
+func (s *LLMService) ProofreadTextWithOptions(ctx context.Context, text string, wordCount int, includeAlternatives bool, requestID string, opts ProofreadOptions) (*ProofreadResult, error) {
+        return s.ProofreadWithOptions(ctx, text, requestID, opts)
+}
+
+// ErrNoProvider is returned by optional lookups when no LLM key is configured
+var ErrNoProvider = errors.New("no LLM provider configured")
+
+// SuggestCodeMixEquivalents looks up Tamil equivalents for code-mixed words
+func (s *LLMService) SuggestCodeMixEquivalents(ctx context.Context, words []string) ([]CodeMixSuggestion, error) {
+        if s.googleAPIKey == "" {
+                return nil, ErrNoProvider
+        }
+        return CallGeminiCodeMix(words, s.googleAPIKey)
+}
@@ -0,0 +1,200 @@
+// Package moderation runs the automatic checks applied to user-contributed
+// Tamil words before they reach the reviewer queue.
+package moderation
+
+import (
+	_ "embed"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"tamil-proofreading-platform/backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+//go:embed blocklist.txt
+var defaultBlocklist string
+
+const (
+	FlagDuplicateTamil     = "duplicate_tamil"
+	FlagAlternateSpelling  = "known_alternate"
+	FlagBlockedTerm        = "blocked_term"
+	FlagRejectedBefore     = "previously_rejected"
+	FlagUncommonTranslit   = "unusual_transliteration"
+	FlagCategoryUnknown    = "unknown_category"
+	FlagContributorPending = "contributor_backlog"
+)
+
+// contributorBacklogLimit flags contributors with many unreviewed words so
+// reviewers can spot bulk or automated submissions.
+const contributorBacklogLimit = 25
+
+var knownCategories = map[models.WordCategory]bool{
+	models.CategoryCommon: true, models.CategoryVerb: true, models.CategoryNoun: true,
+	models.CategoryAdjective: true, models.CategoryPronoun: true, models.CategoryAdverb: true,
+	models.CategoryPreposition: true, models.CategoryConjunction: true, models.CategoryInterjection: true,
+	models.CategoryProperNoun: true, models.CategoryPhrase: true,
+}
+
+// Result is the outcome of the automatic checks.
+type Result struct {
+	// Flags are findings shown to reviewers; they do not block submission.
+	Flags []string `json:"flags"`
+	// Blocked is set when the word must be rejected without review.
+	Blocked bool `json:"blocked"`
+	// Notes explain each flag in reviewer-facing language.
+	Notes []string `json:"notes,omitempty"`
+}
+
+func (r *Result) flag(name, note string) {
+	r.Flags = append(r.Flags, name)
+	r.Notes = append(r.Notes, note)
+}
+
+type ModerationService struct {
+	db         *gorm.DB
+	tamilTerms []string
+	latinTerms map[string]struct{}
+}
+
+func NewModerationService(db *gorm.DB) *ModerationService {
+	s := &ModerationService{db: db, latinTerms: make(map[string]struct{})}
+	for _, line := range strings.Split(defaultBlocklist, "\n") {
+		term := strings.ToLower(strings.TrimSpace(line))
+		if term == "" || strings.HasPrefix(term, "#") {
+			continue
+		}
+		if containsTamil(term) {
+			s.tamilTerms = append(s.tamilTerms, term)
+		} else {
+			s.latinTerms[term] = struct{}{}
+		}
+	}
+	return s
+}
+
+// ValidateScript reports whether tamil is well-formed Tamil script and
+// transliteration is plain lowercase Latin.
+func ValidateScript(tamil, transliteration string) error {
+	if tamil == "" {
+		return fmt.Errorf("tamil_text is required")
+	}
+
+	prevBase := false
+	var prev rune
+	for i, r := range tamil {
+		switch {
+		case r == 0x0BD7 && prev == 0x0BC6:
+			// Decomposed ௌ (ெ + ௗ).
+		case r == ' ' || r == '\u200c' || r == '\u200d':
+			prevBase = false
+		case isTamilSign(r):
+			if i == 0 || !prevBase {
+				return fmt.Errorf("tamil_text has a vowel sign or pulli without a consonant")
+			}
+			// A consonant takes at most one dependent sign (ொ/ோ/ௌ are single runes).
+			prevBase = false
+		case unicode.Is(unicode.Tamil, r):
+			prevBase = true
+		default:
+			return fmt.Errorf("tamil_text must contain only Tamil script (found %q)", r)
+		}
+		prev = r
+	}
+
+	if transliteration == "" {
+		return fmt.Errorf("transliteration is required")
+	}
+	for _, r := range transliteration {
+		if !(r >= 'a' && r <= 'z') && r != ' ' && r != '-' && r != '\'' {
+			return fmt.Errorf("transliteration must use lowercase Latin letters (found %q)", r)
+		}
+	}
+	return nil
+}
+
+// Check runs the duplicate, blocklist and plausibility checks for a
+// contribution. The word must already have passed ValidateScript.
+func (s *ModerationService) Check(word *models.TamilWord) (Result, error) {
+	var result Result
+
+	if s.isBlocked(word) {
+		result.Blocked = true
+		result.flag(FlagBlockedTerm, "contains a term on the moderation blocklist")
+	}
+
+	var sameTamil []models.TamilWord
+	if err := s.db.Where("tamil_text = ? AND id <> ?", word.TamilText, word.ID).Find(&sameTamil).Error; err != nil {
+		return result, err
+	}
+	for _, other := range sameTamil {
+		switch other.ReviewStatus {
+		case models.WordRejected:
+			result.flag(FlagRejectedBefore, fmt.Sprintf("same Tamil text was rejected as %q: %s", other.Transliteration, other.ReviewNote))
+		default:
+			result.flag(FlagDuplicateTamil, fmt.Sprintf("same Tamil text already exists as %q (%s)", other.Transliteration, other.ReviewStatus))
+		}
+		for _, alt := range other.Alternates() {
+			if alt == word.Transliteration {
+				result.flag(FlagAlternateSpelling, fmt.Sprintf("already an alternate spelling of %q", other.Transliteration))
+			}
+		}
+	}
+
+	if !hasVowel(word.Transliteration) || len(word.Transliteration) > 40 {
+		result.flag(FlagUncommonTranslit, "transliteration does not look like a romanised Tamil word")
+	}
+	if word.Category != "" && !knownCategories[word.Category] {
+		result.flag(FlagCategoryUnknown, fmt.Sprintf("category %q is not a known word category", word.Category))
+	}
+
+	if word.SubmittedBy != nil {
+		var backlog int64
+		if err := s.db.Model(&models.TamilWord{}).
+			Where("submitted_by = ? AND review_status = ?", *word.SubmittedBy, models.WordPending).
+			Count(&backlog).Error; err != nil {
+			return result, err
+		}
+		if backlog >= contributorBacklogLimit {
+			result.flag(FlagContributorPending, fmt.Sprintf("contributor has %d words awaiting review", backlog))
+		}
+	}
+
+	return result, nil
+}
+
+func (s *ModerationService) isBlocked(word *models.TamilWord) bool {
+	for _, term := range s.tamilTerms {
+		if strings.Contains(word.TamilText, term) {
+			return true
+		}
+	}
+
+	candidates := append([]string{word.Transliteration}, word.Alternates()...)
+	candidates = append(candidates, strings.Fields(strings.ToLower(word.Meaning))...)
+	for _, c := range candidates {
+		if _, ok := s.latinTerms[strings.Trim(c, ".,;:!?()\"'")]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+func containsTamil(s string) bool {
+	for _, r := range s {
+		if unicode.Is(unicode.Tamil, r) {
+			return true
+		}
+	}
+	return false
+}
+
+// isTamilSign reports dependent vowel signs, the pulli and the au length mark.
+func isTamilSign(r rune) bool {
+	return (r >= 0x0BBE && r <= 0x0BCD) || r == 0x0BD7 || r == 0x0B82
+}
+
+func hasVowel(s string) bool {
+	return strings.ContainsAny(s, "aeiou")
+}
@@ -0,0 +1,30 @@
+package moderation
+
+import (
+	"testing"
+
+	"tamil-proofreading-platform/backend/internal/models"
+)
+
+func TestIsBlocked(t *testing.T) {
+	s := NewModerationService(nil)
+	tests := []struct {
+		word models.TamilWord
+		want bool
+	}{
+		{models.TamilWord{TamilText: "தேவடியாள்", Transliteration: "thevadiyaal"}, true},
+		{models.TamilWord{TamilText: "ஓத்தா", Transliteration: "ootha"}, true},
+		{models.TamilWord{TamilText: "கூதி", Transliteration: "koothi"}, true},
+		{models.TamilWord{TamilText: "வணக்கம்", Transliteration: "vanakkam", Meaning: "hello, you bastard"}, true},
+		{models.TamilWord{TamilText: "ஒத்தாசை", Transliteration: "othaasai", Meaning: "help"}, false},
+		{models.TamilWord{TamilText: "கூதிர்", Transliteration: "koothir", Meaning: "cold wind"}, false},
+		{models.TamilWord{TamilText: "ஒத்த", Transliteration: "otha", Meaning: "similar"}, false},
+		{models.TamilWord{TamilText: "மயிர்", Transliteration: "mayir", Meaning: "hair"}, false},
+		{models.TamilWord{TamilText: "சுன்னி", Transliteration: "sunni", Meaning: "Sunni"}, false},
+	}
+	for _, tt := range tests {
+		if got := s.isBlocked(&tt.word); got != tt.want {
+			t.Errorf("isBlocked(%s / %s) = %v, want %v", tt.word.TamilText, tt.word.Transliteration, got, tt.want)
+		}
+	}
+}
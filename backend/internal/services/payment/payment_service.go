@@ -154,6 +154,20 @@ func (s *PaymentService) UpdatePaymentStatus(paymentID uint, status models.Payme
 	return s.db.Save(payment).Error
 }
 
+// ChangePaymentStatus sets a payment's status unless it already has it, and
+// reports whether it changed. Gateways retry webhooks, so only the call
+// that makes the change should act on it.
+func (s *PaymentService) ChangePaymentStatus(paymentID uint, status models.PaymentStatus, gatewayPaymentID string) (bool, error) {
+	updates := map[string]interface{}{"status": status}
+	if gatewayPaymentID != "" {
+		updates["gateway_payment_id"] = gatewayPaymentID
+	}
+	result := s.db.Model(&models.Payment{}).
+		Where("id = ? AND status <> ?", paymentID, status).
+		Updates(updates)
+	return result.RowsAffected > 0, result.Error
+}
+
 // GetPaymentByTransactionID retrieves payment by transaction ID
 func (s *PaymentService) GetPaymentByTransactionID(transactionID string) (*models.Payment, error) {
 	var payment models.Payment
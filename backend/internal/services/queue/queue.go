@@ -0,0 +1,537 @@
+// Package queue runs submission jobs from a Postgres table. Workers claim
+// jobs with FOR UPDATE SKIP LOCKED, so any number of workers and processes
+// can share the table, and hold each job on a lease they renew while it
+// runs. Failed jobs are retried with exponential backoff and moved to the
+// dead-letter state once out of attempts.
+//
+// Jobs are scheduled by their owner's subscription plan. Higher-priority
+// lanes are claimed first; within a lane, users share the workers by
+// weighted fair queuing on word count, so one user's large uploads delay
+// only their own later work, and each user has a cap on running jobs. A job
+// left waiting longer than MaxWait is claimed as if in the top lane, so
+// lower lanes are slowed but never starved.
+package queue
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math/rand"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"tamil-proofreading-platform/backend/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Options tunes a JobQueue.
+type Options struct {
+	// Workers is how many jobs this process runs at once.
+	Workers int
+	// PollInterval is how often idle workers look for due jobs.
+	PollInterval time.Duration
+	// Visibility is the lease on a running job. Workers renew it while the
+	// job runs; once it lapses another worker may take the job.
+	Visibility time.Duration
+	// MaxAttempts is how many times a job runs before it is dead.
+	MaxAttempts int
+	// BaseBackoff is the wait before the first retry; it doubles with each
+	// attempt up to MaxBackoff.
+	BaseBackoff time.Duration
+	MaxBackoff  time.Duration
+	// SweepInterval is how often orphaned submissions are requeued and old
+	// finished jobs removed.
+	SweepInterval time.Duration
+	// Retention is how long done jobs are kept. Dead jobs are kept until
+	// they are retried or removed by an admin.
+	Retention time.Duration
+	// Plans sets the scheduling policy of each subscription plan; users on
+	// a plan not listed get the free plan's.
+	Plans map[models.SubscriptionPlan]PlanPolicy
+	// MaxWait is how long a due job waits before it is promoted to the top
+	// lane.
+	MaxWait time.Duration
+}
+
+// PlanPolicy is how jobs of users on one plan are scheduled.
+type PlanPolicy struct {
+	// Priority is the plan's lane; higher lanes are claimed first.
+	Priority int
+	// Weight is the plan's share of the workers relative to other plans in
+	// its lane.
+	Weight float64
+	// MaxConcurrent caps how many jobs a user runs at once; 0 is no cap.
+	MaxConcurrent int
+}
+
+var DefaultOptions = Options{
+	Workers:       4,
+	PollInterval:  2 * time.Second,
+	Visibility:    2 * time.Minute,
+	MaxAttempts:   5,
+	BaseBackoff:   10 * time.Second,
+	MaxBackoff:    10 * time.Minute,
+	SweepInterval: time.Minute,
+	Retention:     7 * 24 * time.Hour,
+	Plans: map[models.SubscriptionPlan]PlanPolicy{
+		models.PlanFree:       {Priority: 0, Weight: 1, MaxConcurrent: 1},
+		models.PlanBasic:      {Priority: 0, Weight: 2, MaxConcurrent: 2},
+		models.PlanPro:        {Priority: 1, Weight: 4, MaxConcurrent: 4},
+		models.PlanEnterprise: {Priority: 1, Weight: 8, MaxConcurrent: 8},
+	},
+	MaxWait: 2 * time.Minute,
+}
+
+// orphanGrace is how old a pending submission must be before the sweeper
+// treats it as orphaned, so it does not race a request still enqueueing it.
+const orphanGrace = time.Minute
+
+// claimLock is the advisory lock key that serializes claims, so two
+// workers cannot both take a user's last free slot.
+const claimLock = 0x6a6f6273 // "jobs"
+
+// Handler runs a job. A returned error schedules a retry.
+type Handler func(ctx context.Context, job models.Job) error
+
+// FailureHandler is told about each failed attempt. dead is true when the
+// job will not be retried.
+type FailureHandler func(job models.Job, err error, dead bool)
+
+var (
+	ErrLeaseLost = errors.New("job lease lost")
+	ErrNotDead   = errors.New("only dead jobs can be retried")
+)
+
+type JobQueue struct {
+	db       *gorm.DB
+	opts     Options
+	handle   Handler
+	failed   FailureHandler
+	workerID string
+	wake     chan struct{}
+
+	mu      sync.Mutex
+	running map[uint]context.CancelFunc // by job ID
+}
+
+func NewJobQueue(db *gorm.DB, opts Options, handle Handler, failed FailureHandler) *JobQueue {
+	host, _ := os.Hostname()
+	return &JobQueue{
+		db:       db,
+		opts:     opts,
+		handle:   handle,
+		failed:   failed,
+		workerID: host + ":" + strconv.Itoa(os.Getpid()),
+		wake:     make(chan struct{}, 1),
+		running:  make(map[uint]context.CancelFunc),
+	}
+}
+
+// Enqueue queues a job for the submission using db, which may be a
+// transaction. It does nothing if the submission already has an unfinished
+// job.
+func (q *JobQueue) Enqueue(db *gorm.DB, submissionID uint) error {
+	created, err := q.enqueue(db, submissionID)
+	if err != nil {
+		return err
+	}
+	if created {
+		q.notify()
+	}
+	return nil
+}
+
+func (q *JobQueue) enqueue(db *gorm.DB, submissionID uint) (bool, error) {
+	var owner struct {
+		UserID       uint
+		WordCount    int
+		Subscription models.SubscriptionPlan
+	}
+	if err := db.Table("submissions").
+		Select("submissions.user_id, submissions.word_count, users.subscription").
+		Joins("JOIN users ON users.id = submissions.user_id").
+		Where("submissions.id = ?", submissionID).
+		Limit(1).Scan(&owner).Error; err != nil {
+		return false, err
+	}
+	if owner.UserID == 0 {
+		return false, gorm.ErrRecordNotFound
+	}
+	plan, policy := q.policy(owner.Subscription)
+
+	// Start-time fair queuing: the job starts at the later of the system's
+	// virtual time (the earliest start of any unfinished job, or the latest
+	// finish when the queue is idle) and the finish of the user's previous
+	// job, and costs its words divided by the plan's weight
+	var tags struct {
+		Now  float64
+		Prev float64
+	}
+	if err := db.Raw(`
+		SELECT
+			COALESCE((SELECT MIN(virtual_start) FROM jobs WHERE finished_at IS NULL),
+				(SELECT MAX(virtual_finish) FROM jobs), 0) AS now,
+			COALESCE((SELECT MAX(virtual_finish) FROM jobs WHERE user_id = ?), 0) AS prev`,
+		owner.UserID,
+	).Scan(&tags).Error; err != nil {
+		return false, err
+	}
+	cost := max(owner.WordCount, 1)
+	start := max(tags.Now, tags.Prev)
+
+	job := models.Job{
+		SubmissionID:  submissionID,
+		UserID:        owner.UserID,
+		Plan:          plan,
+		Priority:      policy.Priority,
+		MaxConcurrent: policy.MaxConcurrent,
+		Cost:          cost,
+		VirtualStart:  start,
+		VirtualFinish: start + float64(cost)/policy.Weight,
+		State:         models.JobQueued,
+		MaxAttempts:   q.opts.MaxAttempts,
+		RunAt:         time.Now(),
+	}
+	result := db.Clauses(clause.OnConflict{DoNothing: true}).Create(&job)
+	return result.RowsAffected > 0, result.Error
+}
+
+// policy returns the scheduling policy for a plan, falling back to the free
+// plan's, and the plan it belongs to.
+func (q *JobQueue) policy(plan models.SubscriptionPlan) (models.SubscriptionPlan, PlanPolicy) {
+	policy, ok := q.opts.Plans[plan]
+	if !ok {
+		plan = models.PlanFree
+		policy, ok = q.opts.Plans[plan]
+	}
+	if !ok || policy.Weight <= 0 {
+		policy.Weight = 1
+	}
+	return plan, policy
+}
+
+// Retry requeues a dead job with a fresh set of attempts, putting its
+// failed submission back to pending.
+func (q *JobQueue) Retry(jobID uint) (*models.Job, error) {
+	var job models.Job
+	err := q.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&job, jobID).Error; err != nil {
+			return err
+		}
+		if job.State != models.JobDead {
+			return fmt.Errorf("%w: job is %s", ErrNotDead, job.State)
+		}
+		if err := tx.Model(&models.Submission{}).
+			Where("id = ? AND status = ?", job.SubmissionID, models.StatusFailed).
+			Updates(map[string]interface{}{"status": models.StatusPending, "error": ""}).Error; err != nil {
+			return err
+		}
+
+		job.State, job.Attempts, job.RunAt = models.JobQueued, 0, time.Now()
+		job.LockedBy, job.LockedUntil, job.FinishedAt = "", nil, nil
+		return tx.Select("state", "attempts", "run_at", "locked_by", "locked_until", "finished_at").Save(&job).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	q.notify()
+	return &job, nil
+}
+
+// Cancel finishes the submission's unfinished job as cancelled, using db,
+// which may be a transaction. A job running in this process is stopped at
+// once; one running elsewhere stops when its worker next renews the lease.
+// It returns false if the submission had no unfinished job.
+func (q *JobQueue) Cancel(db *gorm.DB, submissionID uint) (bool, error) {
+	var ids []uint
+	err := db.Raw(`
+		UPDATE jobs SET state = ?, locked_until = NULL, finished_at = ?, updated_at = ?
+		WHERE submission_id = ? AND finished_at IS NULL
+		RETURNING id`,
+		models.JobCancelled, time.Now(), time.Now(), submissionID,
+	).Scan(&ids).Error
+	if err != nil || len(ids) == 0 {
+		return false, err
+	}
+
+	q.mu.Lock()
+	for _, id := range ids {
+		if cancel, ok := q.running[id]; ok {
+			cancel()
+		}
+	}
+	q.mu.Unlock()
+	return true, nil
+}
+
+func (q *JobQueue) notify() {
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Start runs the workers and the sweeper until ctx is cancelled. The
+// sweeper runs once straight away to recover work left behind by a
+// previous process.
+func (q *JobQueue) Start(ctx context.Context) {
+	if q.db == nil {
+		log.Printf("job queue not started: no database")
+		return
+	}
+	go q.sweepLoop(ctx)
+	for i := 0; i < q.opts.Workers; i++ {
+		go q.work(ctx, q.workerID+"/"+strconv.Itoa(i+1))
+	}
+}
+
+// work runs jobs as the named worker. Each worker has its own name so a
+// lapsed lease is never mistaken for one the worker still holds.
+func (q *JobQueue) work(ctx context.Context, worker string) {
+	ticker := time.NewTicker(q.opts.PollInterval)
+	defer ticker.Stop()
+	for {
+		job, err := q.claim(worker)
+		if err != nil {
+			log.Printf("job queue: claim failed: %v", err)
+		}
+		if job != nil {
+			q.run(ctx, worker, job)
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-q.wake:
+		case <-ticker.C:
+		}
+	}
+}
+
+// claim leases the next due job: a queued job whose time has come, or a
+// running job whose lease has lapsed, from an owner below their
+// concurrency cap. Jobs are taken by lane, then by virtual start.
+func (q *JobQueue) claim(worker string) (*models.Job, error) {
+	now := time.Now()
+	topLane := 0
+	for _, policy := range q.opts.Plans {
+		topLane = max(topLane, policy.Priority)
+	}
+
+	var jobs []models.Job
+	err := q.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(?)", claimLock).Error; err != nil {
+			return err
+		}
+		return tx.Raw(`
+			UPDATE jobs SET state = ?, attempts = attempts + 1, locked_by = ?, locked_until = ?,
+				started_at = COALESCE(started_at, ?), updated_at = ?
+			WHERE id = (
+				SELECT j.id FROM jobs j
+				WHERE ((j.state = ? AND j.run_at <= ?) OR (j.state = ? AND j.locked_until < ?))
+				AND (j.max_concurrent = 0 OR j.max_concurrent > (
+					SELECT COUNT(*) FROM jobs r
+					WHERE r.user_id = j.user_id AND r.state = ? AND r.locked_until >= ?
+				))
+				ORDER BY CASE WHEN j.run_at < ? THEN GREATEST(j.priority, ?) ELSE j.priority END DESC,
+					j.virtual_start, j.id
+				FOR UPDATE SKIP LOCKED
+				LIMIT 1
+			)
+			RETURNING *`,
+			models.JobRunning, worker, now.Add(q.opts.Visibility), now, now,
+			models.JobQueued, now, models.JobRunning, now,
+			models.JobRunning, now,
+			now.Add(-q.opts.MaxWait), topLane,
+		).Scan(&jobs).Error
+	})
+	if err != nil || len(jobs) == 0 {
+		return nil, err
+	}
+	job := &jobs[0]
+
+	// A job whose lease lapsed on its last attempt was never finished
+	if job.Attempts > job.MaxAttempts {
+		q.fail(worker, job, errors.New("lease expired on the final attempt"))
+		return nil, nil
+	}
+	return job, nil
+}
+
+func (q *JobQueue) run(ctx context.Context, worker string, job *models.Job) {
+	jobCtx, cancel := context.WithCancel(ctx)
+	q.mu.Lock()
+	q.running[job.ID] = cancel
+	q.mu.Unlock()
+	defer func() {
+		q.mu.Lock()
+		delete(q.running, job.ID)
+		q.mu.Unlock()
+		cancel()
+	}()
+	go q.renew(jobCtx, cancel, worker, job.ID)
+
+	err := q.call(jobCtx, *job)
+	if err == nil && jobCtx.Err() != nil && ctx.Err() == nil {
+		err = ErrLeaseLost
+	}
+	if err != nil {
+		q.fail(worker, job, err)
+		return
+	}
+
+	now := time.Now()
+	if err := q.db.Model(&models.Job{}).
+		Where("id = ? AND locked_by = ? AND state = ?", job.ID, worker, models.JobRunning).
+		Updates(map[string]interface{}{
+			"state":        models.JobDone,
+			"locked_until": nil,
+			"finished_at":  now,
+			"last_error":   "",
+		}).Error; err != nil {
+		log.Printf("job queue: marking job %d done failed: %v", job.ID, err)
+	}
+}
+
+// call runs the handler, turning a panic into an error so one bad job
+// cannot stop a worker.
+func (q *JobQueue) call(ctx context.Context, job models.Job) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return q.handle(ctx, job)
+}
+
+// renew extends the job's lease until ctx ends, cancelling the job if the
+// lease was taken by another worker.
+func (q *JobQueue) renew(ctx context.Context, cancel context.CancelFunc, worker string, jobID uint) {
+	ticker := time.NewTicker(q.opts.Visibility / 3)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			result := q.db.Model(&models.Job{}).
+				Where("id = ? AND locked_by = ? AND state = ?", jobID, worker, models.JobRunning).
+				Update("locked_until", time.Now().Add(q.opts.Visibility))
+			if result.Error == nil && result.RowsAffected == 0 {
+				log.Printf("job queue: lost lease on job %d", jobID)
+				cancel()
+				return
+			}
+		}
+	}
+}
+
+// fail schedules a retry, or moves the job to the dead-letter state once it
+// is out of attempts.
+func (q *JobQueue) fail(worker string, job *models.Job, cause error) {
+	dead := job.Attempts >= job.MaxAttempts
+	updates := map[string]interface{}{
+		"locked_until": nil,
+		"last_error":   cause.Error(),
+	}
+	if dead {
+		now := time.Now()
+		updates["state"] = models.JobDead
+		updates["finished_at"] = now
+		job.State, job.FinishedAt = models.JobDead, &now
+	} else {
+		job.RunAt = time.Now().Add(q.backoff(job.Attempts))
+		updates["state"] = models.JobQueued
+		updates["run_at"] = job.RunAt
+		job.State = models.JobQueued
+	}
+	job.LastError = cause.Error()
+
+	result := q.db.Model(&models.Job{}).
+		Where("id = ? AND locked_by = ? AND state = ?", job.ID, worker, models.JobRunning).
+		Updates(updates)
+	if result.Error != nil {
+		log.Printf("job queue: recording failure of job %d failed: %v", job.ID, result.Error)
+		return
+	}
+	if result.RowsAffected == 0 {
+		// Another worker holds the job now and will report on it
+		return
+	}
+	log.Printf("job queue: job %d (submission %d) attempt %d failed: %v", job.ID, job.SubmissionID, job.Attempts, cause)
+	if q.failed != nil {
+		q.failed(*job, cause, dead)
+	}
+}
+
+// backoff doubles the wait with each attempt, with up to 20% jitter so
+// retries of jobs that failed together spread out.
+func (q *JobQueue) backoff(attempts int) time.Duration {
+	wait := q.opts.BaseBackoff
+	for i := 1; i < attempts && wait < q.opts.MaxBackoff; i++ {
+		wait *= 2
+	}
+	wait = min(wait, q.opts.MaxBackoff)
+	return wait + time.Duration(rand.Int63n(int64(wait)/5+1))
+}
+
+func (q *JobQueue) sweepLoop(ctx context.Context) {
+	ticker := time.NewTicker(q.opts.SweepInterval)
+	defer ticker.Stop()
+	for {
+		if requeued, err := q.Sweep(); err != nil {
+			log.Printf("job queue: sweep failed: %v", err)
+		} else if requeued > 0 {
+			log.Printf("job queue: requeued %d orphaned submissions", requeued)
+			q.notify()
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Sweep queues a job for every pending or processing submission that has
+// no unfinished job, such as one whose process stopped before enqueueing
+// it, and removes done jobs past their retention. It returns the number of
+// submissions requeued.
+func (q *JobQueue) Sweep() (int64, error) {
+	now := time.Now()
+	var orphans []uint
+	if err := q.db.Raw(`
+		SELECT s.id FROM submissions s
+		WHERE s.status IN ? AND s.deleted_at IS NULL AND s.created_at < ?
+		AND NOT EXISTS (SELECT 1 FROM jobs j WHERE j.submission_id = s.id AND j.finished_at IS NULL)
+		ORDER BY s.id`,
+		[]models.SubmissionStatus{models.StatusPending, models.StatusProcessing}, now.Add(-orphanGrace),
+	).Scan(&orphans).Error; err != nil {
+		return 0, err
+	}
+
+	var requeued int64
+	for _, id := range orphans {
+		created, err := q.enqueue(q.db, id)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			continue // owner deleted
+		}
+		if err != nil {
+			return requeued, err
+		}
+		if created {
+			requeued++
+		}
+	}
+
+	if err := q.db.Where("state = ? AND finished_at < ?", models.JobDone, now.Add(-q.opts.Retention)).
+		Delete(&models.Job{}).Error; err != nil {
+		return requeued, err
+	}
+	return requeued, nil
+}
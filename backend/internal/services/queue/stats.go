@@ -0,0 +1,148 @@
+package queue
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+	"time"
+
+	"tamil-proofreading-platform/backend/internal/models"
+)
+
+// statsWindow is how far back started jobs are counted for wait times.
+const statsWindow = time.Hour
+
+// PlanStats describes the queue for one subscription plan. Waits are in
+// seconds; OldestWait is how long the oldest due job has been waiting, and
+// the average and 95th percentile cover jobs started in the last hour.
+type PlanStats struct {
+	Plan          models.SubscriptionPlan `json:"plan"`
+	Priority      int                     `json:"priority"`
+	Weight        float64                 `json:"weight"`
+	MaxConcurrent int                     `json:"max_concurrent"`
+	Queued        int64                   `json:"queued"`
+	Due           int64                   `json:"due"`
+	Running       int64                   `json:"running"`
+	OldestWait    float64                 `json:"oldest_wait_seconds"`
+	Started       int64                   `json:"started_last_hour"`
+	AvgWait       float64                 `json:"avg_wait_seconds"`
+	P95Wait       float64                 `json:"p95_wait_seconds"`
+}
+
+// UserStats is one user's share of the queue.
+type UserStats struct {
+	UserID  uint                    `json:"user_id"`
+	Plan    models.SubscriptionPlan `json:"plan"`
+	Queued  int64                   `json:"queued"`
+	Running int64                   `json:"running"`
+	Words   int64                   `json:"queued_words"`
+}
+
+// Stats is a snapshot of the queue's depth and wait times.
+type Stats struct {
+	Workers int         `json:"workers_per_process"`
+	Plans   []PlanStats `json:"plans"`
+	// Users lists the users with the most unfinished jobs.
+	Users []UserStats `json:"users"`
+}
+
+// Stats reports queue depth and wait time by plan and the busiest users.
+func (q *JobQueue) Stats(users int) (*Stats, error) {
+	now := time.Now()
+
+	var depth []struct {
+		Plan    models.SubscriptionPlan
+		Queued  int64
+		Due     int64
+		Running int64
+		Oldest  *time.Time
+	}
+	if err := q.db.Model(&models.Job{}).
+		Select(`plan,
+			COUNT(*) FILTER (WHERE state = ?) AS queued,
+			COUNT(*) FILTER (WHERE state = ? AND run_at <= ?) AS due,
+			COUNT(*) FILTER (WHERE state = ?) AS running,
+			MIN(run_at) FILTER (WHERE state = ? AND run_at <= ?) AS oldest`,
+			models.JobQueued, models.JobQueued, now, models.JobRunning, models.JobQueued, now).
+		Where("finished_at IS NULL").
+		Group("plan").
+		Scan(&depth).Error; err != nil {
+		return nil, err
+	}
+
+	var waits []struct {
+		Plan    models.SubscriptionPlan
+		Started int64
+		Avg     float64
+		P95     float64
+	}
+	if err := q.db.Model(&models.Job{}).
+		Select(`plan, COUNT(*) AS started,
+			AVG(EXTRACT(EPOCH FROM started_at - created_at)) AS avg,
+			percentile_cont(0.95) WITHIN GROUP (ORDER BY EXTRACT(EPOCH FROM started_at - created_at)) AS p95`).
+		Where("started_at >= ?", now.Add(-statsWindow)).
+		Group("plan").
+		Scan(&waits).Error; err != nil {
+		return nil, err
+	}
+
+	lanes := make(map[models.SubscriptionPlan]*PlanStats)
+	lane := func(plan models.SubscriptionPlan) *PlanStats {
+		if plan == "" {
+			plan = models.PlanFree
+		}
+		if l, ok := lanes[plan]; ok {
+			return l
+		}
+		_, policy := q.policy(plan)
+		l := &PlanStats{Plan: plan, Priority: policy.Priority, Weight: policy.Weight, MaxConcurrent: policy.MaxConcurrent}
+		lanes[plan] = l
+		return l
+	}
+	for plan := range q.opts.Plans {
+		lane(plan)
+	}
+	for _, d := range depth {
+		l := lane(d.Plan)
+		l.Queued += d.Queued
+		l.Due += d.Due
+		l.Running += d.Running
+		if d.Oldest != nil {
+			l.OldestWait = max(l.OldestWait, now.Sub(*d.Oldest).Seconds())
+		}
+	}
+	for _, w := range waits {
+		l := lane(w.Plan)
+		l.Started, l.AvgWait, l.P95Wait = w.Started, w.Avg, w.P95
+	}
+
+	stats := &Stats{Workers: q.opts.Workers, Plans: make([]PlanStats, 0, len(lanes))}
+	for _, l := range lanes {
+		stats.Plans = append(stats.Plans, *l)
+	}
+	// Top lanes first, then heavier plans
+	slices.SortFunc(stats.Plans, func(a, b PlanStats) int {
+		if a.Priority != b.Priority {
+			return b.Priority - a.Priority
+		}
+		if a.Weight != b.Weight {
+			return cmp.Compare(b.Weight, a.Weight)
+		}
+		return strings.Compare(string(a.Plan), string(b.Plan))
+	})
+
+	if err := q.db.Model(&models.Job{}).
+		Select(`user_id, MAX(plan) AS plan,
+			COUNT(*) FILTER (WHERE state = ?) AS queued,
+			COUNT(*) FILTER (WHERE state = ?) AS running,
+			COALESCE(SUM(cost) FILTER (WHERE state = ?), 0) AS words`,
+			models.JobQueued, models.JobRunning, models.JobQueued).
+		Where("finished_at IS NULL").
+		Group("user_id").
+		Order("COUNT(*) DESC, user_id").
+		Limit(users).
+		Scan(&stats.Users).Error; err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
@@ -0,0 +1,229 @@
+package rules
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"tamil-proofreading-platform/backend/internal/models"
+	"tamil-proofreading-platform/backend/internal/services/llm"
+)
+
+// TypeCodeMix marks English words, in Latin or Tamil script, inside Tamil
+// text.
+const TypeCodeMix = "code_mix"
+
+// CodeMixToken is one detected English word.
+type CodeMixToken struct {
+	Text  string
+	Start int
+	End   int
+	// Latin is set for words in Latin script; otherwise the word is
+	// transliterated English in Tamil script.
+	Latin   bool
+	Mapping *models.CodeMixMapping
+	// Suffix is the case ending after a mapped spelling, as in மீட்டிங்கில்.
+	// Strip is the spelling's final pulli when the suffix starts with a
+	// vowel sign that replaced it.
+	Suffix string
+	Strip  string
+}
+
+// CodeMixChecker finds code-mixed English. Mapped words always get their
+// Tamil equivalent suggested; with FlagUnmapped, other detected words are
+// flagged too.
+type CodeMixChecker struct {
+	byEnglish    map[string]*models.CodeMixMapping
+	bySpelling   map[string]*models.CodeMixMapping
+	skip         map[string]bool
+	flagUnmapped bool
+}
+
+// NewCodeMixChecker indexes the active mappings. Words in skip (such as the
+// user's dictionary) are never reported.
+func NewCodeMixChecker(mappings []models.CodeMixMapping, skip []string, flagUnmapped bool) *CodeMixChecker {
+	c := &CodeMixChecker{
+		byEnglish:    make(map[string]*models.CodeMixMapping, len(mappings)),
+		bySpelling:   make(map[string]*models.CodeMixMapping, len(mappings)),
+		skip:         make(map[string]bool, len(skip)),
+		flagUnmapped: flagUnmapped,
+	}
+	for i := range mappings {
+		m := &mappings[i]
+		if !m.IsActive || len(m.Equivalents) == 0 {
+			continue
+		}
+		c.byEnglish[strings.ToLower(m.English)] = m
+		for _, s := range m.Spellings {
+			c.bySpelling[s] = m
+		}
+	}
+	for _, w := range skip {
+		c.skip[strings.ToLower(w)] = true
+	}
+	return c
+}
+
+// Detect returns the code-mixed words in text. Latin words are only
+// reported in text that also contains Tamil.
+func (c *CodeMixChecker) Detect(text string) []CodeMixToken {
+	tamil := strings.IndexFunc(text, func(r rune) bool { return unicode.Is(unicode.Tamil, r) }) >= 0
+	if !tamil {
+		return nil
+	}
+
+	var tokens []CodeMixToken
+	for _, w := range words(text) {
+		word := text[w.Start:w.End]
+		if c.skip[strings.ToLower(word)] {
+			continue
+		}
+
+		first, _ := utf8.DecodeRuneInString(word)
+		if first < utf8.RuneSelf {
+			if !isEnglishWord(word) {
+				continue
+			}
+			tokens = append(tokens, CodeMixToken{
+				Text: word, Start: w.Start, End: w.End, Latin: true,
+				Mapping: c.byEnglish[strings.ToLower(word)],
+			})
+			continue
+		}
+
+		if m := c.bySpelling[word]; m != nil {
+			tokens = append(tokens, CodeMixToken{Text: word, Start: w.Start, End: w.End, Mapping: m})
+			continue
+		}
+		if m, stem, strip := c.inflectedSpelling(word); m != nil {
+			tokens = append(tokens, CodeMixToken{
+				Text: word, Start: w.Start, End: w.End, Mapping: m,
+				Suffix: word[len(stem):], Strip: strip,
+			})
+			continue
+		}
+		if LooksTransliterated(word) {
+			tokens = append(tokens, CodeMixToken{Text: word, Start: w.Start, End: w.End})
+		}
+	}
+	return tokens
+}
+
+func (c *CodeMixChecker) Check(text string) []llm.Suggestion {
+	var found []llm.Suggestion
+	for _, t := range c.Detect(text) {
+		if t.Mapping == nil && !c.flagUnmapped {
+			continue
+		}
+		s := llm.Suggestion{
+			Original:   t.Text,
+			Corrected:  t.Text,
+			Reason:     "ஆங்கிலச் சொல் கலப்பு; தமிழ்ச் சொல்லைப் பயன்படுத்தவும்",
+			Type:       TypeCodeMix,
+			StartIndex: t.Start,
+			EndIndex:   t.End,
+		}
+		if t.Mapping != nil {
+			s.Reason = "ஆங்கிலச் சொல் (" + t.Mapping.English + "); தமிழில்: " + strings.Join(t.Mapping.Equivalents, ", ")
+			with := t.Mapping.Equivalents[0]
+			switch {
+			case t.Suffix == "":
+				s.Corrected = with
+			case t.Strip != "" && strings.HasSuffix(with, t.Strip):
+				// The equivalent takes the vowel-sign suffix the same way.
+				s.Corrected = strings.TrimSuffix(with, t.Strip) + t.Suffix
+			}
+			// Otherwise the suffix cannot be moved over mechanically, so
+			// the inflected word is flagged for a manual fix.
+		}
+		found = append(found, s)
+	}
+	return found
+}
+
+// inflectedSpelling matches a mapped spelling followed by a case suffix,
+// e.g. மீட்டிங்கில், or a pulli-final spelling whose pulli became a vowel
+// sign, e.g. கம்ப்யூட்டரில். It returns the longest matching stem and, for
+// the vowel-sign form, the pulli that was stripped.
+func (c *CodeMixChecker) inflectedSpelling(word string) (*models.CodeMixMapping, string, string) {
+	var match *models.CodeMixMapping
+	var stem, strip string
+	for spelling, m := range c.bySpelling {
+		if len(word) > len(spelling) && strings.HasPrefix(word, spelling) {
+			if len(spelling) > len(stem) || (len(spelling) == len(stem) && strip != "") {
+				match, stem, strip = m, spelling, ""
+			}
+			continue
+		}
+		if !strings.HasSuffix(spelling, pulli) {
+			continue
+		}
+		base := strings.TrimSuffix(spelling, pulli)
+		if len(base) <= len(stem) || !strings.HasPrefix(word, base) {
+			continue
+		}
+		if r, _ := utf8.DecodeRuneInString(word[len(base):]); isTamilVowelSign(r) {
+			match, stem, strip = m, base, pulli
+		}
+	}
+	return match, stem, strip
+}
+
+// words splits text into word spans.
+func words(text string) []span {
+	var spans []span
+	for i := 0; i < len(text); {
+		r, size := utf8.DecodeRuneInString(text[i:])
+		if !isWordRune(r) {
+			i += size
+			continue
+		}
+		start := i
+		latin := r < utf8.RuneSelf
+		for i < len(text) {
+			r, size := utf8.DecodeRuneInString(text[i:])
+			// A script change ends the word, so "Zoomல்" splits in two.
+			if !isWordRune(r) || (r < utf8.RuneSelf) != latin {
+				break
+			}
+			i += size
+		}
+		spans = append(spans, span{Start: start, End: i})
+	}
+	return spans
+}
+
+// isEnglishWord accepts Latin words of three or more letters that are not
+// all-caps acronyms.
+func isEnglishWord(word string) bool {
+	if len(word) < 3 {
+		return false
+	}
+	lower := false
+	for _, r := range word {
+		if !unicode.IsLetter(r) {
+			return false
+		}
+		lower = lower || unicode.IsLower(r)
+	}
+	return lower
+}
+
+// LooksTransliterated applies spelling heuristics for English written in
+// Tamil script: native words never start with a consonant cluster or ட, and
+// rarely use ஃப or end in -ிங் (-ing) or -ஷன் (-tion).
+func LooksTransliterated(word string) bool {
+	if utf8.RuneCountInString(word) < 3 || strings.HasPrefix(word, "ஸ்ரீ") {
+		return false
+	}
+	first, size := utf8.DecodeRuneInString(word)
+	if second, _ := utf8.DecodeRuneInString(word[size:]); second == '\u0bcd' {
+		return true
+	}
+	if first == 'ட' {
+		return true
+	}
+	return strings.Contains(word, "ஃப") ||
+		strings.HasSuffix(word, "ிங்") ||
+		strings.HasSuffix(word, "ஷன்")
+}
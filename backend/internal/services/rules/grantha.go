@@ -0,0 +1,106 @@
+package rules
+
+import (
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"tamil-proofreading-platform/backend/internal/models"
+	"tamil-proofreading-platform/backend/internal/services/llm"
+)
+
+// TypeGrantha marks grantha letters and Sanskrit-origin words with pure
+// Tamil alternatives.
+const TypeGrantha = "grantha"
+
+// GranthaChecker suggests pure Tamil alternatives from the mapping table
+// and, with FlagLetters set, flags any other word containing grantha letters.
+type GranthaChecker struct {
+	mappings    []models.GranthaMapping
+	flagLetters bool
+}
+
+// NewGranthaChecker uses the active mappings, longest word first.
+func NewGranthaChecker(mappings []models.GranthaMapping, flagLetters bool) *GranthaChecker {
+	c := &GranthaChecker{flagLetters: flagLetters}
+	for _, m := range mappings {
+		if m.IsActive && m.Word != "" && len(m.Alternatives) > 0 {
+			c.mappings = append(c.mappings, m)
+		}
+	}
+	sort.SliceStable(c.mappings, func(i, j int) bool { return len(c.mappings[i].Word) > len(c.mappings[j].Word) })
+	return c
+}
+
+func (c *GranthaChecker) Check(text string) []llm.Suggestion {
+	var found []llm.Suggestion
+	for _, m := range c.mappings {
+		reason := "தனித்தமிழ்: " + strings.Join(m.Alternatives, ", ")
+		if m.Note != "" {
+			reason += " (" + m.Note + ")"
+		}
+		for _, sp := range findTerm(text, m.Word, true) {
+			s := replacement(text, sp, m.Alternatives[0], reason, TypeGrantha)
+			if !overlapsAny(found, s) {
+				found = append(found, s)
+			}
+		}
+	}
+
+	if c.flagLetters {
+		for _, sp := range granthaWords(text) {
+			s := llm.Suggestion{
+				Original:   text[sp.Start:sp.End],
+				Corrected:  text[sp.Start:sp.End],
+				Reason:     "கிரந்த எழுத்து உள்ளது; தனித்தமிழ் வடிவத்தைப் பயன்படுத்தவும்",
+				Type:       TypeGrantha,
+				StartIndex: sp.Start,
+				EndIndex:   sp.End,
+			}
+			if !overlapsAny(found, s) {
+				found = append(found, s)
+			}
+		}
+	}
+	return found
+}
+
+// isGrantha reports whether r is one of ஜ ஶ ஷ ஸ ஹ. க்ஷ is covered by ஷ.
+func isGrantha(r rune) bool {
+	switch r {
+	case 'ஜ', 'ஶ', 'ஷ', 'ஸ', 'ஹ':
+		return true
+	}
+	return false
+}
+
+// HasGrantha reports whether text contains a grantha letter.
+func HasGrantha(text string) bool {
+	return strings.IndexFunc(text, isGrantha) >= 0
+}
+
+// granthaWords returns the spans of words containing grantha letters.
+func granthaWords(text string) []span {
+	var spans []span
+	for i := 0; i < len(text); {
+		r, size := utf8.DecodeRuneInString(text[i:])
+		if !isWordRune(r) {
+			i += size
+			continue
+		}
+		start := i
+		grantha := false
+		for i < len(text) {
+			r, size := utf8.DecodeRuneInString(text[i:])
+			if !isWordRune(r) {
+				break
+			}
+			grantha = grantha || isGrantha(r)
+			i += size
+		}
+		if grantha {
+			spans = append(spans, span{Start: start, End: i})
+		}
+	}
+	return spans
+}
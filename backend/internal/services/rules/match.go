@@ -0,0 +1,107 @@
+package rules
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const pulli = "\u0bcd"
+
+// mFinal is the ம் ending that becomes த்த before case suffixes, as in
+// ஜனநாயகம் → ஜனநாயகத்தில்.
+const mFinal = "ம\u0bcd"
+
+// span is a byte range in the checked text. Inflected is set when the term
+// matched in an inflected form with its ending Strip removed: a final pulli
+// replaced by a vowel sign (கம்ப்யூட்டர் → கம்ப்யூட்டரில்) or a final ம்
+// replaced by த்த (ஜனநாயகம் → ஜனநாயகத்தில்).
+type span struct {
+	Start     int
+	End       int
+	Inflected bool
+	Strip     string
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsMark(r) || unicode.IsDigit(r) ||
+		r == '\u200c' || r == '\u200d'
+}
+
+func isTamilVowelSign(r rune) bool {
+	return r >= '\u0bbe' && r <= '\u0bcc'
+}
+
+// findTerm returns the occurrences of term in text that start at a word
+// boundary. With stem set the term may be followed by suffixes; otherwise it
+// must end at a word boundary too.
+func findTerm(text, term string, stem bool) []span {
+	if term == "" {
+		return nil
+	}
+
+	var spans []span
+	scan(text, term, func(start, end int) {
+		if !stem && end < len(text) {
+			if r, _ := utf8.DecodeRuneInString(text[end:]); isWordRune(r) {
+				return
+			}
+		}
+		spans = append(spans, span{Start: start, End: end})
+	})
+
+	if !stem || utf8.RuneCountInString(term) < 3 {
+		return spans
+	}
+	if strings.HasSuffix(term, pulli) {
+		base := strings.TrimSuffix(term, pulli)
+		scan(text, base, func(start, end int) {
+			if end < len(text) {
+				if r, _ := utf8.DecodeRuneInString(text[end:]); isTamilVowelSign(r) {
+					spans = append(spans, span{Start: start, End: end, Inflected: true, Strip: pulli})
+				}
+			}
+		})
+	}
+	if strings.HasSuffix(term, mFinal) {
+		base := strings.TrimSuffix(term, mFinal)
+		scan(text, base, func(start, end int) {
+			if strings.HasPrefix(text[end:], "த\u0bcdத") {
+				spans = append(spans, span{Start: start, End: end, Inflected: true, Strip: mFinal})
+			}
+		})
+	}
+	return spans
+}
+
+// scan calls fn for each occurrence of pattern preceded by a word boundary.
+func scan(text, pattern string, fn func(start, end int)) {
+	for offset := 0; offset < len(text); {
+		i := strings.Index(text[offset:], pattern)
+		if i < 0 {
+			return
+		}
+		start := offset + i
+		end := start + len(pattern)
+		offset = end
+
+		if start > 0 {
+			if r, _ := utf8.DecodeLastRuneInString(text[:start]); isWordRune(r) {
+				continue
+			}
+		}
+		fn(start, end)
+	}
+}
+
+// wordEnd returns the end of the word containing position i.
+func wordEnd(text string, i int) int {
+	for i < len(text) {
+		r, size := utf8.DecodeRuneInString(text[i:])
+		if !isWordRune(r) {
+			break
+		}
+		i += size
+	}
+	return i
+}
@@ -0,0 +1,106 @@
+package rules
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"tamil-proofreading-platform/backend/internal/models"
+	"tamil-proofreading-platform/backend/internal/services/llm"
+)
+
+// TypePunctuation marks punctuation that breaks the profile's conventions.
+const TypePunctuation = "punctuation"
+
+// PunctuationChecker enforces quote style and spacing around punctuation.
+type PunctuationChecker struct {
+	Quotes                models.QuoteStyle
+	AllowSpaceBeforePunct bool
+}
+
+func (p PunctuationChecker) Check(text string) []llm.Suggestion {
+	var found []llm.Suggestion
+	if !p.AllowSpaceBeforePunct {
+		found = append(found, spaceBeforePunct(text)...)
+	}
+	switch p.Quotes {
+	case models.QuotesCurly:
+		found = append(found, straightQuotes(text)...)
+	case models.QuotesStraight:
+		found = append(found, curlyQuotes(text)...)
+	}
+	return found
+}
+
+func spaceBeforePunct(text string) []llm.Suggestion {
+	var found []llm.Suggestion
+	for i := 0; i < len(text); i++ {
+		if text[i] != ' ' {
+			continue
+		}
+		j := i
+		for j < len(text) && text[j] == ' ' {
+			j++
+		}
+		if j < len(text) && strings.IndexByte(",.?!;:", text[j]) >= 0 && i > 0 && text[i-1] != '\n' {
+			// "..." after a space starts an ellipsis and is left alone.
+			if text[j] == '.' && strings.HasPrefix(text[j:], "...") {
+				i = j
+				continue
+			}
+			found = append(found, llm.Suggestion{
+				Original:   text[i : j+1],
+				Corrected:  text[j : j+1],
+				Reason:     "நிறுத்தக்குறிக்கு முன் இடைவெளி தேவையில்லை",
+				Type:       TypePunctuation,
+				StartIndex: i,
+				EndIndex:   j + 1,
+			})
+		}
+		i = j - 1
+	}
+	return found
+}
+
+// straightQuotes pairs " marks in order, suggesting curly replacements.
+func straightQuotes(text string) []llm.Suggestion {
+	var found []llm.Suggestion
+	open := true
+	for i := 0; i < len(text); i++ {
+		if text[i] != '"' {
+			continue
+		}
+		corrected := "“"
+		if !open {
+			corrected = "”"
+		}
+		open = !open
+		found = append(found, llm.Suggestion{
+			Original:   `"`,
+			Corrected:  corrected,
+			Reason:     "வளைந்த மேற்கோள் குறியைப் பயன்படுத்தவும்",
+			Type:       TypePunctuation,
+			StartIndex: i,
+			EndIndex:   i + 1,
+		})
+	}
+	return found
+}
+
+func curlyQuotes(text string) []llm.Suggestion {
+	var found []llm.Suggestion
+	for i := 0; i < len(text); {
+		r, size := utf8.DecodeRuneInString(text[i:])
+		if r == '“' || r == '”' {
+			found = append(found, llm.Suggestion{
+				Original:   text[i : i+size],
+				Corrected:  `"`,
+				Reason:     "நேர் மேற்கோள் குறியைப் பயன்படுத்தவும்",
+				Type:       TypePunctuation,
+				StartIndex: i,
+				EndIndex:   i + size,
+			})
+		}
+		i += size
+	}
+	return found
+}
@@ -0,0 +1,57 @@
+package rules
+
+import (
+	"tamil-proofreading-platform/backend/internal/services/llm"
+)
+
+// TypeRegister marks spoken forms found in text that should be written.
+const TypeRegister = "register"
+
+// colloquialForms maps common spoken (பேச்சுத்தமிழ்) forms to their written
+// equivalents.
+var colloquialForms = map[string]string{
+	"இருக்கு":     "இருக்கிறது",
+	"இல்ல":        "இல்லை",
+	"வேணும்":      "வேண்டும்",
+	"வேணாம்":      "வேண்டாம்",
+	"முடியல":      "முடியவில்லை",
+	"தெரியல":      "தெரியவில்லை",
+	"போறேன்":      "போகிறேன்",
+	"வரேன்":       "வருகிறேன்",
+	"சொல்றேன்":    "சொல்கிறேன்",
+	"பண்ணு":       "செய்",
+	"பண்ணினான்":   "செய்தான்",
+	"பண்ணினேன்":   "செய்தேன்",
+	"எங்க":        "எங்கே",
+	"அங்க":        "அங்கே",
+	"இங்க":        "இங்கே",
+	"ரொம்ப":       "மிகவும்",
+	"அவங்க":       "அவர்கள்",
+	"இவங்க":       "இவர்கள்",
+	"நீங்க":       "நீங்கள்",
+	"நாங்க":       "நாங்கள்",
+	"எல்லாரும்":   "எல்லோரும்",
+	"இப்ப":        "இப்போது",
+	"அப்ப":        "அப்போது",
+	"பார்க்கறேன்": "பார்க்கிறேன்",
+}
+
+// RegisterChecker flags spoken forms in text written to a formal register.
+type RegisterChecker struct{}
+
+func (RegisterChecker) Check(text string) []llm.Suggestion {
+	var found []llm.Suggestion
+	for spoken, written := range colloquialForms {
+		for _, sp := range findTerm(text, spoken, false) {
+			found = append(found, llm.Suggestion{
+				Original:   spoken,
+				Corrected:  written,
+				Reason:     "பேச்சு வழக்குச் சொல்; எழுத்து வழக்கில் \"" + written + "\" பயன்படுத்தவும்",
+				Type:       TypeRegister,
+				StartIndex: sp.Start,
+				EndIndex:   sp.End,
+			})
+		}
+	}
+	return found
+}
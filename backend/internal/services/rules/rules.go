@@ -0,0 +1,79 @@
+// Package rules runs deterministic checks over submission text. Checkers
+// return suggestions in the same shape as the LLM so both can be stored and
+// shown together.
+package rules
+
+import (
+	"sort"
+	"strings"
+
+	"tamil-proofreading-platform/backend/internal/services/llm"
+)
+
+// Checker finds issues in text. Suggestion offsets are byte offsets into the
+// text, matching the LLM suggestions.
+type Checker interface {
+	Check(text string) []llm.Suggestion
+}
+
+// Run applies checkers in order. When two suggestions overlap, the one from
+// the earlier checker is kept.
+func Run(text string, checkers ...Checker) []llm.Suggestion {
+	var kept []llm.Suggestion
+	for _, checker := range checkers {
+		if checker == nil {
+			continue
+		}
+		for _, s := range checker.Check(text) {
+			if !overlapsAny(kept, s) {
+				kept = append(kept, s)
+			}
+		}
+	}
+	sort.SliceStable(kept, func(i, j int) bool { return kept[i].StartIndex < kept[j].StartIndex })
+	return kept
+}
+
+// Merge adds rule suggestions to an LLM result. LLM suggestions for the same
+// original text are replaced, and rule corrections are applied to the
+// corrected text. It returns the number of suggestions added.
+func Merge(result *llm.ProofreadResult, found []llm.Suggestion) int {
+	if result == nil || len(found) == 0 {
+		return 0
+	}
+
+	originals := make(map[string]bool, len(found))
+	for _, s := range found {
+		originals[s.Original] = true
+	}
+	kept := result.Suggestions[:0]
+	for _, s := range result.Suggestions {
+		if !originals[s.Original] {
+			kept = append(kept, s)
+		}
+	}
+	result.Suggestions = kept
+
+	corrected := result.CorrectedText
+	for _, s := range found {
+		if s.Corrected != s.Original {
+			corrected = strings.Replace(corrected, s.Original, s.Corrected, 1)
+		}
+	}
+	result.CorrectedText = corrected
+
+	result.Suggestions = append(result.Suggestions, found...)
+	sort.SliceStable(result.Suggestions, func(i, j int) bool {
+		return result.Suggestions[i].StartIndex < result.Suggestions[j].StartIndex
+	})
+	return len(found)
+}
+
+func overlapsAny(kept []llm.Suggestion, s llm.Suggestion) bool {
+	for _, k := range kept {
+		if s.StartIndex < k.EndIndex && k.StartIndex < s.EndIndex {
+			return true
+		}
+	}
+	return false
+}
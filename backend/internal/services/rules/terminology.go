@@ -0,0 +1,106 @@
+package rules
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"tamil-proofreading-platform/backend/internal/models"
+	"tamil-proofreading-platform/backend/internal/services/llm"
+)
+
+// TypeTerminology marks suggestions from organization glossaries.
+const TypeTerminology = "terminology"
+
+type termPattern struct {
+	match       string
+	replacement string
+	stem        bool
+	reason      string
+}
+
+// TerminologyChecker flags glossary variants and forbidden terms.
+type TerminologyChecker struct {
+	patterns []termPattern
+}
+
+// NewTerminologyChecker compiles glossary terms. Longer patterns are tried
+// first so a multi-word term wins over a single word inside it.
+func NewTerminologyChecker(terms []models.GlossaryTerm) *TerminologyChecker {
+	c := &TerminologyChecker{}
+	for _, t := range terms {
+		stem := t.MatchMode == models.MatchStem
+		switch t.Kind {
+		case models.TermForbidden:
+			reason := t.Note
+			if reason == "" && t.Replacement != "" {
+				reason = fmt.Sprintf("நிறுவன நடைப்படி \"%s\" தவிர்க்கப்பட வேண்டும்; \"%s\" பயன்படுத்தவும்", t.Term, t.Replacement)
+			} else if reason == "" {
+				reason = fmt.Sprintf("நிறுவன நடைப்படி \"%s\" தவிர்க்கப்பட வேண்டும்", t.Term)
+			}
+			c.patterns = append(c.patterns, termPattern{match: t.Term, replacement: t.Replacement, stem: stem, reason: reason})
+		default:
+			reason := t.Note
+			if reason == "" {
+				reason = fmt.Sprintf("நிறுவன நடைப்படி \"%s\" என்பதைப் பயன்படுத்தவும்", t.Term)
+			}
+			for _, v := range t.Variants {
+				c.patterns = append(c.patterns, termPattern{match: v, replacement: t.Term, stem: stem, reason: reason})
+			}
+		}
+	}
+	sort.SliceStable(c.patterns, func(i, j int) bool { return len(c.patterns[i].match) > len(c.patterns[j].match) })
+	return c
+}
+
+// Empty reports whether there are no terms to check.
+func (c *TerminologyChecker) Empty() bool {
+	return c == nil || len(c.patterns) == 0
+}
+
+func (c *TerminologyChecker) Check(text string) []llm.Suggestion {
+	if c.Empty() {
+		return nil
+	}
+
+	var found []llm.Suggestion
+	for _, p := range c.patterns {
+		for _, sp := range findTerm(text, p.match, p.stem) {
+			s := replacement(text, sp, p.replacement, p.reason, TypeTerminology)
+			if !overlapsAny(found, s) {
+				found = append(found, s)
+			}
+		}
+	}
+	return found
+}
+
+// replacement builds a suggestion replacing a matched span. An empty
+// replacement flags the match without changing it.
+func replacement(text string, sp span, with, reason, kind string) llm.Suggestion {
+	s := llm.Suggestion{
+		Original:   text[sp.Start:sp.End],
+		Corrected:  with,
+		Reason:     reason,
+		Type:       kind,
+		StartIndex: sp.Start,
+		EndIndex:   sp.End,
+	}
+	if sp.Inflected {
+		if strings.HasSuffix(with, sp.Strip) {
+			// The replacement inflects the same way, so the suffix that
+			// follows the span fits it too.
+			s.Corrected = strings.TrimSuffix(with, sp.Strip)
+		} else {
+			// The replacement cannot take the suffix mechanically, so
+			// flag the whole inflected word for a manual fix.
+			s.EndIndex = wordEnd(text, sp.End)
+			s.Original = text[s.StartIndex:s.EndIndex]
+			s.Corrected = ""
+		}
+	}
+	if s.Corrected == "" {
+		s.Corrected = s.Original
+	}
+	return s
+}
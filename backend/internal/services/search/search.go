@@ -0,0 +1,354 @@
+// Package search finds a user's submissions by their original or proofread
+// text. Postgres has no text search configuration for Tamil, and its word
+// parser can split Tamil words at vowel signs, so matching is by substring
+// on trigram indexes instead: every search term must occur in one of the
+// texts, and matches are ranked by trigram word similarity to the query.
+// Results are paged with opaque cursors rather than offsets, so pages stay
+// consistent while new submissions arrive.
+package search
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"tamil-proofreading-platform/backend/internal/models"
+	"tamil-proofreading-platform/backend/internal/services/library"
+
+	"gorm.io/gorm"
+)
+
+// Sort orders.
+const (
+	SortRelevance = "relevance"
+	SortNewest    = "newest"
+)
+
+// Archived filter values.
+const (
+	ArchivedExclude = "false"
+	ArchivedOnly    = "true"
+	ArchivedAll     = "all"
+)
+
+const (
+	// MaxTerms bounds the terms of one query.
+	MaxTerms = 8
+	// snippetRunes is roughly how much text a snippet shows around the
+	// first match.
+	snippetRunes = 160
+)
+
+var (
+	ErrInvalidCursor = errors.New("invalid cursor")
+	ErrTooManyTerms  = errors.New("too many search terms")
+)
+
+// Migrate enables pg_trgm and creates the trigram indexes search relies
+// on. It is safe to run on every start.
+func Migrate(db *gorm.DB) error {
+	for _, stmt := range []string{
+		"CREATE EXTENSION IF NOT EXISTS pg_trgm",
+		"CREATE INDEX IF NOT EXISTS idx_submissions_original_text_trgm ON submissions USING gin (original_text gin_trgm_ops)",
+		"CREATE INDEX IF NOT EXISTS idx_submissions_proofread_text_trgm ON submissions USING gin (proofread_text gin_trgm_ops)",
+	} {
+		if err := db.Exec(stmt).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Query is one search. Zero values mean no filter.
+type Query struct {
+	Text     string
+	Statuses []models.SubmissionStatus
+	// Model matches either the model tier or the requested Gemini model.
+	Model    string
+	From     *time.Time
+	To       *time.Time
+	Archived string
+	// Filter narrows by folder, tags and metadata.
+	Filter library.Filter
+	// Sort defaults to relevance when there is text to rank by and to
+	// newest otherwise.
+	Sort   string
+	Limit  int
+	Cursor string
+}
+
+// Snippet is an excerpt of a matching text. Highlights are the [start, end)
+// rune offsets of the matched terms within Text.
+type Snippet struct {
+	Field      string   `json:"field"`
+	Text       string   `json:"text"`
+	Highlights [][2]int `json:"highlights"`
+}
+
+// Result is one matching submission.
+type Result struct {
+	Submission models.Submission `json:"submission"`
+	Rank       float64           `json:"rank,omitempty"`
+	Snippets   []Snippet         `json:"snippets,omitempty"`
+}
+
+// Page is one page of results. NextCursor is empty on the last page.
+type Page struct {
+	Results    []Result `json:"results"`
+	Sort       string   `json:"sort"`
+	NextCursor string   `json:"next_cursor,omitempty"`
+}
+
+// cursor is the position after the last result of a page.
+type cursor struct {
+	Sort      string    `json:"s"`
+	Rank      float64   `json:"r,omitempty"`
+	CreatedAt time.Time `json:"t,omitempty"`
+	ID        uint      `json:"i"`
+}
+
+type SearchService struct {
+	db *gorm.DB
+}
+
+func NewSearchService(db *gorm.DB) *SearchService {
+	return &SearchService{db: db}
+}
+
+// hit is a submission with its rank, as scanned from the database.
+type hit struct {
+	models.Submission
+	Rank float64 `gorm:"column:search_rank"`
+}
+
+// rankExpr scores a submission against the query text by the better of
+// its two texts.
+const rankExpr = "GREATEST(word_similarity(?, original_text), word_similarity(?, proofread_text))::float8"
+
+// Search returns one page of the user's submissions matching q.
+func (s *SearchService) Search(userID uint, q Query) (*Page, error) {
+	terms := Terms(q.Text)
+	if len(terms) > MaxTerms {
+		return nil, ErrTooManyTerms
+	}
+	text := strings.Join(terms, " ")
+	if q.Sort != SortRelevance && q.Sort != SortNewest {
+		q.Sort = SortNewest
+		if len(terms) > 0 {
+			q.Sort = SortRelevance
+		}
+	}
+	// Without terms every submission ranks the same
+	if len(terms) == 0 {
+		q.Sort = SortNewest
+	}
+
+	query := s.db.Model(&models.Submission{}).Where("user_id = ?", userID)
+	switch q.Archived {
+	case ArchivedOnly:
+		query = query.Where("archived = ?", true)
+	case ArchivedAll:
+	default:
+		query = query.Where("archived = ?", false)
+	}
+	if len(q.Statuses) > 0 {
+		query = query.Where("status IN ?", q.Statuses)
+	}
+	if q.Model != "" {
+		query = query.Where("(model_used = ? OR model = ?)", q.Model, q.Model)
+	}
+	if q.From != nil {
+		query = query.Where("created_at >= ?", *q.From)
+	}
+	if q.To != nil {
+		query = query.Where("created_at < ?", *q.To)
+	}
+	query = q.Filter.Apply(query, userID)
+	for _, term := range terms {
+		pattern := "%" + escapeLike(term) + "%"
+		query = query.Where("(original_text ILIKE ? OR proofread_text ILIKE ?)", pattern, pattern)
+	}
+
+	if q.Cursor != "" {
+		after, err := decodeCursor(q.Cursor)
+		if err != nil || after.Sort != q.Sort {
+			return nil, ErrInvalidCursor
+		}
+		if q.Sort == SortRelevance {
+			query = query.Where("("+rankExpr+" < ? OR ("+rankExpr+" = ? AND id < ?))",
+				text, text, after.Rank, text, text, after.Rank, after.ID)
+		} else {
+			query = query.Where("(created_at < ? OR (created_at = ? AND id < ?))",
+				after.CreatedAt, after.CreatedAt, after.ID)
+		}
+	}
+
+	if q.Sort == SortRelevance {
+		query = query.Select("submissions.*, "+rankExpr+" AS search_rank", text, text).
+			Order("search_rank DESC, id DESC")
+	} else {
+		query = query.Order("created_at DESC, id DESC")
+	}
+
+	// One extra row tells whether there is another page
+	var hits []hit
+	if err := query.Limit(q.Limit + 1).Find(&hits).Error; err != nil {
+		return nil, err
+	}
+
+	page := &Page{Results: make([]Result, 0, min(len(hits), q.Limit)), Sort: q.Sort}
+	if len(hits) > q.Limit {
+		hits = hits[:q.Limit]
+		last := hits[len(hits)-1]
+		page.NextCursor = encodeCursor(cursor{Sort: q.Sort, Rank: last.Rank, CreatedAt: last.CreatedAt, ID: last.ID})
+	}
+	pattern := highlightPattern(terms)
+	for _, h := range hits {
+		result := Result{Submission: h.Submission, Rank: h.Rank}
+		if pattern != nil {
+			for _, field := range []struct{ name, text string }{
+				{"original_text", h.OriginalText},
+				{"proofread_text", h.ProofreadText},
+			} {
+				if snippet, ok := makeSnippet(field.name, field.text, pattern); ok {
+					result.Snippets = append(result.Snippets, snippet)
+				}
+			}
+		}
+		page.Results = append(page.Results, result)
+	}
+
+	submissions := make([]*models.Submission, len(page.Results))
+	for i := range page.Results {
+		submissions[i] = &page.Results[i].Submission
+	}
+	if err := library.AttachTags(s.db, submissions); err != nil {
+		return nil, err
+	}
+	return page, nil
+}
+
+// Terms splits a query into its distinct search terms. Quotes keep a
+// phrase together as one term.
+func Terms(text string) []string {
+	var terms []string
+	seen := make(map[string]bool)
+	add := func(term string) {
+		term = strings.TrimSpace(term)
+		key := strings.ToLower(term)
+		if term != "" && !seen[key] {
+			seen[key] = true
+			terms = append(terms, term)
+		}
+	}
+	for i, part := range strings.Split(text, `"`) {
+		// Odd parts were inside quotes
+		if i%2 == 1 {
+			add(strings.Join(strings.Fields(part), " "))
+			continue
+		}
+		for _, word := range strings.Fields(part) {
+			add(word)
+		}
+	}
+	return terms
+}
+
+func escapeLike(term string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(term)
+}
+
+func highlightPattern(terms []string) *regexp.Regexp {
+	if len(terms) == 0 {
+		return nil
+	}
+	quoted := make([]string, len(terms))
+	for i, term := range terms {
+		quoted[i] = regexp.QuoteMeta(term)
+	}
+	return regexp.MustCompile("(?i)" + strings.Join(quoted, "|"))
+}
+
+// makeSnippet cuts an excerpt of text around its first match, trimmed to
+// whole words, and marks every match within it.
+func makeSnippet(field, text string, pattern *regexp.Regexp) (Snippet, bool) {
+	matches := pattern.FindAllStringIndex(text, -1)
+	if len(matches) == 0 {
+		return Snippet{}, false
+	}
+
+	runes := []rune(text)
+	matchStart := utf8.RuneCountInString(text[:matches[0][0]])
+	matchEnd := matchStart + utf8.RuneCountInString(text[matches[0][0]:matches[0][1]])
+	context := max((snippetRunes-(matchEnd-matchStart))/2, 0)
+	start, end := max(matchStart-context, 0), min(matchEnd+context, len(runes))
+	if start > 0 {
+		if i := indexSpace(runes[start:matchStart]); i >= 0 {
+			start += i + 1
+		}
+	}
+	if end < len(runes) {
+		if i := lastIndexSpace(runes[matchEnd:end]); i >= 0 {
+			end = matchEnd + i
+		}
+	}
+
+	prefix, suffix := "", ""
+	if start > 0 {
+		prefix = "…"
+	}
+	if end < len(runes) {
+		suffix = "…"
+	}
+	snippet := Snippet{Field: field, Text: prefix + string(runes[start:end]) + suffix, Highlights: [][2]int{}}
+	offset := len([]rune(prefix)) - start
+	for _, m := range matches {
+		from := utf8.RuneCountInString(text[:m[0]])
+		to := from + utf8.RuneCountInString(text[m[0]:m[1]])
+		if to > end {
+			break
+		}
+		if from >= start {
+			snippet.Highlights = append(snippet.Highlights, [2]int{from + offset, to + offset})
+		}
+	}
+	return snippet, true
+}
+
+func indexSpace(runes []rune) int {
+	for i, r := range runes {
+		if unicode.IsSpace(r) {
+			return i
+		}
+	}
+	return -1
+}
+
+func lastIndexSpace(runes []rune) int {
+	for i := len(runes) - 1; i >= 0; i-- {
+		if unicode.IsSpace(runes[i]) {
+			return i
+		}
+	}
+	return -1
+}
+
+func encodeCursor(c cursor) string {
+	raw, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+func decodeCursor(s string) (cursor, error) {
+	var c cursor
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return c, err
+	}
+	err = json.Unmarshal(raw, &c)
+	return c, err
+}
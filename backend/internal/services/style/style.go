@@ -0,0 +1,264 @@
+// Package style resolves style guide profiles and turns them into prompt
+// instructions and deterministic rule checks.
+package style
+
+import (
+	"errors"
+	"strings"
+
+	"tamil-proofreading-platform/backend/internal/models"
+	"tamil-proofreading-platform/backend/internal/services/rules"
+
+	"gorm.io/gorm"
+)
+
+var ErrUnknownProfile = errors.New("unknown style profile")
+
+// Builtins are available to every user.
+var Builtins = []models.StyleProfile{
+	{
+		Key:            "formal",
+		Name:           "Formal (செந்தமிழ்)",
+		Description:    "Literary written Tamil for books, government and academic documents.",
+		Register:       models.RegisterLiterary,
+		GranthaPolicy:  models.PolicyPreferTamil,
+		LoanwordPolicy: models.PolicyAvoid,
+		QuoteStyle:     models.QuotesCurly,
+		Builtin:        true,
+	},
+	{
+		Key:            "journalistic",
+		Name:           "Journalistic",
+		Description:    "Plain modern written Tamil as used by newspapers and news sites.",
+		Register:       models.RegisterStandard,
+		GranthaPolicy:  models.PolicyAllow,
+		LoanwordPolicy: models.PolicyPreferTamil,
+		QuoteStyle:     models.QuotesCurly,
+		Builtin:        true,
+	},
+	{
+		Key:            "colloquial",
+		Name:           "Colloquial (பேச்சுத்தமிழ்)",
+		Description:    "Spoken Tamil for dialogue, social media and informal writing.",
+		Register:       models.RegisterColloquial,
+		GranthaPolicy:  models.PolicyAllow,
+		LoanwordPolicy: models.PolicyAllow,
+		QuoteStyle:     models.QuotesAny,
+		Builtin:        true,
+	},
+}
+
+// IsBuiltin reports whether key names a built-in profile.
+func IsBuiltin(key string) bool {
+	for _, p := range Builtins {
+		if p.Key == key {
+			return true
+		}
+	}
+	return false
+}
+
+// List returns the built-in profiles followed by the organization's own.
+func List(db *gorm.DB, orgID *uint) ([]models.StyleProfile, error) {
+	profiles := append([]models.StyleProfile{}, Builtins...)
+	if orgID == nil {
+		return profiles, nil
+	}
+
+	var custom []models.StyleProfile
+	if err := db.Where("organization_id = ?", *orgID).Order("name ASC").Find(&custom).Error; err != nil {
+		return nil, err
+	}
+	return append(profiles, custom...), nil
+}
+
+// Lookup finds a profile by key, checking built-ins first and then the
+// organization's profiles.
+func Lookup(db *gorm.DB, orgID *uint, key string) (*models.StyleProfile, error) {
+	key = strings.TrimSpace(key)
+	for _, p := range Builtins {
+		if p.Key == key {
+			profile := p
+			return &profile, nil
+		}
+	}
+	if orgID == nil {
+		return nil, ErrUnknownProfile
+	}
+
+	var profile models.StyleProfile
+	err := db.Where("organization_id = ? AND key = ?", *orgID, key).First(&profile).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrUnknownProfile
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &profile, nil
+}
+
+// Resolve picks the profile for a submission: the requested key, else the
+// organization's default, else none.
+func Resolve(db *gorm.DB, userID uint, requested string) (*models.StyleProfile, error) {
+	var member models.OrganizationMember
+	var orgID *uint
+	var orgDefault string
+	if err := db.Where("user_id = ?", userID).First(&member).Error; err == nil {
+		orgID = &member.OrganizationID
+		var org models.Organization
+		if err := db.Select("id", "default_style_profile").First(&org, member.OrganizationID).Error; err == nil {
+			orgDefault = org.DefaultStyleProfile
+		}
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	key := strings.TrimSpace(requested)
+	if key == "" {
+		key = orgDefault
+	}
+	if key == "" {
+		return nil, nil
+	}
+	return Lookup(db, orgID, key)
+}
+
+// Validate checks a profile's enumerated fields, filling defaults.
+func Validate(p *models.StyleProfile) error {
+	p.Key = strings.ToLower(strings.TrimSpace(p.Key))
+	p.Name = strings.TrimSpace(p.Name)
+	if p.Key == "" || p.Name == "" {
+		return errors.New("key and name are required")
+	}
+	if IsBuiltin(p.Key) {
+		return errors.New("key is reserved for a built-in profile")
+	}
+	if p.Register == "" {
+		p.Register = models.RegisterStandard
+	}
+	if p.GranthaPolicy == "" {
+		p.GranthaPolicy = models.PolicyAllow
+	}
+	if p.LoanwordPolicy == "" {
+		p.LoanwordPolicy = models.PolicyAllow
+	}
+	if p.QuoteStyle == "" {
+		p.QuoteStyle = models.QuotesAny
+	}
+
+	switch p.Register {
+	case models.RegisterLiterary, models.RegisterStandard, models.RegisterColloquial:
+	default:
+		return errors.New("register must be literary, standard or colloquial")
+	}
+	for _, policy := range []models.StylePolicy{p.GranthaPolicy, p.LoanwordPolicy} {
+		switch policy {
+		case models.PolicyAllow, models.PolicyPreferTamil, models.PolicyAvoid:
+		default:
+			return errors.New("policies must be allow, prefer_tamil or avoid")
+		}
+	}
+	switch p.QuoteStyle {
+	case models.QuotesAny, models.QuotesCurly, models.QuotesStraight:
+	default:
+		return errors.New("quote_style must be any, curly or straight")
+	}
+	return nil
+}
+
+// Instructions describes the profile for the proofreading prompt.
+func Instructions(p *models.StyleProfile) string {
+	if p == nil {
+		return ""
+	}
+
+	var lines []string
+	switch p.Register {
+	case models.RegisterLiterary:
+		lines = append(lines, "- Register: formal literary Tamil (செந்தமிழ்). Convert spoken forms to written forms (இருக்கு → இருக்கிறது).")
+	case models.RegisterStandard:
+		lines = append(lines, "- Register: plain modern written Tamil. Convert spoken forms to written forms, but keep sentences simple.")
+	case models.RegisterColloquial:
+		lines = append(lines, "- Register: spoken Tamil (பேச்சுத்தமிழ்). Keep colloquial forms such as இருக்கு and நீங்க; do NOT convert them to literary forms.")
+	}
+	switch p.GranthaPolicy {
+	case models.PolicyPreferTamil:
+		lines = append(lines, "- Grantha letters (ஜ ஷ ஸ ஹ க்ஷ): suggest pure Tamil spellings or words where a common one exists.")
+	case models.PolicyAvoid:
+		lines = append(lines, "- Grantha letters (ஜ ஷ ஸ ஹ க்ஷ): avoid them; always suggest pure Tamil (தனித்தமிழ்) alternatives.")
+	default:
+		lines = append(lines, "- Grantha letters are acceptable; do not replace them.")
+	}
+	switch p.LoanwordPolicy {
+	case models.PolicyPreferTamil:
+		lines = append(lines, "- English loanwords: suggest a Tamil word where a widely understood one exists.")
+	case models.PolicyAvoid:
+		lines = append(lines, "- English loanwords: replace English words (in Latin or Tamil script) with Tamil equivalents.")
+	default:
+		lines = append(lines, "- English loanwords are acceptable; do not replace them.")
+	}
+	switch p.QuoteStyle {
+	case models.QuotesCurly:
+		lines = append(lines, "- Use curly quotation marks “ ”.")
+	case models.QuotesStraight:
+		lines = append(lines, "- Use straight quotation marks \".")
+	}
+	if !p.AllowSpaceBeforePunct {
+		lines = append(lines, "- No space before , . ? ! ; :")
+	}
+	if extra := strings.TrimSpace(p.Instructions); extra != "" {
+		lines = append(lines, "- "+extra)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// pureTamilInstruction is added when a submission asks for pure Tamil
+// without a profile that already avoids grantha letters.
+const pureTamilInstruction = "- Pure Tamil (தனித்தமிழ்) mode: avoid grantha letters (ஜ ஷ ஸ ஹ க்ஷ) and Sanskrit-origin words; suggest pure Tamil alternatives with type \"grantha\"."
+
+// GranthaPolicy returns the effective grantha policy; pureTamil forces avoid.
+func GranthaPolicy(p *models.StyleProfile, pureTamil bool) models.StylePolicy {
+	if pureTamil {
+		return models.PolicyAvoid
+	}
+	if p == nil || p.GranthaPolicy == "" {
+		return models.PolicyAllow
+	}
+	return p.GranthaPolicy
+}
+
+// PromptGuide is Instructions plus the pure Tamil instruction when the
+// submission requests it.
+func PromptGuide(p *models.StyleProfile, pureTamil bool) string {
+	guide := Instructions(p)
+	if pureTamil && (p == nil || p.GranthaPolicy != models.PolicyAvoid) {
+		if guide != "" {
+			guide += "\n"
+		}
+		guide += pureTamilInstruction
+	}
+	return guide
+}
+
+// Checkers returns the deterministic style checks the profile enables.
+func Checkers(p *models.StyleProfile) []rules.Checker {
+	if p == nil {
+		return nil
+	}
+
+	var checkers []rules.Checker
+	if p.Register != models.RegisterColloquial {
+		checkers = append(checkers, rules.RegisterChecker{})
+	}
+	checkers = append(checkers, rules.PunctuationChecker{
+		Quotes:                p.QuoteStyle,
+		AllowSpaceBeforePunct: p.AllowSpaceBeforePunct,
+	})
+	return checkers
+}
+
+// FlagUnmappedCodeMix reports whether English words without a known Tamil
+// equivalent should be flagged.
+func FlagUnmappedCodeMix(p *models.StyleProfile) bool {
+	return p != nil && p.LoanwordPolicy != "" && p.LoanwordPolicy != models.PolicyAllow
+}
@@ -0,0 +1,94 @@
+package subtitle
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Limits are the per-cue checks. Zero values disable a check.
+type Limits struct {
+	MaxCPS        float64 `json:"max_cps"`
+	MaxLineLength int     `json:"max_line_length"`
+	MaxLines      int     `json:"max_lines"`
+}
+
+// DefaultLimits follow common broadcast guidance for adult programmes.
+var DefaultLimits = Limits{MaxCPS: 17, MaxLineLength: 42, MaxLines: 2}
+
+type CueReport struct {
+	Index       int      `json:"index"`
+	ID          string   `json:"id,omitempty"`
+	Start       string   `json:"start"`
+	End         string   `json:"end"`
+	Text        string   `json:"text"`
+	Chars       int      `json:"chars"`
+	Duration    float64  `json:"duration"`
+	CPS         float64  `json:"cps"`
+	LongestLine int      `json:"longest_line"`
+	Issues      []string `json:"issues,omitempty"`
+}
+
+type Report struct {
+	Limits  Limits      `json:"limits"`
+	Cues    []CueReport `json:"cues"`
+	Flagged int         `json:"flagged"`
+}
+
+// Check measures each cue's reading speed in characters per second and its
+// line lengths, ignoring markup.
+func Check(cues []Cue, limits Limits) Report {
+	report := Report{Limits: limits, Cues: make([]CueReport, 0, len(cues))}
+	for _, cue := range cues {
+		var visible []string
+		chars, longest := 0, 0
+		for _, l := range cue.Lines {
+			text := strings.TrimSpace(Visible(l.Text))
+			visible = append(visible, text)
+			n := Chars(text)
+			chars += n
+			if n > longest {
+				longest = n
+			}
+		}
+
+		r := CueReport{
+			Index:       cue.Index,
+			ID:          cue.ID,
+			Start:       formatTimestamp(cue.Start.Seconds()),
+			End:         formatTimestamp(cue.End.Seconds()),
+			Text:        strings.Join(visible, "\n"),
+			Chars:       chars,
+			Duration:    round2((cue.End - cue.Start).Seconds()),
+			LongestLine: longest,
+		}
+		if r.Duration > 0 {
+			r.CPS = round2(float64(chars) / r.Duration)
+		}
+
+		if r.Duration <= 0 {
+			r.Issues = append(r.Issues, "cue ends before it starts")
+		} else if limits.MaxCPS > 0 && r.CPS > limits.MaxCPS {
+			r.Issues = append(r.Issues, fmt.Sprintf("reading speed %.1f cps exceeds %.1f", r.CPS, limits.MaxCPS))
+		}
+		if limits.MaxLineLength > 0 && longest > limits.MaxLineLength {
+			r.Issues = append(r.Issues, fmt.Sprintf("line of %d characters exceeds %d", longest, limits.MaxLineLength))
+		}
+		if limits.MaxLines > 0 && len(cue.Lines) > limits.MaxLines {
+			r.Issues = append(r.Issues, fmt.Sprintf("%d lines exceeds %d", len(cue.Lines), limits.MaxLines))
+		}
+		if len(r.Issues) > 0 {
+			report.Flagged++
+		}
+		report.Cues = append(report.Cues, r)
+	}
+	return report
+}
+
+func formatTimestamp(seconds float64) string {
+	ms := int(seconds*1000 + 0.5)
+	return fmt.Sprintf("%02d:%02d:%02d.%03d", ms/3600000, ms/60000%60, ms/1000%60, ms%1000)
+}
+
+func round2(f float64) float64 {
+	return float64(int(f*100+0.5)) / 100
+}
@@ -0,0 +1,191 @@
+// Package subtitle parses SRT and WebVTT files into cues and checks them
+// against line-length and reading-speed limits.
+package subtitle
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+var ErrInvalid = errors.New("invalid subtitle file")
+
+type Format string
+
+const (
+	SRT    Format = "srt"
+	WebVTT Format = "vtt"
+)
+
+// Line is one line of cue text. Start:End is its byte range in the file,
+// markup included.
+type Line struct {
+	Text  string
+	Start int
+	End   int
+}
+
+type Cue struct {
+	Index int
+	ID    string
+	Start time.Duration
+	End   time.Duration
+	Lines []Line
+}
+
+var (
+	timingPattern = regexp.MustCompile(`^\s*((?:\d+:)?\d{1,2}:\d{2}[,.]\d{1,3})\s*-->\s*((?:\d+:)?\d{1,2}:\d{2}[,.]\d{1,3})`)
+	markupPattern = regexp.MustCompile(`<[^>]*>|\{\\[^}]*\}`)
+)
+
+type rawLine struct {
+	text  string
+	start int
+	end   int
+}
+
+func splitLines(src string) []rawLine {
+	var lines []rawLine
+	for start := 0; start < len(src); {
+		end := strings.IndexByte(src[start:], '\n')
+		next := len(src)
+		if end < 0 {
+			end = len(src)
+		} else {
+			end += start
+			next = end + 1
+		}
+		textEnd := end
+		if textEnd > start && src[textEnd-1] == '\r' {
+			textEnd--
+		}
+		lines = append(lines, rawLine{text: src[start:textEnd], start: start, end: textEnd})
+		start = next
+	}
+	return lines
+}
+
+// Parse reads the cues of an SRT or WebVTT file. A leading byte order mark
+// is allowed.
+func Parse(src string, format Format) ([]Cue, error) {
+	lines := splitLines(src)
+	i := 0
+	if format == WebVTT {
+		if len(lines) == 0 || !strings.HasPrefix(strings.TrimPrefix(lines[0].text, "\ufeff"), "WEBVTT") {
+			return nil, fmt.Errorf("%w: missing WEBVTT header", ErrInvalid)
+		}
+		// Skip the header block.
+		for i < len(lines) && strings.TrimSpace(lines[i].text) != "" {
+			i++
+		}
+	}
+
+	var cues []Cue
+	for i < len(lines) {
+		for i < len(lines) && strings.TrimSpace(lines[i].text) == "" {
+			i++
+		}
+		if i >= len(lines) {
+			break
+		}
+
+		blockStart := i
+		for i < len(lines) && strings.TrimSpace(lines[i].text) != "" {
+			i++
+		}
+		block := lines[blockStart:i]
+
+		first := strings.TrimPrefix(block[0].text, "\ufeff")
+		if format == WebVTT && (strings.HasPrefix(first, "NOTE") || strings.HasPrefix(first, "STYLE") || strings.HasPrefix(first, "REGION")) {
+			continue
+		}
+
+		timing := 0
+		id := ""
+		if !strings.Contains(first, "-->") {
+			id = strings.TrimSpace(first)
+			timing = 1
+		}
+		if timing >= len(block) {
+			return nil, fmt.Errorf("%w: cue %q has no timing", ErrInvalid, id)
+		}
+		m := timingPattern.FindStringSubmatch(block[timing].text)
+		if m == nil {
+			return nil, fmt.Errorf("%w: bad timing line %q", ErrInvalid, block[timing].text)
+		}
+		start, err := parseTimestamp(m[1])
+		if err != nil {
+			return nil, err
+		}
+		end, err := parseTimestamp(m[2])
+		if err != nil {
+			return nil, err
+		}
+
+		cue := Cue{Index: len(cues) + 1, ID: id, Start: start, End: end}
+		for _, l := range block[timing+1:] {
+			cue.Lines = append(cue.Lines, Line{Text: l.text, Start: l.start, End: l.end})
+		}
+		cues = append(cues, cue)
+	}
+	return cues, nil
+}
+
+// parseTimestamp parses hh:mm:ss,mmm or mm:ss.mmm.
+func parseTimestamp(s string) (time.Duration, error) {
+	bad := fmt.Errorf("%w: bad timestamp %q", ErrInvalid, s)
+	parts := strings.Split(strings.Replace(s, ",", ".", 1), ":")
+	whole, frac, _ := strings.Cut(parts[len(parts)-1], ".")
+	parts[len(parts)-1] = whole
+
+	// Hours and minutes fold into whole seconds; the fraction is added
+	// as milliseconds
+	var units int64
+	for _, p := range parts {
+		n, err := strconv.ParseInt(p, 10, 64)
+		if err != nil || n < 0 {
+			return 0, bad
+		}
+		units = units*60 + n
+	}
+	total := time.Duration(units) * time.Second
+	if frac != "" {
+		if len(frac) > 3 {
+			return 0, bad
+		}
+		ms, err := strconv.Atoi(frac + strings.Repeat("0", 3-len(frac)))
+		if err != nil {
+			return 0, bad
+		}
+		total += time.Duration(ms) * time.Millisecond
+	}
+	return total, nil
+}
+
+// Markup returns the byte ranges of formatting tags in a cue line, such as
+// <i>, <v Speaker> or {\an8}.
+func Markup(line string) [][]int {
+	return markupPattern.FindAllStringIndex(line, -1)
+}
+
+// Visible returns a line with its markup removed.
+func Visible(line string) string {
+	return markupPattern.ReplaceAllString(line, "")
+}
+
+// Chars counts user-perceived characters: combining marks such as Tamil
+// vowel signs and pulli do not count separately.
+func Chars(s string) int {
+	n := 0
+	for _, r := range s {
+		if unicode.IsMark(r) || r == '\u200c' || r == '\u200d' {
+			continue
+		}
+		n++
+	}
+	return n
+}
@@ -0,0 +1,105 @@
+package subtitle
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseTimestamp(t *testing.T) {
+	tests := []struct {
+		in   string
+		want time.Duration
+	}{
+		{"00:00:00,000", 0},
+		{"00:00:59,000", 59 * time.Second},
+		{"00:01:01,000", time.Minute + time.Second},
+		{"00:01:01.250", time.Minute + time.Second + 250*time.Millisecond},
+		{"01:00:00,000", time.Hour},
+		{"01:02:03,004", time.Hour + 2*time.Minute + 3*time.Second + 4*time.Millisecond},
+		{"10:00:00.5", 10*time.Hour + 500*time.Millisecond},
+		{"02:03.400", 2*time.Minute + 3*time.Second + 400*time.Millisecond},
+		{"59:59.999", 59*time.Minute + 59*time.Second + 999*time.Millisecond},
+	}
+	for _, tt := range tests {
+		got, err := parseTimestamp(tt.in)
+		if err != nil {
+			t.Errorf("parseTimestamp(%q): %v", tt.in, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("parseTimestamp(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestParseTimings(t *testing.T) {
+	tests := []struct {
+		name   string
+		format Format
+		src    string
+		start  time.Duration
+		end    time.Duration
+	}{
+		{
+			name:   "srt across a minute",
+			format: SRT,
+			src:    "1\n00:00:59,000 --> 00:01:01,000\nவணக்கம்\n",
+			start:  59 * time.Second,
+			end:    61 * time.Second,
+		},
+		{
+			name:   "srt hours",
+			format: SRT,
+			src:    "1\r\n01:59:58,500 --> 02:00:01,250\r\nவணக்கம்\r\n",
+			start:  time.Hour + 59*time.Minute + 58*time.Second + 500*time.Millisecond,
+			end:    2*time.Hour + time.Second + 250*time.Millisecond,
+		},
+		{
+			name:   "vtt across a minute",
+			format: WebVTT,
+			src:    "WEBVTT\n\n00:59.000 --> 01:01.000\nவணக்கம்\n",
+			start:  59 * time.Second,
+			end:    61 * time.Second,
+		},
+		{
+			name:   "vtt hours",
+			format: WebVTT,
+			src:    "WEBVTT\n\nintro\n01:00:00.000 --> 01:00:02.500\nவணக்கம்\n",
+			start:  time.Hour,
+			end:    time.Hour + 2*time.Second + 500*time.Millisecond,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cues, err := Parse(tt.src, tt.format)
+			if err != nil {
+				t.Fatalf("Parse: %v", err)
+			}
+			if len(cues) != 1 {
+				t.Fatalf("got %d cues, want 1", len(cues))
+			}
+			if cues[0].Start != tt.start || cues[0].End != tt.end {
+				t.Errorf("got %v --> %v, want %v --> %v", cues[0].Start, cues[0].End, tt.start, tt.end)
+			}
+
+			report := Check(cues, DefaultLimits)
+			want := (tt.end - tt.start).Seconds()
+			if got := report.Cues[0].Duration; got != round2(want) {
+				t.Errorf("Check duration = %v, want %v", got, round2(want))
+			}
+			for _, issue := range report.Cues[0].Issues {
+				if issue == "cue ends before it starts" {
+					t.Errorf("Check flagged a well-ordered cue: %v", report.Cues[0].Issues)
+				}
+			}
+		})
+	}
+}
+
+func TestParseTimestampInvalid(t *testing.T) {
+	for _, in := range []string{"", "aa:00:00,000", "00:00:00,1234", "00:-1:00,000"} {
+		if _, err := parseTimestamp(in); err == nil {
+			t.Errorf("parseTimestamp(%q) succeeded, want an error", in)
+		}
+	}
+}
@@ -0,0 +1,228 @@
+package suggestions
+
+import (
+	"encoding/json"
+	"errors"
+	"sort"
+	"strings"
+	"time"
+
+	"tamil-proofreading-platform/backend/internal/models"
+	"tamil-proofreading-platform/backend/internal/services/llm"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrNotApplicable = errors.New("suggestion cannot be located in the original text")
+	ErrConflict      = errors.New("suggestion overlaps an accepted suggestion")
+)
+
+// Build turns proofreading suggestions into rows for a submission. Offsets
+// that do not point at the suggestion's original text are repaired by
+// searching for the nearest occurrence, or set to -1.
+func Build(submissionID uint, text string, found []llm.Suggestion) []models.SubmissionSuggestion {
+	rows := make([]models.SubmissionSuggestion, 0, len(found))
+	for i, s := range found {
+		start, end := locate(text, s.Original, s.StartIndex, s.EndIndex)
+		rows = append(rows, models.SubmissionSuggestion{
+			SubmissionID: submissionID,
+			Position:     i,
+			Original:     s.Original,
+			Corrected:    s.Corrected,
+			Reason:       s.Reason,
+			Type:         s.Type,
+			StartIndex:   start,
+			EndIndex:     end,
+			State:        models.SuggestionPending,
+		})
+	}
+	return rows
+}
+
+// locate returns the byte range of original in text, preferring the given
+// offsets and otherwise the occurrence closest to start.
+func locate(text, original string, start, end int) (int, int) {
+	if original == "" {
+		// Insertions have no text to search for.
+		if start >= 0 && start <= len(text) {
+			return start, start
+		}
+		return -1, -1
+	}
+	if start >= 0 && end <= len(text) && start <= end && text[start:end] == original {
+		return start, end
+	}
+
+	best := -1
+	for offset := 0; offset <= len(text); {
+		i := strings.Index(text[offset:], original)
+		if i < 0 {
+			break
+		}
+		at := offset + i
+		if best < 0 || abs(at-start) < abs(best-start) {
+			best = at
+		}
+		offset = at + 1
+	}
+	if best < 0 {
+		return -1, -1
+	}
+	return best, best + len(original)
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// Apply returns text with the accepted suggestions applied. Suggestions
+// that cannot be located, or that overlap an earlier accepted one, are
+// skipped.
+func Apply(text string, rows []models.SubmissionSuggestion) string {
+	var accepted []models.SubmissionSuggestion
+	for _, s := range rows {
+		if s.State == models.SuggestionAccepted && s.Applicable() && s.EndIndex <= len(text) {
+			accepted = append(accepted, s)
+		}
+	}
+	sort.SliceStable(accepted, func(i, j int) bool {
+		return accepted[i].StartIndex < accepted[j].StartIndex
+	})
+
+	var b strings.Builder
+	pos := 0
+	for _, s := range accepted {
+		if s.StartIndex < pos {
+			continue
+		}
+		b.WriteString(text[pos:s.StartIndex])
+		b.WriteString(s.Corrected)
+		pos = s.EndIndex
+	}
+	b.WriteString(text[pos:])
+	return b.String()
+}
+
+// Conflicts reports whether s overlaps an accepted suggestion other than
+// itself.
+func Conflicts(s models.SubmissionSuggestion, rows []models.SubmissionSuggestion) bool {
+	for _, other := range rows {
+		if other.ID == s.ID || other.State != models.SuggestionAccepted || !other.Applicable() {
+			continue
+		}
+		if s.StartIndex < other.EndIndex && other.StartIndex < s.EndIndex {
+			return true
+		}
+		// Two insertions at the same point would be applied in an
+		// arbitrary order.
+		if s.StartIndex == s.EndIndex && other.StartIndex == s.StartIndex {
+			return true
+		}
+	}
+	return false
+}
+
+// Store replaces a submission's suggestion rows.
+func Store(tx *gorm.DB, submissionID uint, rows []models.SubmissionSuggestion) error {
+	if err := tx.Where("submission_id = ?", submissionID).Delete(&models.SubmissionSuggestion{}).Error; err != nil {
+		return err
+	}
+	if len(rows) == 0 {
+		return nil
+	}
+	return tx.CreateInBatches(&rows, 200).Error
+}
+
+// Load returns a submission's suggestion rows in their original order.
+// Submissions processed before suggestions were stored as rows are
+// migrated from their JSON column on first access.
+func Load(db *gorm.DB, submission models.Submission) ([]models.SubmissionSuggestion, error) {
+	var rows []models.SubmissionSuggestion
+	if err := db.Where("submission_id = ?", submission.ID).Order("position ASC").Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	if len(rows) > 0 || submission.Status != models.StatusCompleted {
+		return rows, nil
+	}
+
+	var found []llm.Suggestion
+	if strings.TrimSpace(submission.Suggestions) == "" || json.Unmarshal([]byte(submission.Suggestions), &found) != nil || len(found) == 0 {
+		return rows, nil
+	}
+	rows = Build(submission.ID, submission.OriginalText, found)
+	if err := Store(db, submission.ID, rows); err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// Decide sets the state of the given suggestions and recomputes the
+// submission's final text. It runs in a transaction that locks the
+// submission, so concurrent decisions apply in order. Accepting a
+// suggestion that cannot be applied or that conflicts fails the whole call
+// unless skipInvalid is set, in which case such suggestions are left as
+// they are. It returns the suggestions that changed state.
+func Decide(db *gorm.DB, submission *models.Submission, ids []uint, state models.SuggestionState, skipInvalid bool) ([]models.SubmissionSuggestion, error) {
+	var changed []models.SubmissionSuggestion
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(submission, submission.ID).Error; err != nil {
+			return err
+		}
+		rows, err := Load(tx, *submission)
+		if err != nil {
+			return err
+		}
+
+		index := make(map[uint]int, len(rows))
+		for i, s := range rows {
+			index[s.ID] = i
+		}
+
+		now := time.Now()
+		for _, id := range ids {
+			i, ok := index[id]
+			if !ok {
+				return gorm.ErrRecordNotFound
+			}
+			s := rows[i]
+			if s.State == state {
+				continue
+			}
+			if state == models.SuggestionAccepted {
+				var invalid error
+				if !s.Applicable() {
+					invalid = ErrNotApplicable
+				} else if Conflicts(s, rows) {
+					invalid = ErrConflict
+				}
+				if invalid != nil {
+					if skipInvalid {
+						continue
+					}
+					return invalid
+				}
+			}
+			rows[i].State = state
+			rows[i].DecidedAt = &now
+			if err := tx.Model(&rows[i]).Updates(map[string]interface{}{
+				"state":      state,
+				"decided_at": now,
+			}).Error; err != nil {
+				return err
+			}
+			changed = append(changed, rows[i])
+		}
+
+		submission.FinalText = Apply(submission.OriginalText, rows)
+		return tx.Model(submission).Update("final_text", submission.FinalText).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return changed, nil
+}
@@ -0,0 +1,155 @@
+// Package userdict applies a user's personal dictionary and ignore rules to
+// proofreading requests and results.
+package userdict
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"tamil-proofreading-platform/backend/internal/models"
+	"tamil-proofreading-platform/backend/internal/services/llm"
+
+	"gorm.io/gorm"
+)
+
+const maxPatternLength = 200
+
+// trimChars are stripped from suggestion originals before matching, since
+// the model often includes adjoining punctuation.
+const trimChars = " \t\n.,;:!?\"'()[]{}“”‘’।"
+
+type compiledRule struct {
+	rule  models.UserIgnoreRule
+	regex *regexp.Regexp
+}
+
+// Dictionary is a user's loaded personal dictionary and ignore rules.
+type Dictionary struct {
+	words map[string]struct{}
+	list  []string
+	rules []compiledRule
+}
+
+// Load reads the user's dictionary entries and ignore rules.
+func Load(db *gorm.DB, userID uint) (*Dictionary, error) {
+	var entries []models.UserDictionaryEntry
+	if err := db.Where("user_id = ?", userID).Order("word ASC").Find(&entries).Error; err != nil {
+		return nil, err
+	}
+	var rules []models.UserIgnoreRule
+	if err := db.Where("user_id = ?", userID).Order("id ASC").Find(&rules).Error; err != nil {
+		return nil, err
+	}
+
+	d := &Dictionary{words: make(map[string]struct{}, len(entries))}
+	for _, e := range entries {
+		d.words[normalize(e.Word)] = struct{}{}
+		d.list = append(d.list, e.Word)
+	}
+	for _, r := range rules {
+		compiled := compiledRule{rule: r}
+		if r.MatchType == models.IgnoreRegex {
+			re, err := CompilePattern(r.Pattern)
+			if err != nil {
+				// Rules are validated on save; skip anything that no longer compiles.
+				continue
+			}
+			compiled.regex = re
+		}
+		d.rules = append(d.rules, compiled)
+	}
+	return d, nil
+}
+
+// CompilePattern validates a regex ignore rule. Matching is case-insensitive
+// and anchored to the whole suggestion original.
+func CompilePattern(pattern string) (*regexp.Regexp, error) {
+	if len(pattern) > maxPatternLength {
+		return nil, fmt.Errorf("pattern is longer than %d characters", maxPatternLength)
+	}
+	return regexp.Compile(`(?i)^(?:` + pattern + `)$`)
+}
+
+// Words returns the dictionary words in alphabetical order.
+func (d *Dictionary) Words() []string {
+	if d == nil {
+		return nil
+	}
+	return d.list
+}
+
+// Empty reports whether there is nothing to apply.
+func (d *Dictionary) Empty() bool {
+	return d == nil || (len(d.words) == 0 && len(d.rules) == 0)
+}
+
+// Ignores reports whether a suggestion should be dropped.
+func (d *Dictionary) Ignores(s llm.Suggestion) bool {
+	if d == nil {
+		return false
+	}
+
+	original := normalize(s.Original)
+	if original == "" {
+		return false
+	}
+	if _, ok := d.words[original]; ok {
+		return true
+	}
+	// Multi-word originals are ignored when any protected word would change.
+	for _, token := range strings.Fields(original) {
+		if _, ok := d.words[strings.Trim(token, trimChars)]; ok && !strings.Contains(strings.ToLower(s.Corrected), token) {
+			return true
+		}
+	}
+
+	for _, r := range d.rules {
+		if r.rule.SuggestionType != "" && !strings.EqualFold(r.rule.SuggestionType, s.Type) {
+			continue
+		}
+		pattern := normalize(r.rule.Pattern)
+		switch r.rule.MatchType {
+		case models.IgnorePrefix:
+			if strings.HasPrefix(original, pattern) {
+				return true
+			}
+		case models.IgnoreRegex:
+			if r.regex != nil && r.regex.MatchString(strings.Trim(s.Original, trimChars)) {
+				return true
+			}
+		default:
+			if original == pattern {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Apply drops ignored suggestions and reverts their edits in the corrected
+// text so the stored result stays consistent. It returns the number dropped.
+func (d *Dictionary) Apply(result *llm.ProofreadResult) int {
+	if d.Empty() || result == nil {
+		return 0
+	}
+
+	kept := result.Suggestions[:0]
+	dropped := 0
+	for _, s := range result.Suggestions {
+		if !d.Ignores(s) {
+			kept = append(kept, s)
+			continue
+		}
+		dropped++
+		if s.Corrected != "" && s.Original != "" {
+			result.CorrectedText = strings.Replace(result.CorrectedText, s.Corrected, s.Original, 1)
+		}
+	}
+	result.Suggestions = kept
+	return dropped
+}
+
+func normalize(s string) string {
+	return strings.ToLower(strings.Trim(s, trimChars))
+}
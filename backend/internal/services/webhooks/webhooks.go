@@ -0,0 +1,463 @@
+// Package webhooks delivers lifecycle events to the HTTPS endpoints users
+// and organizations register. Each event is stored as one delivery per
+// subscribed endpoint and sent by background workers, signed with the
+// endpoint's secret and retried with exponential backoff until it succeeds
+// or runs out of attempts.
+package webhooks
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	mathrand "math/rand"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"tamil-proofreading-platform/backend/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Options tunes a WebhookService.
+type Options struct {
+	// Workers is how many deliveries this process sends at once.
+	Workers int
+	// PollInterval is how often idle workers look for due deliveries.
+	PollInterval time.Duration
+	// Timeout bounds each attempt, including reading the response.
+	Timeout time.Duration
+	// MaxAttempts is how many times a delivery is tried before it fails.
+	MaxAttempts int
+	// BaseBackoff is the wait before the first retry; it doubles with each
+	// attempt up to MaxBackoff.
+	BaseBackoff time.Duration
+	MaxBackoff  time.Duration
+	// Retention is how long finished deliveries are kept.
+	Retention time.Duration
+	// AllowPrivate permits plain HTTP and endpoints on loopback or private
+	// networks, for local development.
+	AllowPrivate bool
+}
+
+var DefaultOptions = Options{
+	Workers:      2,
+	PollInterval: 5 * time.Second,
+	Timeout:      10 * time.Second,
+	MaxAttempts:  8,
+	BaseBackoff:  time.Minute,
+	MaxBackoff:   6 * time.Hour,
+	Retention:    30 * 24 * time.Hour,
+}
+
+// maxResponseBody is how much of an endpoint's response is kept in the
+// delivery log.
+const maxResponseBody = 2048
+
+// SignatureHeader carries "t=<unix time>,v1=<hex HMAC-SHA256>" where the
+// HMAC is over "<unix time>.<body>" keyed with the endpoint secret.
+const SignatureHeader = "X-Webhook-Signature"
+
+var (
+	ErrInvalidURL      = errors.New("invalid webhook URL")
+	ErrBlockedAddress  = errors.New("webhook address is not public")
+	ErrEndpointStopped = errors.New("webhook endpoint is disabled or deleted")
+)
+
+// Envelope is the JSON body of every delivery.
+type Envelope struct {
+	ID        string    `json:"id"`
+	Type      string    `json:"type"`
+	CreatedAt time.Time `json:"created_at"`
+	Data      any       `json:"data"`
+}
+
+type WebhookService struct {
+	db     *gorm.DB
+	opts   Options
+	client *http.Client
+	wake   chan struct{}
+}
+
+func NewWebhookService(db *gorm.DB, opts Options) *WebhookService {
+	s := &WebhookService{
+		db:   db,
+		opts: opts,
+		wake: make(chan struct{}, 1),
+	}
+	dialer := &net.Dialer{Timeout: opts.Timeout, Control: s.checkDial}
+	s.client = &http.Client{
+		Timeout: opts.Timeout,
+		Transport: &http.Transport{
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: opts.Timeout,
+			MaxIdleConnsPerHost: 2,
+		},
+		// A redirect could point anywhere, so it is treated as the response
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+	return s
+}
+
+// NewSecret returns a random signing secret.
+func NewSecret() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return "whsec_" + hex.EncodeToString(buf), nil
+}
+
+// Sign returns the signature header value for body sent at t.
+func Sign(secret string, t time.Time, body []byte) string {
+	ts := strconv.FormatInt(t.Unix(), 10)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(ts))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "t=" + ts + ",v1=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// ValidateURL checks an endpoint URL, returning it normalized. Endpoints
+// must use HTTPS and must not name a private host, unless AllowPrivate is
+// set. Addresses are checked again when each delivery connects.
+func (s *WebhookService) ValidateURL(raw string) (string, error) {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil || u.Host == "" || u.Hostname() == "" {
+		return "", ErrInvalidURL
+	}
+	if u.User != nil {
+		return "", fmt.Errorf("%w: credentials are not allowed", ErrInvalidURL)
+	}
+	switch u.Scheme {
+	case "https":
+	case "http":
+		if !s.opts.AllowPrivate {
+			return "", fmt.Errorf("%w: must use https", ErrInvalidURL)
+		}
+	default:
+		return "", fmt.Errorf("%w: must use https", ErrInvalidURL)
+	}
+	if !s.opts.AllowPrivate {
+		host := strings.ToLower(u.Hostname())
+		if host == "localhost" || strings.HasSuffix(host, ".localhost") || strings.HasSuffix(host, ".internal") {
+			return "", ErrBlockedAddress
+		}
+		if ip := net.ParseIP(host); ip != nil && !publicIP(ip) {
+			return "", ErrBlockedAddress
+		}
+	}
+	u.Fragment = ""
+	return u.String(), nil
+}
+
+// checkDial refuses connections to non-public addresses once the host name
+// has been resolved, so DNS cannot point an endpoint inside the network.
+func (s *WebhookService) checkDial(network, address string, _ syscall.RawConn) error {
+	if s.opts.AllowPrivate {
+		return nil
+	}
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	if ip := net.ParseIP(host); ip == nil || !publicIP(ip) {
+		return ErrBlockedAddress
+	}
+	return nil
+}
+
+var sharedAddressSpace = &net.IPNet{IP: net.IPv4(100, 64, 0, 0), Mask: net.CIDRMask(10, 32)}
+
+func publicIP(ip net.IP) bool {
+	return !(ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsMulticast() ||
+		sharedAddressSpace.Contains(ip))
+}
+
+// Publish queues an event for every active endpoint of the user that
+// subscribes to it, and for their organization's endpoints when the
+// organization shares that event type. Payment events are never shared.
+func (s *WebhookService) Publish(userID uint, event string, data any) error {
+	if s.db == nil {
+		return nil
+	}
+	query := s.db.Where("user_id = ?", userID)
+	if models.OrgShareableEvent(event) {
+		orgID, err := s.sharingOrganization(userID, event)
+		if err != nil {
+			return err
+		}
+		if orgID != 0 {
+			query = s.db.Where("user_id = ? OR organization_id = ?", userID, orgID)
+		}
+	}
+	var endpoints []models.WebhookEndpoint
+	if err := s.db.Where("active = ?", true).Where(query).Find(&endpoints).Error; err != nil {
+		return err
+	}
+
+	var deliveries []models.WebhookDelivery
+	var payload []byte
+	eventID := "evt_" + strings.ReplaceAll(uuid.NewString(), "-", "")
+	for _, endpoint := range endpoints {
+		if !endpoint.Subscribes(event) {
+			continue
+		}
+		if payload == nil {
+			var err error
+			payload, err = json.Marshal(Envelope{ID: eventID, Type: event, CreatedAt: time.Now().UTC(), Data: data})
+			if err != nil {
+				return err
+			}
+		}
+		deliveries = append(deliveries, models.WebhookDelivery{
+			EndpointID:    endpoint.ID,
+			EventID:       eventID,
+			Event:         event,
+			Payload:       string(payload),
+			State:         models.DeliveryPending,
+			NextAttemptAt: time.Now(),
+		})
+	}
+	if len(deliveries) == 0 {
+		return nil
+	}
+	if err := s.db.Create(&deliveries).Error; err != nil {
+		return err
+	}
+	s.notify()
+	return nil
+}
+
+// sharingOrganization returns the ID of the user's organization if it has
+// opted in to receiving the event, or 0.
+func (s *WebhookService) sharingOrganization(userID uint, event string) (uint, error) {
+	var org models.Organization
+	err := s.db.Joins("JOIN organization_members ON organization_members.organization_id = organizations.id").
+		Where("organization_members.user_id = ?", userID).
+		First(&org).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	for _, shared := range org.WebhookEvents {
+		if shared == event {
+			return org.ID, nil
+		}
+	}
+	return 0, nil
+}
+
+// Replay sends a delivery's event to its endpoint again as a new delivery
+// with a fresh set of attempts.
+func (s *WebhookService) Replay(original *models.WebhookDelivery) (*models.WebhookDelivery, error) {
+	var endpoint models.WebhookEndpoint
+	if err := s.db.First(&endpoint, original.EndpointID).Error; err != nil {
+		return nil, err
+	}
+	if !endpoint.Active {
+		return nil, ErrEndpointStopped
+	}
+
+	delivery := models.WebhookDelivery{
+		EndpointID:    original.EndpointID,
+		EventID:       original.EventID,
+		Event:         original.Event,
+		Payload:       original.Payload,
+		State:         models.DeliveryPending,
+		NextAttemptAt: time.Now(),
+		ReplayOf:      &original.ID,
+	}
+	if err := s.db.Create(&delivery).Error; err != nil {
+		return nil, err
+	}
+	s.notify()
+	return &delivery, nil
+}
+
+func (s *WebhookService) notify() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Start runs the delivery workers until ctx is cancelled, and removes
+// finished deliveries past their retention once an hour.
+func (s *WebhookService) Start(ctx context.Context) {
+	if s.db == nil {
+		log.Printf("webhook delivery not started: no database")
+		return
+	}
+	go s.cleanupLoop(ctx)
+	for i := 0; i < s.opts.Workers; i++ {
+		go s.work(ctx)
+	}
+}
+
+func (s *WebhookService) work(ctx context.Context) {
+	ticker := time.NewTicker(s.opts.PollInterval)
+	defer ticker.Stop()
+	for {
+		delivery, err := s.claim()
+		if err != nil {
+			log.Printf("webhooks: claim failed: %v", err)
+		}
+		if delivery != nil {
+			s.deliver(ctx, delivery)
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.wake:
+		case <-ticker.C:
+		}
+	}
+}
+
+// claim leases the next due delivery, or one whose worker stopped while
+// sending it.
+func (s *WebhookService) claim() (*models.WebhookDelivery, error) {
+	now := time.Now()
+	var deliveries []models.WebhookDelivery
+	err := s.db.Raw(`
+		UPDATE webhook_deliveries SET state = ?, attempts = attempts + 1, locked_until = ?, updated_at = ?
+		WHERE id = (
+			SELECT id FROM webhook_deliveries
+			WHERE (state = ? AND next_attempt_at <= ?) OR (state = ? AND locked_until < ?)
+			ORDER BY next_attempt_at, id
+			FOR UPDATE SKIP LOCKED
+			LIMIT 1
+		)
+		RETURNING *`,
+		models.DeliveryDelivering, now.Add(2*s.opts.Timeout+30*time.Second), now,
+		models.DeliveryPending, now, models.DeliveryDelivering, now,
+	).Scan(&deliveries).Error
+	if err != nil || len(deliveries) == 0 {
+		return nil, err
+	}
+	return &deliveries[0], nil
+}
+
+// deliver makes one attempt and records its outcome.
+func (s *WebhookService) deliver(ctx context.Context, delivery *models.WebhookDelivery) {
+	var endpoint models.WebhookEndpoint
+	err := s.db.First(&endpoint, delivery.EndpointID).Error
+	if err == nil && !endpoint.Active {
+		err = ErrEndpointStopped
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		err = ErrEndpointStopped
+	}
+	if err != nil {
+		s.record(delivery, 0, "", err, 0, errors.Is(err, ErrEndpointStopped))
+		return
+	}
+
+	body := []byte(delivery.Payload)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint.URL, strings.NewReader(delivery.Payload))
+	if err != nil {
+		s.record(delivery, 0, "", err, 0, true)
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "TamilProofreading-Webhooks/1.0")
+	req.Header.Set("X-Webhook-Event", delivery.Event)
+	req.Header.Set("X-Webhook-Id", delivery.EventID)
+	req.Header.Set("X-Webhook-Delivery", strconv.FormatUint(uint64(delivery.ID), 10))
+	req.Header.Set(SignatureHeader, Sign(endpoint.Secret, time.Now(), body))
+
+	started := time.Now()
+	resp, err := s.client.Do(req)
+	if err != nil {
+		s.record(delivery, 0, "", err, time.Since(started), false)
+		return
+	}
+	defer resp.Body.Close()
+	snippet, _ := io.ReadAll(io.LimitReader(resp.Body, maxResponseBody))
+	elapsed := time.Since(started)
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		err = fmt.Errorf("endpoint returned %s", resp.Status)
+	}
+	s.record(delivery, resp.StatusCode, strings.ToValidUTF8(string(snippet), ""), err, elapsed, false)
+}
+
+// record stores an attempt's outcome: success, a retry after backoff, or
+// failure once out of attempts or when final is set.
+func (s *WebhookService) record(delivery *models.WebhookDelivery, status int, body string, cause error, elapsed time.Duration, final bool) {
+	updates := map[string]interface{}{
+		"locked_until":    nil,
+		"response_status": status,
+		"response_body":   body,
+		"duration_ms":     elapsed.Milliseconds(),
+		"error":           "",
+	}
+	switch {
+	case cause == nil:
+		updates["state"] = models.DeliverySucceeded
+		updates["delivered_at"] = time.Now()
+	case final || delivery.Attempts >= s.opts.MaxAttempts:
+		updates["state"] = models.DeliveryFailed
+		updates["error"] = cause.Error()
+	default:
+		updates["state"] = models.DeliveryPending
+		updates["error"] = cause.Error()
+		updates["next_attempt_at"] = time.Now().Add(s.backoff(delivery.Attempts))
+	}
+
+	if err := s.db.Model(&models.WebhookDelivery{}).
+		Where("id = ? AND state = ?", delivery.ID, models.DeliveryDelivering).
+		Updates(updates).Error; err != nil {
+		log.Printf("webhooks: recording delivery %d failed: %v", delivery.ID, err)
+	}
+	if cause != nil {
+		log.Printf("webhooks: delivery %d (%s) attempt %d failed: %v", delivery.ID, delivery.Event, delivery.Attempts, cause)
+	}
+}
+
+// backoff doubles the wait with each attempt, with up to 20% jitter.
+func (s *WebhookService) backoff(attempts int) time.Duration {
+	wait := s.opts.BaseBackoff
+	for i := 1; i < attempts && wait < s.opts.MaxBackoff; i++ {
+		wait *= 2
+	}
+	wait = min(wait, s.opts.MaxBackoff)
+	return wait + time.Duration(mathrand.Int63n(int64(wait)/5+1))
+}
+
+func (s *WebhookService) cleanupLoop(ctx context.Context) {
+	ticker := time.NewTicker(time.Hour)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		if err := s.db.Where("state IN ? AND created_at < ?",
+			[]models.DeliveryState{models.DeliverySucceeded, models.DeliveryFailed},
+			time.Now().Add(-s.opts.Retention),
+		).Delete(&models.WebhookDelivery{}).Error; err != nil {
+			log.Printf("webhooks: cleanup failed: %v", err)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package htmlsanitize
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Version identifies the current sanitizing rules. Bump it whenever the
+// allowlist tightens, so Backfill cleans stored HTML again.
+const Version = 1
+
+// backfillBatch is how many rows Backfill reads at a time.
+const backfillBatch = 500
+
+// Target is a column of stored HTML.
+type Target struct {
+	Table  string
+	Column string
+}
+
+// Backfill sanitizes the HTML already stored in each target with the current
+// rules, so HTML saved under older rules is never served as it was stored.
+// Rows are read in batches by id and only rows whose HTML changes are
+// written. A target is recorded once done, so later runs skip it until
+// Version changes.
+func Backfill(db *gorm.DB, targets ...Target) error {
+	if err := db.Exec(`CREATE TABLE IF NOT EXISTS html_sanitize_backfills (
+		target TEXT PRIMARY KEY,
+		version INTEGER NOT NULL,
+		completed_at TIMESTAMPTZ NOT NULL
+	)`).Error; err != nil {
+		return err
+	}
+
+	for _, t := range targets {
+		name := t.Table + "." + t.Column
+		var done int64
+		if err := db.Table("html_sanitize_backfills").
+			Where("target = ? AND version >= ?", name, Version).
+			Count(&done).Error; err != nil {
+			return err
+		}
+		if done > 0 {
+			continue
+		}
+		if err := backfill(db, t); err != nil {
+			return err
+		}
+		if err := db.Exec(`INSERT INTO html_sanitize_backfills (target, version, completed_at) VALUES (?, ?, ?)
+			ON CONFLICT (target) DO UPDATE SET version = EXCLUDED.version, completed_at = EXCLUDED.completed_at`,
+			name, Version, time.Now()).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func backfill(db *gorm.DB, t Target) error {
+	type row struct {
+		ID   uint
+		HTML string
+	}
+	var last uint
+	for {
+		var rows []row
+		if err := db.Table(t.Table).
+			Select("id, "+t.Column+" AS html").
+			Where("id > ? AND "+t.Column+" <> ''", last).
+			Order("id").
+			Limit(backfillBatch).
+			Scan(&rows).Error; err != nil {
+			return err
+		}
+		for _, r := range rows {
+			if clean := Sanitize(r.HTML); clean != r.HTML {
+				if err := db.Table(t.Table).Where("id = ?", r.ID).UpdateColumn(t.Column, clean).Error; err != nil {
+					return err
+				}
+			}
+			last = r.ID
+		}
+		if len(rows) < backfillBatch {
+			return nil
+		}
+	}
+}
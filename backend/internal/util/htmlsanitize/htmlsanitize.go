@@ -0,0 +1,288 @@
+// Package htmlsanitize cleans user-supplied rich text against an allowlist
+// of elements, attributes, URL schemes and CSS properties. Input is parsed
+// as a browser would parse it and the cleaned tree is serialized again, so
+// what is returned is exactly what was checked.
+package htmlsanitize
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// attrRule validates an attribute value, returning the value to keep.
+type attrRule func(string) (string, bool)
+
+// dropped elements are removed with their content. This covers everything
+// serialized as raw text, foreign content such as SVG and MathML, and
+// embedded or form content.
+var dropped = map[atom.Atom]bool{
+	atom.Script: true, atom.Style: true, atom.Xmp: true, atom.Iframe: true,
+	atom.Noembed: true, atom.Noframes: true, atom.Plaintext: true, atom.Noscript: true,
+	atom.Template: true, atom.Object: true, atom.Embed: true, atom.Applet: true,
+	atom.Frame: true, atom.Frameset: true, atom.Svg: true, atom.Math: true,
+	atom.Link: true, atom.Meta: true, atom.Base: true, atom.Head: true, atom.Title: true,
+	atom.Input: true, atom.Textarea: true, atom.Select: true, atom.Button: true,
+	atom.Audio: true, atom.Video: true, atom.Canvas: true, atom.Source: true,
+	atom.Track: true, atom.Param: true, atom.Dialog: true,
+}
+
+// allowed lists the elements that are kept and their attributes beyond the
+// global ones. Other elements are unwrapped: their content is kept.
+var allowed = map[atom.Atom]map[string]attrRule{
+	atom.P: nil, atom.Br: nil, atom.Hr: nil, atom.Div: nil, atom.Span: nil,
+	atom.B: nil, atom.Strong: nil, atom.I: nil, atom.Em: nil, atom.U: nil,
+	atom.S: nil, atom.Strike: nil, atom.Del: nil, atom.Ins: nil, atom.Mark: nil,
+	atom.Sub: nil, atom.Sup: nil, atom.Small: nil, atom.Code: nil, atom.Kbd: nil,
+	atom.Pre: nil, atom.Abbr: nil, atom.Cite: nil,
+	atom.H1: nil, atom.H2: nil, atom.H3: nil, atom.H4: nil, atom.H5: nil, atom.H6: nil,
+	atom.Ul: nil, atom.Li: {"value": number},
+	atom.Ol: {"start": number, "type": oneOf("1", "a", "A", "i", "I")},
+	atom.Dl: nil, atom.Dt: nil, atom.Dd: nil,
+	atom.Blockquote: {"cite": link},
+	atom.Q:          {"cite": link},
+	atom.Figure:     nil, atom.Figcaption: nil,
+	atom.Table: nil, atom.Caption: nil, atom.Thead: nil, atom.Tbody: nil, atom.Tfoot: nil,
+	atom.Tr:       nil,
+	atom.Td:       {"colspan": number, "rowspan": number, "align": align},
+	atom.Th:       {"colspan": number, "rowspan": number, "align": align, "scope": oneOf("row", "col", "rowgroup", "colgroup")},
+	atom.Colgroup: {"span": number},
+	atom.Col:      {"span": number},
+	atom.A:        {"href": link, "target": oneOf("_blank")},
+	atom.Img:      {"src": imageSource, "alt": text, "width": number, "height": number},
+}
+
+// global attributes are allowed on every kept element.
+var global = map[string]attrRule{
+	"title": text,
+	"lang":  text,
+	"dir":   oneOf("ltr", "rtl", "auto"),
+	"class": classes,
+	"style": style,
+}
+
+// Sanitize returns src with everything outside the allowlist removed.
+// Comments are removed too, and links opening a new window get
+// rel="noopener noreferrer".
+func Sanitize(src string) string {
+	if strings.TrimSpace(src) == "" {
+		return ""
+	}
+
+	body := &html.Node{Type: html.ElementNode, Data: "body", DataAtom: atom.Body}
+	nodes, err := html.ParseFragment(strings.NewReader(src), body)
+	if err != nil {
+		return ""
+	}
+	for _, n := range nodes {
+		body.AppendChild(n)
+	}
+	clean(body)
+
+	var b bytes.Buffer
+	for c := body.FirstChild; c != nil; c = c.NextSibling {
+		if err := html.Render(&b, c); err != nil {
+			return ""
+		}
+	}
+	// Cleaning can leave only whitespace, which is treated as empty
+	if strings.TrimSpace(b.String()) == "" {
+		return ""
+	}
+	return b.String()
+}
+
+func clean(parent *html.Node) {
+	for c := parent.FirstChild; c != nil; {
+		next := c.NextSibling
+		switch c.Type {
+		case html.TextNode:
+		case html.ElementNode:
+			rules, ok := allowed[c.DataAtom]
+			switch {
+			case c.Namespace != "" || dropped[c.DataAtom]:
+				parent.RemoveChild(c)
+			case ok:
+				c.Attr = cleanAttrs(c, rules)
+				clean(c)
+			default:
+				// Keep the content of unknown elements; it is cleaned
+				// in turn as the loop continues with it
+				if c.FirstChild != nil {
+					next = c.FirstChild
+				}
+				for gc := c.FirstChild; gc != nil; {
+					following := gc.NextSibling
+					c.RemoveChild(gc)
+					parent.InsertBefore(gc, c)
+					gc = following
+				}
+				parent.RemoveChild(c)
+			}
+		default:
+			parent.RemoveChild(c)
+		}
+		c = next
+	}
+}
+
+func cleanAttrs(n *html.Node, rules map[string]attrRule) []html.Attribute {
+	var out []html.Attribute
+	blank := false
+	for _, a := range n.Attr {
+		if a.Namespace != "" {
+			continue
+		}
+		key := strings.ToLower(a.Key)
+		rule, ok := rules[key]
+		if !ok {
+			rule, ok = global[key]
+		}
+		if !ok {
+			continue
+		}
+		value, ok := rule(a.Val)
+		if !ok {
+			continue
+		}
+		if key == "target" {
+			blank = true
+		}
+		out = append(out, html.Attribute{Key: key, Val: value})
+	}
+	if blank {
+		out = append(out, html.Attribute{Key: "rel", Val: "noopener noreferrer"})
+	}
+	return out
+}
+
+func text(v string) (string, bool) {
+	return v, true
+}
+
+var digits = regexp.MustCompile(`^[0-9]{1,4}$`)
+
+func number(v string) (string, bool) {
+	v = strings.TrimSpace(v)
+	return v, digits.MatchString(v)
+}
+
+func oneOf(values ...string) attrRule {
+	return func(v string) (string, bool) {
+		v = strings.TrimSpace(v)
+		for _, allowed := range values {
+			if v == allowed {
+				return v, true
+			}
+		}
+		return "", false
+	}
+}
+
+var align = oneOf("left", "right", "center", "justify")
+
+var classToken = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+func classes(v string) (string, bool) {
+	var kept []string
+	for _, c := range strings.Fields(v) {
+		if classToken.MatchString(c) {
+			kept = append(kept, c)
+		}
+	}
+	return strings.Join(kept, " "), len(kept) > 0
+}
+
+var schemes = map[string]bool{"": true, "http": true, "https": true, "mailto": true, "tel": true}
+
+var dataImage = regexp.MustCompile(`^(?i:data:image/(png|gif|jpeg|webp);base64),[A-Za-z0-9+/=]*$`)
+
+// link allows relative URLs and the http, https, mailto and tel schemes.
+func link(v string) (string, bool) {
+	v, ok := normalizeURL(v)
+	if !ok {
+		return "", false
+	}
+	return v, schemes[scheme(v)]
+}
+
+// imageSource also allows base64 PNG, GIF, JPEG and WebP data URLs, which
+// editors use for pasted images. SVG is not allowed as it can run script.
+func imageSource(v string) (string, bool) {
+	v, ok := normalizeURL(v)
+	if !ok {
+		return "", false
+	}
+	if scheme(v) == "data" {
+		return v, dataImage.MatchString(v)
+	}
+	return v, schemes[scheme(v)]
+}
+
+// normalizeURL removes what browsers ignore when reading a URL, such as tabs
+// and newlines inside "java\tscript:", and rejects other control characters.
+func normalizeURL(v string) (string, bool) {
+	v = strings.Map(func(r rune) rune {
+		if r == '\t' || r == '\n' || r == '\r' {
+			return -1
+		}
+		return r
+	}, v)
+	v = strings.TrimFunc(v, func(r rune) bool { return r <= ' ' })
+	for _, r := range v {
+		if r < ' ' || r == 0x7f {
+			return "", false
+		}
+	}
+	return v, true
+}
+
+// scheme returns the lower-cased scheme of a URL, or "" if it is relative.
+func scheme(v string) string {
+	i := strings.IndexAny(v, ":/?#")
+	if i < 0 || v[i] != ':' {
+		return ""
+	}
+	return strings.ToLower(v[:i])
+}
+
+// cssProperties are the inline styles rich-text editors write for text
+// formatting and layout.
+var cssProperties = map[string]bool{
+	"color": true, "background-color": true, "font-weight": true, "font-style": true,
+	"font-size": true, "font-family": true, "text-decoration": true, "text-align": true,
+	"text-indent": true, "line-height": true, "vertical-align": true, "direction": true,
+	"margin-left": true, "margin-right": true, "padding-left": true, "padding-right": true,
+	"white-space": true,
+}
+
+// cssValue allows keywords, lengths, colours and quoted font names, but no
+// functions, escapes or comments, so no url() or expression().
+var cssValue = regexp.MustCompile(`^[A-Za-z0-9 #%.,'"-]+$`)
+
+var cssColor = regexp.MustCompile(`^(?i:rgba?|hsla?)\([0-9 ,.%/]+\)$`)
+
+// style keeps the allowed declarations of a style attribute.
+func style(v string) (string, bool) {
+	var kept []string
+	for _, decl := range strings.Split(v, ";") {
+		name, value, ok := strings.Cut(decl, ":")
+		name = strings.ToLower(strings.TrimSpace(name))
+		value = strings.TrimSpace(value)
+		if !ok || !cssProperties[name] || value == "" {
+			continue
+		}
+		if !cssColor.MatchString(value) && !(cssValue.MatchString(value) && balancedQuotes(value)) {
+			continue
+		}
+		kept = append(kept, name+": "+value)
+	}
+	return strings.Join(kept, "; "), len(kept) > 0
+}
+
+func balancedQuotes(v string) bool {
+	return strings.Count(v, `"`)%2 == 0 && strings.Count(v, `'`)%2 == 0
+}
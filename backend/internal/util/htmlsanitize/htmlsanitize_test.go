@@ -0,0 +1,157 @@
+package htmlsanitize
+
+import (
+	"strings"
+	"testing"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+func TestSanitize(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"plain text", "வணக்கம்", "வணக்கம்"},
+		{"only whitespace left", " \x00<script>x</script>", ""},
+		{"formatting kept", "<p><b>தமிழ்</b> <i>மொழி</i></p>", "<p><b>தமிழ்</b> <i>மொழி</i></p>"},
+		{"unknown element unwrapped", "<custom-el>உரை</custom-el>", "உரை"},
+		{"script removed", "<p>a<script>alert(1)</script>b</p>", "<p>ab</p>"},
+		{"svg style removed", "<svg><style>*{}</style><script>alert(1)</script></svg>x", "x"},
+		{"svg onload", `<svg onload="alert(1)"><circle/></svg>`, ""},
+		{"svg foreignObject", `<svg><foreignObject><img src=x onerror=alert(1)></foreignObject></svg>`, ""},
+		{"math", `<math><mtext><table><mglyph><style><img src=x onerror=alert(1)>`, ""},
+		{"math href", `<math href="javascript:alert(1)">x</math>`, ""},
+		{"noscript", `<noscript><p title="</noscript><img src=x onerror=alert(1)>">`, `<img src="x"/>&#34;&gt;`},
+		{"template", `<template><img src=x onerror=alert(1)></template>y`, "y"},
+		{"comment", "a<!-- <img src=x onerror=alert(1)> -->b", "ab"},
+		{"conditional comment", "<!--[if IE]><script>alert(1)</script><![endif]-->c", "c"},
+		{"mixed case handler", `<p OnClick="alert(1)">x</p>`, "<p>x</p>"},
+		{"unquoted handler", `<img src=/a.png onerror=alert(1)>`, `<img src="/a.png"/>`},
+		{"handler after slash", `<img/src="/a.png"/onerror=alert(1)>`, `<img src="/a.png"/>`},
+		{"id dropped", `<p id="x">y</p>`, "<p>y</p>"},
+		{"javascript href", `<a href="javascript:alert(1)">x</a>`, "<a>x</a>"},
+		{"mixed case javascript", `<a href="JaVaScRiPt:alert(1)">x</a>`, "<a>x</a>"},
+		{"tab in scheme", "<a href=\"java\tscript:alert(1)\">x</a>", "<a>x</a>"},
+		{"newline in scheme", "<a href=\"java\nscript:alert(1)\">x</a>", "<a>x</a>"},
+		{"entity tab in scheme", `<a href="java&#9;script:alert(1)">x</a>`, "<a>x</a>"},
+		{"entity encoded scheme", `<a href="&#106;avascript:alert(1)">x</a>`, "<a>x</a>"},
+		{"leading space scheme", `<a href=" javascript:alert(1)">x</a>`, "<a>x</a>"},
+		{"control character", "<a href=\"java\x01script:alert(1)\">x</a>", "<a>x</a>"},
+		{"vbscript", `<a href="vbscript:msgbox(1)">x</a>`, "<a>x</a>"},
+		{"data html link", `<a href="data:text/html,<script>alert(1)</script>">x</a>`, "<a>x</a>"},
+		{"data html image", `<img src="data:text/html;base64,PHNjcmlwdD4=">`, "<img/>"},
+		{"data svg image", `<img src="data:image/svg+xml;base64,PHN2Zz4=">`, "<img/>"},
+		{"data png image", `<img src="data:image/png;base64,iVBORw0KGgo=">`, `<img src="data:image/png;base64,iVBORw0KGgo="/>`},
+		{"https link", `<a href="https://example.com/a?b=1">x</a>`, `<a href="https://example.com/a?b=1">x</a>`},
+		{"relative link", `<a href="/docs#x">x</a>`, `<a href="/docs#x">x</a>`},
+		{"mailto link", `<a href="mailto:a@example.com">x</a>`, `<a href="mailto:a@example.com">x</a>`},
+		{"css url", `<p style="background-image: url(javascript:alert(1)); color: red">x</p>`, `<p style="color: red">x</p>`},
+		{"css url on allowed property", `<p style="color: url(//evil.example/x)">x</p>`, "<p>x</p>"},
+		{"css expression", `<p style="color: expression(alert(1))">x</p>`, "<p>x</p>"},
+		{"css escape", `<p style="color: \65 xpression(alert(1))">x</p>`, "<p>x</p>"},
+		{"css comment", `<p style="color: red/**/; font-weight: bold">x</p>`, `<p style="font-weight: bold">x</p>`},
+		{"css rgb", `<span style="color: rgb(1, 2, 3)">x</span>`, `<span style="color: rgb(1, 2, 3)">x</span>`},
+		{"css behavior", `<p style="behavior: url(x.htc)">x</p>`, "<p>x</p>"},
+		{"target blank gets rel", `<a href="/x" target="_blank">x</a>`, `<a href="/x" target="_blank" rel="noopener noreferrer">x</a>`},
+		{"client rel replaced", `<a href="/x" target="_blank" rel="opener">x</a>`, `<a href="/x" target="_blank" rel="noopener noreferrer">x</a>`},
+		{"other target dropped", `<a href="/x" target="_top">x</a>`, `<a href="/x">x</a>`},
+		{"iframe", `<iframe src="https://example.com"></iframe>x`, "x"},
+		{"object", `<object data="x.swf"></object>x`, "x"},
+		{"form controls", `<form action="/x"><input value="a"><button>b</button>c</form>`, "c"},
+		{"base", `<base href="https://evil.example/">x`, "x"},
+		{"meta refresh", `<meta http-equiv="refresh" content="0;url=javascript:alert(1)">x`, "x"},
+		{"xmp breakout", `<xmp></xmp><img src=x onerror=alert(1)>`, `<img src="x"/>`},
+		{"title breakout", `<title></title><img src=x onerror=alert(1)>`, `<img src="x"/>`},
+		{"escaped text", "<p>&lt;script&gt;alert(1)&lt;/script&gt;</p>", "<p>&lt;script&gt;alert(1)&lt;/script&gt;</p>"},
+		{"class filtered", `<p class="lead x&quot;y">x</p>`, `<p class="lead">x</p>`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Sanitize(tt.in)
+			if got != tt.want {
+				t.Errorf("Sanitize(%q)\n got %q\nwant %q", tt.in, got, tt.want)
+			}
+			checkSafe(t, got)
+		})
+	}
+}
+
+func FuzzSanitize(f *testing.F) {
+	for _, seed := range []string{
+		"<p>வணக்கம்</p>",
+		`<svg><style><img src=x onerror=alert(1)>`,
+		`<math><mtext><table><mglyph><style><!--</style><img src=x onerror=alert(1)>-->`,
+		`<noscript><p title="</noscript><img src=x onerror=alert(1)>">`,
+		`<template><script>alert(1)</script></template>`,
+		"<a href=\"java\tscript:alert(1)\" target=_blank>x</a>",
+		`<a href="data:text/html,<script>alert(1)</script>">x</a>`,
+		`<img src="data:image/svg+xml;base64,PHN2Zz4=" ONERROR=alert(1)>`,
+		`<p style="color: red; background: url(x); width: expression(alert(1))">x</p>`,
+		`<table><custom><td>a</td></custom><tr><td>b</td></tr></table>`,
+		`<ul><li><unknown><p>a</p></unknown></li></ul>`,
+		`<a href="/a"><b><a href="/b">x</a></b></a>`,
+		`<p><custom><div>x</div></custom></p>`,
+	} {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, src string) {
+		got := Sanitize(src)
+		checkSafe(t, got)
+		if again := Sanitize(got); again != got {
+			t.Errorf("Sanitize is not idempotent for %q:\nonce  %q\ntwice %q", src, got, again)
+		}
+	})
+}
+
+// checkSafe parses sanitized output as a browser would and fails on any
+// script-capable element, event handler or disallowed URL.
+func checkSafe(t *testing.T, out string) {
+	t.Helper()
+	body := &html.Node{Type: html.ElementNode, Data: "body", DataAtom: atom.Body}
+	nodes, err := html.ParseFragment(strings.NewReader(out), body)
+	if err != nil {
+		t.Fatalf("parsing output %q: %v", out, err)
+	}
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode {
+			switch strings.ToLower(n.Data) {
+			case "script", "style", "svg", "math", "iframe", "object", "embed", "template", "noscript", "base", "meta", "link":
+				t.Errorf("output %q has a <%s> element", out, n.Data)
+			}
+			if n.Namespace != "" {
+				t.Errorf("output %q has foreign content <%s:%s>", out, n.Namespace, n.Data)
+			}
+			for _, a := range n.Attr {
+				key := strings.ToLower(a.Key)
+				if strings.HasPrefix(key, "on") {
+					t.Errorf("output %q has event handler %s", out, a.Key)
+				}
+				switch key {
+				case "href", "src", "cite":
+					v, _ := normalizeURL(a.Val)
+					s := scheme(v)
+					if !schemes[s] && !(s == "data" && n.DataAtom == atom.Img && dataImage.MatchString(v)) {
+						t.Errorf("output %q has %s with scheme %q", out, a.Key, s)
+					}
+				case "style":
+					if v := strings.ToLower(a.Val); strings.Contains(v, "url(") || strings.Contains(v, "expression") || strings.Contains(v, `\`) {
+						t.Errorf("output %q has unsafe style %q", out, a.Val)
+					}
+				}
+			}
+		}
+		if n.Type == html.CommentNode {
+			t.Errorf("output %q has a comment", out)
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	for _, n := range nodes {
+		walk(n)
+	}
+}